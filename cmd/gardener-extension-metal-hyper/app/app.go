@@ -18,6 +18,7 @@ func NewHyperCommand(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(
 		providermetal.NewControllerManagerCommand(ctx),
 		validatormetal.NewValidatorCommand(ctx),
+		NewPlanDeletionCommand(ctx),
 	)
 
 	return cmd
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	metalinstall "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/install"
+	metalv1alpha1 "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/v1alpha1"
+	metalinfrastructure "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/infrastructure"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// planDeletionOptions are the options of the command which prints the deletion plan of an infrastructure.
+type planDeletionOptions struct {
+	// Namespace is the namespace of the shoot in the seed.
+	Namespace string
+	// Name is the name of the infrastructure resource.
+	Name string
+}
+
+// AddFlags implements Flagger.AddFlags.
+func (o *planDeletionOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Namespace, "namespace", "", "The namespace of the shoot in the seed.")
+	fs.StringVar(&o.Name, "name", "", "The name of the infrastructure resource, defaults to the only infrastructure in the namespace.")
+}
+
+// Complete implements Completer.Complete.
+func (o *planDeletionOptions) Complete() error {
+	if o.Namespace == "" {
+		return fmt.Errorf("namespace must be set")
+	}
+	return nil
+}
+
+// NewPlanDeletionCommand creates a new command which prints the metal resources that are released when the
+// infrastructure of a shoot is deleted, without changing any of them.
+func NewPlanDeletionCommand(ctx context.Context) *cobra.Command {
+	var (
+		restOpts  = &controllercmd.RESTOptions{}
+		planOpts  = &planDeletionOptions{}
+		aggOption = controllercmd.NewOptionAggregator(restOpts, planOpts)
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan-infrastructure-deletion",
+		Short: "Prints the metal resources that are released when the infrastructure of a shoot is deleted.",
+
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := aggOption.Complete(); err != nil {
+				controllercmd.LogErrAndExit(err, "Error completing options")
+			}
+
+			scheme := runtime.NewScheme()
+			if err := corev1.AddToScheme(scheme); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not create scheme")
+			}
+			if err := extensionscontroller.AddToScheme(scheme); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not create scheme")
+			}
+			if err := metalinstall.AddToScheme(scheme); err != nil {
+				controllercmd.LogErrAndExit(err, "Could not create scheme")
+			}
+
+			c, err := client.New(restOpts.Completed().Config, client.Options{Scheme: scheme})
+			if err != nil {
+				controllercmd.LogErrAndExit(err, "Could not create client")
+			}
+
+			plan, err := planInfrastructureDeletion(ctx, c, planOpts.Namespace, planOpts.Name)
+			if err != nil {
+				controllercmd.LogErrAndExit(err, "Could not plan infrastructure deletion")
+			}
+
+			out, err := yaml.Marshal(plan)
+			if err != nil {
+				controllercmd.LogErrAndExit(err, "Could not marshal deletion plan")
+			}
+			fmt.Fprint(os.Stdout, string(out))
+		},
+	}
+
+	aggOption.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func planInfrastructureDeletion(ctx context.Context, c client.Client, namespace, name string) (*metalv1alpha1.DeletionPlan, error) {
+	infrastructure := &extensionsv1alpha1.Infrastructure{}
+	if name != "" {
+		if err := c.Get(ctx, kutil.Key(namespace, name), infrastructure); err != nil {
+			return nil, err
+		}
+	} else {
+		infrastructures := &extensionsv1alpha1.InfrastructureList{}
+		if err := c.List(ctx, infrastructures, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		if len(infrastructures.Items) != 1 {
+			return nil, fmt.Errorf("expected exactly one infrastructure in namespace %s, found %d", namespace, len(infrastructures.Items))
+		}
+		infrastructure = &infrastructures.Items[0]
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := metalinfrastructure.PlanDeletion(ctx, c, metalclient.DefaultClientFactory, infrastructure, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &metalv1alpha1.DeletionPlan{}
+	if err := metalv1alpha1.Convert_metal_DeletionPlan_To_v1alpha1_DeletionPlan(plan, out, nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -14,9 +14,12 @@ import (
 	metalinstall "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/install"
 	metalcmd "github.com/metal-stack/gardener-extension-provider-metal/pkg/cmd"
 	metalcontrolplane "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/controlplane"
+	metalhealthcheck "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/healthcheck"
 	metalinfrastructure "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/infrastructure"
+	metalorphan "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/orphan"
 	metalworker "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/worker"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/gardener/gardener-extensions/pkg/controller"
@@ -47,6 +50,11 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			MaxConcurrentReconciles: 5,
 		}
 
+		// options for the health check controllers
+		healthCheckCtrlOpts = &controllercmd.ControllerOptions{
+			MaxConcurrentReconciles: 5,
+		}
+
 		// options for the infrastructure controller
 		infraCtrlOpts = &controllercmd.ControllerOptions{
 			MaxConcurrentReconciles: 5,
@@ -74,6 +82,7 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 			mgrOpts,
 			controllercmd.PrefixOption("controlplane-", controlPlaneCtrlOpts),
 			controllercmd.PrefixOption("infrastructure-", infraCtrlOpts),
+			controllercmd.PrefixOption("healthcheck-", healthCheckCtrlOpts),
 			controllercmd.PrefixOption("worker-", &workerCtrlOptsUnprefixed),
 			controllercmd.PrefixOption("accounting-", &metalcontrolplane.AccOpts),
 			controllercmd.PrefixOption("auth-", &metalcontrolplane.AuthOpts),
@@ -163,13 +172,26 @@ func NewControllerManagerCommand(ctx context.Context) *cobra.Command {
 				controllercmd.LogErrAndExit(err, "Could not update manager scheme")
 			}
 
+			metalClientPoolOpts := metalclient.DefaultPoolOptions()
+			configFileOpts.Completed().ApplyMetalAPIClient(&metalClientPoolOpts)
+			metalClientPool := metalclient.NewPool(metalClientPoolOpts)
+			metalinfrastructure.DefaultAddOptions.MetalClientFactory = metalClientPool
+			metalcontrolplane.DefaultAddOptions.MetalClientFactory = metalClientPool
+			metalworker.DefaultAddOptions.MetalClientFactory = metalClientPool
+			metalorphan.DefaultAddOptions.MetalClientFactory = metalClientPool
+			metalhealthcheck.DefaultAddOptions.MetalClientFactory = metalClientPool
+
 			configFileOpts.Completed().ApplyMachineImages(&metalworker.DefaultAddOptions.MachineImages)
+			configFileOpts.Completed().ApplyOrphanCollection(&metalorphan.DefaultAddOptions.OrphanCollection)
+			configFileOpts.Completed().ApplyFirewallProvisioningTimeout(&metalinfrastructure.DefaultAddOptions.FirewallProvisioningTimeout)
+			configFileOpts.Completed().ApplyHealthCheckConfig(&metalhealthcheck.DefaultAddOptions.HealthCheckConfig)
 			// configFileOpts.Completed().ApplyETCDStorage(&metalcontrolplaneexposure.DefaultAddOptions.ETCDStorage)
 			// configFileOpts.Completed().ApplyETCDBackup(&metalcontrolplanebackup.DefaultAddOptions.ETCDBackup)
 			controlPlaneCtrlOpts.Completed().Apply(&metalcontrolplane.DefaultAddOptions.Controller)
 			metalcontrolplane.AccOpts.Completed().Apply(&metalcontrolplane.AccOpts)
 			metalcontrolplane.AuthOpts.Completed().Apply(&metalcontrolplane.AuthOpts)
 			infraCtrlOpts.Completed().Apply(&metalinfrastructure.DefaultAddOptions.Controller)
+			healthCheckCtrlOpts.Completed().Apply(&metalhealthcheck.DefaultAddOptions.Controller)
 			reconcileOpts.Completed().Apply(&metalinfrastructure.DefaultAddOptions.IgnoreOperationAnnotation)
 			reconcileOpts.Completed().Apply(&metalcontrolplane.DefaultAddOptions.IgnoreOperationAnnotation)
 			reconcileOpts.Completed().Apply(&metalworker.DefaultAddOptions.IgnoreOperationAnnotation)
@@ -20,6 +20,8 @@ require (
 	github.com/gardener/machine-controller-manager v0.26.2
 	github.com/go-ini/ini v1.46.0 // indirect
 	github.com/go-logr/logr v0.1.0
+	github.com/go-openapi/runtime v0.19.11
+	github.com/go-openapi/strfmt v0.19.4
 	github.com/gobuffalo/packr/v2 v2.7.1
 	github.com/golang/mock v1.4.1
 	github.com/google/go-cmp v0.4.0
@@ -35,6 +37,7 @@ require (
 	github.com/onsi/gomega v1.7.0
 	github.com/pkg/errors v0.9.1
 	github.com/pkg/profile v1.2.1 // indirect
+	github.com/prometheus/client_golang v1.1.0
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.5
 	github.com/ugorji/go v1.1.7 // indirect
@@ -46,8 +49,10 @@ require (
 	k8s.io/apiserver v0.17.0
 	k8s.io/client-go v11.0.1-0.20190409021438-1a26190bd76a+incompatible
 	k8s.io/component-base v0.17.0
+	k8s.io/helm v2.14.2+incompatible
 	k8s.io/kubelet v0.16.6
 	sigs.k8s.io/controller-runtime v0.4.0
+	sigs.k8s.io/yaml v1.1.0
 )
 
 replace (
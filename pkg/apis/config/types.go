@@ -15,6 +15,7 @@
 package config
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,6 +32,21 @@ type ControllerConfiguration struct {
 
 	// ETCD is the etcd configuration.
 	ETCD ETCD
+
+	// OrphanCollection is the configuration of the collector for orphaned metal resources, the collector is not
+	// started if it is not set.
+	OrphanCollection *OrphanCollection
+
+	// MetalAPIClient is the configuration of the clients used to access the metal-api.
+	MetalAPIClient *MetalAPIClient
+
+	// FirewallProvisioningTimeout is the duration after which a firewall that has not finished provisioning is
+	// deleted and allocated again.
+	FirewallProvisioningTimeout *metav1.Duration
+
+	// HealthCheckConfig is the configuration of the health checks of the infrastructure, control plane and worker
+	// resources.
+	HealthCheckConfig *HealthCheckConfig
 }
 
 // MachineImage is a mapping from logical names and versions to GCP-specific identifiers.
@@ -64,3 +80,36 @@ type ETCDBackup struct {
 	// Schedule is the etcd backup schedule.
 	Schedule *string
 }
+
+// OrphanCollection is the configuration of the collector for orphaned metal resources. Metal resources are orphaned
+// if they are marked as managed by the seed and carry the id of a cluster that is not present on the seed anymore.
+type OrphanCollection struct {
+	// SeedName is the name of the seed the extension runs on. Only resources marked as managed by this seed are
+	// considered orphaned, resources of other seeds sharing the metal-api credentials are left alone.
+	SeedName string
+	// SecretRef references the secret with the metal-api credentials that are used to look up orphaned resources.
+	SecretRef corev1.SecretReference
+	// Interval is the interval in which the metal-api is checked for orphaned resources.
+	Interval *metav1.Duration
+	// Release specifies whether orphaned resources are released after the grace period.
+	Release bool
+	// GracePeriod is the duration a resource has to be orphaned before it is released.
+	GracePeriod *metav1.Duration
+}
+
+// MetalAPIClient is the configuration of the clients used to access the metal-api. The controllers share one client
+// per credentials secret.
+type MetalAPIClient struct {
+	// QPS is the number of requests per second all controllers together send to the metal-api with the same credentials.
+	QPS *float32
+	// Burst is the number of requests which may be sent at once above the QPS.
+	Burst *int
+	// CacheTTL is the duration networks and projects read from the metal-api are cached.
+	CacheTTL *metav1.Duration
+}
+
+// HealthCheckConfig is the configuration of the health checks.
+type HealthCheckConfig struct {
+	// SyncPeriod is the period in which the health checks are executed.
+	SyncPeriod metav1.Duration
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -16,6 +17,25 @@ type ControllerConfiguration struct {
 	MachineImages []MachineImage `json:"machineImages,omitempty"`
 	// ETCD is the etcd configuration.
 	ETCD ETCD `json:"etcd"`
+
+	// OrphanCollection is the configuration of the collector for orphaned metal resources, the collector is not
+	// started if it is not set.
+	// +optional
+	OrphanCollection *OrphanCollection `json:"orphanCollection,omitempty"`
+
+	// MetalAPIClient is the configuration of the clients used to access the metal-api.
+	// +optional
+	MetalAPIClient *MetalAPIClient `json:"metalAPIClient,omitempty"`
+
+	// FirewallProvisioningTimeout is the duration after which a firewall that has not finished provisioning is
+	// deleted and allocated again.
+	// +optional
+	FirewallProvisioningTimeout *metav1.Duration `json:"firewallProvisioningTimeout,omitempty"`
+
+	// HealthCheckConfig is the configuration of the health checks of the infrastructure, control plane and worker
+	// resources.
+	// +optional
+	HealthCheckConfig *HealthCheckConfig `json:"healthCheckConfig,omitempty"`
 }
 
 // MachineImage is a mapping from logical names and versions to GCP-specific identifiers.
@@ -52,3 +72,42 @@ type ETCDBackup struct {
 	// +optional
 	Schedule *string `json:"schedule,omitempty"`
 }
+
+// OrphanCollection is the configuration of the collector for orphaned metal resources. Metal resources are orphaned
+// if they are marked as managed by the seed and carry the id of a cluster that is not present on the seed anymore.
+type OrphanCollection struct {
+	// SeedName is the name of the seed the extension runs on. Only resources marked as managed by this seed are
+	// considered orphaned, resources of other seeds sharing the metal-api credentials are left alone.
+	SeedName string `json:"seedName"`
+	// SecretRef references the secret with the metal-api credentials that are used to look up orphaned resources.
+	SecretRef corev1.SecretReference `json:"secretRef"`
+	// Interval is the interval in which the metal-api is checked for orphaned resources.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Release specifies whether orphaned resources are released after the grace period.
+	// +optional
+	Release bool `json:"release,omitempty"`
+	// GracePeriod is the duration a resource has to be orphaned before it is released.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// MetalAPIClient is the configuration of the clients used to access the metal-api. The controllers share one client
+// per credentials secret.
+type MetalAPIClient struct {
+	// QPS is the number of requests per second all controllers together send to the metal-api with the same credentials.
+	// +optional
+	QPS *float32 `json:"qps,omitempty"`
+	// Burst is the number of requests which may be sent at once above the QPS.
+	// +optional
+	Burst *int `json:"burst,omitempty"`
+	// CacheTTL is the duration networks and projects read from the metal-api are cached.
+	// +optional
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+}
+
+// HealthCheckConfig is the configuration of the health checks.
+type HealthCheckConfig struct {
+	// SyncPeriod is the period in which the health checks are executed.
+	SyncPeriod metav1.Duration `json:"syncPeriod"`
+}
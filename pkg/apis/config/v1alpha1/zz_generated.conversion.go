@@ -25,6 +25,7 @@ import (
 
 	config "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -76,6 +77,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HealthCheckConfig)(nil), (*config.HealthCheckConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_HealthCheckConfig_To_config_HealthCheckConfig(a.(*HealthCheckConfig), b.(*config.HealthCheckConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.HealthCheckConfig)(nil), (*HealthCheckConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_HealthCheckConfig_To_v1alpha1_HealthCheckConfig(a.(*config.HealthCheckConfig), b.(*HealthCheckConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MachineImage)(nil), (*config.MachineImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_MachineImage_To_config_MachineImage(a.(*MachineImage), b.(*config.MachineImage), scope)
 	}); err != nil {
@@ -86,6 +97,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MetalAPIClient)(nil), (*config.MetalAPIClient)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MetalAPIClient_To_config_MetalAPIClient(a.(*MetalAPIClient), b.(*config.MetalAPIClient), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MetalAPIClient)(nil), (*MetalAPIClient)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MetalAPIClient_To_v1alpha1_MetalAPIClient(a.(*config.MetalAPIClient), b.(*MetalAPIClient), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OrphanCollection)(nil), (*config.OrphanCollection)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_OrphanCollection_To_config_OrphanCollection(a.(*OrphanCollection), b.(*config.OrphanCollection), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.OrphanCollection)(nil), (*OrphanCollection)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_OrphanCollection_To_v1alpha1_OrphanCollection(a.(*config.OrphanCollection), b.(*OrphanCollection), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -94,6 +125,10 @@ func autoConvert_v1alpha1_ControllerConfiguration_To_config_ControllerConfigurat
 	if err := Convert_v1alpha1_ETCD_To_config_ETCD(&in.ETCD, &out.ETCD, s); err != nil {
 		return err
 	}
+	out.OrphanCollection = (*config.OrphanCollection)(unsafe.Pointer(in.OrphanCollection))
+	out.MetalAPIClient = (*config.MetalAPIClient)(unsafe.Pointer(in.MetalAPIClient))
+	out.FirewallProvisioningTimeout = (*v1.Duration)(unsafe.Pointer(in.FirewallProvisioningTimeout))
+	out.HealthCheckConfig = (*config.HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
 	return nil
 }
 
@@ -107,6 +142,10 @@ func autoConvert_config_ControllerConfiguration_To_v1alpha1_ControllerConfigurat
 	if err := Convert_config_ETCD_To_v1alpha1_ETCD(&in.ETCD, &out.ETCD, s); err != nil {
 		return err
 	}
+	out.OrphanCollection = (*OrphanCollection)(unsafe.Pointer(in.OrphanCollection))
+	out.MetalAPIClient = (*MetalAPIClient)(unsafe.Pointer(in.MetalAPIClient))
+	out.FirewallProvisioningTimeout = (*v1.Duration)(unsafe.Pointer(in.FirewallProvisioningTimeout))
+	out.HealthCheckConfig = (*HealthCheckConfig)(unsafe.Pointer(in.HealthCheckConfig))
 	return nil
 }
 
@@ -187,6 +226,26 @@ func Convert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in *config.ETCDStorage,
 	return autoConvert_config_ETCDStorage_To_v1alpha1_ETCDStorage(in, out, s)
 }
 
+func autoConvert_v1alpha1_HealthCheckConfig_To_config_HealthCheckConfig(in *HealthCheckConfig, out *config.HealthCheckConfig, s conversion.Scope) error {
+	out.SyncPeriod = in.SyncPeriod
+	return nil
+}
+
+// Convert_v1alpha1_HealthCheckConfig_To_config_HealthCheckConfig is an autogenerated conversion function.
+func Convert_v1alpha1_HealthCheckConfig_To_config_HealthCheckConfig(in *HealthCheckConfig, out *config.HealthCheckConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_HealthCheckConfig_To_config_HealthCheckConfig(in, out, s)
+}
+
+func autoConvert_config_HealthCheckConfig_To_v1alpha1_HealthCheckConfig(in *config.HealthCheckConfig, out *HealthCheckConfig, s conversion.Scope) error {
+	out.SyncPeriod = in.SyncPeriod
+	return nil
+}
+
+// Convert_config_HealthCheckConfig_To_v1alpha1_HealthCheckConfig is an autogenerated conversion function.
+func Convert_config_HealthCheckConfig_To_v1alpha1_HealthCheckConfig(in *config.HealthCheckConfig, out *HealthCheckConfig, s conversion.Scope) error {
+	return autoConvert_config_HealthCheckConfig_To_v1alpha1_HealthCheckConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_MachineImage_To_config_MachineImage(in *MachineImage, out *config.MachineImage, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Version = in.Version
@@ -210,3 +269,55 @@ func autoConvert_config_MachineImage_To_v1alpha1_MachineImage(in *config.Machine
 func Convert_config_MachineImage_To_v1alpha1_MachineImage(in *config.MachineImage, out *MachineImage, s conversion.Scope) error {
 	return autoConvert_config_MachineImage_To_v1alpha1_MachineImage(in, out, s)
 }
+
+func autoConvert_v1alpha1_MetalAPIClient_To_config_MetalAPIClient(in *MetalAPIClient, out *config.MetalAPIClient, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int)(unsafe.Pointer(in.Burst))
+	out.CacheTTL = (*v1.Duration)(unsafe.Pointer(in.CacheTTL))
+	return nil
+}
+
+// Convert_v1alpha1_MetalAPIClient_To_config_MetalAPIClient is an autogenerated conversion function.
+func Convert_v1alpha1_MetalAPIClient_To_config_MetalAPIClient(in *MetalAPIClient, out *config.MetalAPIClient, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MetalAPIClient_To_config_MetalAPIClient(in, out, s)
+}
+
+func autoConvert_config_MetalAPIClient_To_v1alpha1_MetalAPIClient(in *config.MetalAPIClient, out *MetalAPIClient, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int)(unsafe.Pointer(in.Burst))
+	out.CacheTTL = (*v1.Duration)(unsafe.Pointer(in.CacheTTL))
+	return nil
+}
+
+// Convert_config_MetalAPIClient_To_v1alpha1_MetalAPIClient is an autogenerated conversion function.
+func Convert_config_MetalAPIClient_To_v1alpha1_MetalAPIClient(in *config.MetalAPIClient, out *MetalAPIClient, s conversion.Scope) error {
+	return autoConvert_config_MetalAPIClient_To_v1alpha1_MetalAPIClient(in, out, s)
+}
+
+func autoConvert_v1alpha1_OrphanCollection_To_config_OrphanCollection(in *OrphanCollection, out *config.OrphanCollection, s conversion.Scope) error {
+	out.SeedName = in.SeedName
+	out.SecretRef = in.SecretRef
+	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.Release = in.Release
+	out.GracePeriod = (*v1.Duration)(unsafe.Pointer(in.GracePeriod))
+	return nil
+}
+
+// Convert_v1alpha1_OrphanCollection_To_config_OrphanCollection is an autogenerated conversion function.
+func Convert_v1alpha1_OrphanCollection_To_config_OrphanCollection(in *OrphanCollection, out *config.OrphanCollection, s conversion.Scope) error {
+	return autoConvert_v1alpha1_OrphanCollection_To_config_OrphanCollection(in, out, s)
+}
+
+func autoConvert_config_OrphanCollection_To_v1alpha1_OrphanCollection(in *config.OrphanCollection, out *OrphanCollection, s conversion.Scope) error {
+	out.SeedName = in.SeedName
+	out.SecretRef = in.SecretRef
+	out.Interval = (*v1.Duration)(unsafe.Pointer(in.Interval))
+	out.Release = in.Release
+	out.GracePeriod = (*v1.Duration)(unsafe.Pointer(in.GracePeriod))
+	return nil
+}
+
+// Convert_config_OrphanCollection_To_v1alpha1_OrphanCollection is an autogenerated conversion function.
+func Convert_config_OrphanCollection_To_v1alpha1_OrphanCollection(in *config.OrphanCollection, out *OrphanCollection, s conversion.Scope) error {
+	return autoConvert_config_OrphanCollection_To_v1alpha1_OrphanCollection(in, out, s)
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package config
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -34,6 +35,26 @@ func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
 		copy(*out, *in)
 	}
 	in.ETCD.DeepCopyInto(&out.ETCD)
+	if in.OrphanCollection != nil {
+		in, out := &in.OrphanCollection, &out.OrphanCollection
+		*out = new(OrphanCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetalAPIClient != nil {
+		in, out := &in.MetalAPIClient, &out.MetalAPIClient
+		*out = new(MetalAPIClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FirewallProvisioningTimeout != nil {
+		in, out := &in.FirewallProvisioningTimeout, &out.FirewallProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckConfig != nil {
+		in, out := &in.HealthCheckConfig, &out.HealthCheckConfig
+		*out = new(HealthCheckConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -120,6 +141,23 @@ func (in *ETCDStorage) DeepCopy() *ETCDStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckConfig) DeepCopyInto(out *HealthCheckConfig) {
+	*out = *in
+	out.SyncPeriod = in.SyncPeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckConfig.
+func (in *HealthCheckConfig) DeepCopy() *HealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineImage) DeepCopyInto(out *MachineImage) {
 	*out = *in
@@ -135,3 +173,61 @@ func (in *MachineImage) DeepCopy() *MachineImage {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetalAPIClient) DeepCopyInto(out *MetalAPIClient) {
+	*out = *in
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(float32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int)
+		**out = **in
+	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalAPIClient.
+func (in *MetalAPIClient) DeepCopy() *MetalAPIClient {
+	if in == nil {
+		return nil
+	}
+	out := new(MetalAPIClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanCollection) DeepCopyInto(out *OrphanCollection) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanCollection.
+func (in *OrphanCollection) DeepCopy() *OrphanCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanCollection)
+	in.DeepCopyInto(out)
+	return out
+}
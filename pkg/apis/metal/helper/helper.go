@@ -33,6 +33,17 @@ func FindMachineImage(machineImages []metal.MachineImage, name, version string)
 	return nil, fmt.Errorf("no machine image with name %q, version %q found", name, version)
 }
 
+// FindImageOverride returns the metal image of the given machine image version from the given image overrides of a
+// worker pool. The returned bool is false if the version is not overridden.
+func FindImageOverride(overrides []metal.ImageOverride, name, version string) (string, bool) {
+	for _, override := range overrides {
+		if override.Name == name && override.Version == version {
+			return override.Image, true
+		}
+	}
+	return "", false
+}
+
 // MergeIAMConfig merges the one iam config into the other
 func MergeIAMConfig(into *metal.IAMConfig, from *metal.IAMConfig) (*metal.IAMConfig, error) {
 	if into == nil && from == nil {
@@ -60,3 +71,9 @@ func MergeIAMConfig(into *metal.IAMConfig, from *metal.IAMConfig) (*metal.IAMCon
 	}
 	return &merged, nil
 }
+
+// PartitionIDs returns all partitions of the cluster, starting with the partition of the infrastructure config followed
+// by the additional partitions.
+func PartitionIDs(infrastructureConfig *metal.InfrastructureConfig) []string {
+	return append([]string{infrastructureConfig.PartitionID}, infrastructureConfig.AdditionalPartitionIDs...)
+}
@@ -12,12 +12,110 @@ type InfrastructureConfig struct {
 	Firewall    Firewall
 	PartitionID string
 	ProjectID   string
+	// NodeNetworkID is the id of an existing private network of the project which is used as node network.
+	// The network is not released on deletion of the cluster.
+	NodeNetworkID *string
+	// NodeNetwork contains the configuration of the node network allocated for the cluster.
+	NodeNetwork *NodeNetwork
+	// AdditionalPartitionIDs are partitions of the region the cluster is spread to in addition to PartitionID. Every
+	// partition gets its own node network and firewalls, worker pools are spread over them with their zones.
+	// Shoots with additional partitions are rejected as long as their node networks are allocated outside of the nodes
+	// cidr of the shoot and the cloud-controller-manager only knows the node network of PartitionID.
+	AdditionalPartitionIDs []string
+}
+
+// NodeNetwork contains the configuration of the node network allocated for the cluster.
+type NodeNetwork struct {
+	// PrefixLength is the expected prefix length of the node network. It does not set the length of the allocated
+	// network: the metal-api allocates node networks with the private network prefix length of the partition, the
+	// reconciliation fails if this length differs from it.
+	PrefixLength *int32
+	// AdditionalPrefix is a prefix which is added to the node network in addition to the allocated one.
+	AdditionalPrefix *string
+	// DualStack requests a node network with an IPv4 and an IPv6 prefix.
+	DualStack bool
 }
 
 type Firewall struct {
 	Size     string
 	Image    string
 	Networks []string
+	// Replicas is the amount of firewall machines that are deployed for the cluster, defaults to one.
+	Replicas *int32
+	// Rules contains rules which are applied on the firewall.
+	Rules *FirewallRules
+	// EgressIPs are static egress IPs of firewall networks which are kept when the firewall is replaced. As they can
+	// only be attached to one firewall at a time, a firewall with egress IPs is deleted before its replacement is
+	// created and the cluster has no egress in between.
+	EgressIPs []FirewallEgressIPs
+	// IgnitionSnippet is a container linux config snippet which is merged into the user data of new firewalls after
+	// the snippets of the cloud profile. It replaces files and units of the cloud profile snippets with the same name.
+	// Firewalls are replaced one after another if the snippet changes.
+	IgnitionSnippet *string
+}
+
+// FirewallEgressIPs contains the static egress IPs of a firewall network.
+type FirewallEgressIPs struct {
+	// NetworkID is the id of the firewall network.
+	NetworkID string
+	// IPs are static IPs of the project in the network, they are never released by the extension. If no IPs are
+	// given, a static IP is allocated once and released when the shoot is deleted.
+	IPs []string
+}
+
+// FirewallRules contains declarative rules for the traffic passing the firewalls.
+type FirewallRules struct {
+	// Egress is an allow-list of egress traffic leaving the cluster. If set, all other egress traffic is dropped. The
+	// rules are applied to the running firewalls by the firewall-policy-controller.
+	Egress []EgressRule
+	// Ingress is an allow-list of traffic entering the cluster from the firewall networks. If there are rules for a
+	// network, all other traffic entering from it is dropped except the replies to connections of the cluster. The rules
+	// only restrict the traffic the firewalls allow otherwise, e.g. for services of type LoadBalancer. They are part of
+	// the user data of the firewalls, the firewalls are replaced one after another if they change.
+	Ingress []IngressRule
+	// RateLimits contains bandwidth limits of networks attached to the firewall. They are part of the user data of the
+	// firewalls, the firewalls are replaced one after another if they change.
+	RateLimits []RateLimit
+}
+
+// FirewallProtocol is a transport protocol of a firewall rule.
+type FirewallProtocol string
+
+const (
+	// FirewallProtocolTCP is the protocol for tcp traffic.
+	FirewallProtocolTCP FirewallProtocol = "TCP"
+	// FirewallProtocolUDP is the protocol for udp traffic.
+	FirewallProtocolUDP FirewallProtocol = "UDP"
+)
+
+// EgressRule allows egress traffic to the given destinations.
+type EgressRule struct {
+	// Protocol is the transport protocol of the traffic.
+	Protocol FirewallProtocol
+	// CIDRs are the destination networks of the traffic.
+	CIDRs []string
+	// Ports are the destination ports of the traffic, all ports are allowed if empty.
+	Ports []int32
+}
+
+// IngressRule allows ingress traffic from an external network.
+type IngressRule struct {
+	// NetworkID is the external network the traffic enters from, it must be one of the firewall networks.
+	NetworkID string
+	// Protocol is the transport protocol of the traffic.
+	Protocol FirewallProtocol
+	// CIDRs are the source networks of the traffic, all sources are allowed if empty.
+	CIDRs []string
+	// Ports are the destination ports of the traffic, all ports are allowed if empty.
+	Ports []int32
+}
+
+// RateLimit limits the bandwidth of a network attached to the firewall.
+type RateLimit struct {
+	// NetworkID is the network to limit, it must be one of the firewall networks.
+	NetworkID string
+	// RateLimit is the bandwidth limit in Mbit/s.
+	RateLimit uint32
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -25,10 +123,154 @@ type Firewall struct {
 // InfrastructureStatus contains information about created infrastructure resources.
 type InfrastructureStatus struct {
 	metav1.TypeMeta
-	Firewall FirewallStatus
+	// Firewalls contains the status of every firewall machine of the cluster.
+	Firewalls []FirewallStatus
+	// Firewall is the status of the single firewall of the cluster.
+	// Deprecated: only read for migrating existing resources to Firewalls.
+	Firewall *FirewallStatus
+	// Rollout contains the state of an ongoing firewall replacement.
+	Rollout *FirewallRollout
+	// NodeNetworkPrefixes contains all prefixes of the node network.
+	NodeNetworkPrefixes []string
+	// AdditionalNodeNetworks contains the node networks of the additional partitions of the cluster.
+	AdditionalNodeNetworks []PartitionNodeNetwork
+	// DeletionPlan contains the metal resources that are released when the infrastructure is deleted. It is only
+	// computed on request.
+	DeletionPlan *DeletionPlan
+	// EgressIPs are the static egress IPs attached to the firewalls, including the allocated ones.
+	EgressIPs []FirewallEgressIPs
+	// FirewallCredentials contains the status of the credentials the firewalls use to access the shoot.
+	FirewallCredentials *FirewallCredentialsStatus
+	// FirewallProvisioning contains the state of the firewall allocations while not all firewalls are provisioned.
+	FirewallProvisioning *FirewallProvisioningStatus
+	// Deletion contains the progress of the release of the metal resources of the cluster while the infrastructure is
+	// deleted.
+	Deletion *DeletionStatus
+}
+
+// PartitionNodeNetwork is the node network of the cluster in a partition.
+type PartitionNodeNetwork struct {
+	// PartitionID is the partition of the node network.
+	PartitionID string
+	// NetworkID is the id of the node network.
+	NetworkID string
+	// Prefixes contains all prefixes of the node network.
+	Prefixes []string
+}
+
+// FirewallProvisioningStatus contains the state of the firewall allocations of the cluster. It is removed once all
+// firewalls of the cluster are provisioned.
+type FirewallProvisioningStatus struct {
+	// Attempts is the number of firewall allocations since all firewalls of the cluster were provisioned.
+	Attempts int32
+	// LastError is the reason the last firewall allocation failed.
+	LastError string
+	// LastErrorTime is the time the last firewall allocation failed.
+	LastErrorTime *metav1.Time
 }
 
+// FirewallCredentialsStatus contains the status of the kubeconfig the firewall-policy-controller uses to access the
+// shoot.
+type FirewallCredentialsStatus struct {
+	// ExpirationTime is the time the client certificate of the firewall-policy-controller expires.
+	ExpirationTime metav1.Time
+	// LastSyncTime is the last time the kubeconfig was written to the shoot.
+	LastSyncTime *metav1.Time
+}
+
+// FirewallStatus contains the status of a firewall of the cluster.
 type FirewallStatus struct {
 	Succeeded bool
 	MachineID string
+	// Phase is the phase of the firewall derived from its allocation and liveliness.
+	Phase FirewallPhase
+	// Liveliness is the liveliness of the firewall machine as reported by the metal-api.
+	Liveliness string
+	// Hostname is the hostname of the firewall.
+	Hostname string
+	// Size is the size of the firewall machine.
+	Size string
+	// Image is the image the firewall was allocated with.
+	Image string
+	// Partition is the partition the firewall is located in.
+	Partition string
+	// AllocationTimestamp is the point in time when the firewall was allocated.
+	AllocationTimestamp *metav1.Time
+	// Networks contains the networks of the firewall and the IPs it acquired in them.
+	Networks []FirewallNetworkStatus
+}
+
+// FirewallPhase is the phase of a firewall.
+type FirewallPhase string
+
+const (
+	// FirewallPhaseProvisioning means that the allocation of the firewall has not yet succeeded.
+	FirewallPhaseProvisioning FirewallPhase = "Provisioning"
+	// FirewallPhaseRunning means that the firewall is allocated and alive.
+	FirewallPhaseRunning FirewallPhase = "Running"
+	// FirewallPhaseUnhealthy means that the firewall is allocated but the metal-api does not report it as alive.
+	FirewallPhaseUnhealthy FirewallPhase = "Unhealthy"
+)
+
+// FirewallNetworkStatus contains the status of a network of a firewall.
+type FirewallNetworkStatus struct {
+	// NetworkID is the id of the network.
+	NetworkID string
+	// IPs are the IPs of the firewall in this network.
+	IPs []string
+}
+
+// FirewallRolloutPhase is the phase of a firewall replacement.
+type FirewallRolloutPhase string
+
+const (
+	// FirewallRolloutPhaseProvisioning means that the new firewall was created and the rollout waits for its allocation to succeed.
+	FirewallRolloutPhaseProvisioning FirewallRolloutPhase = "Provisioning"
+	// FirewallRolloutPhaseDeleting means that the new firewall is allocated and the old firewall is being deleted.
+	FirewallRolloutPhaseDeleting FirewallRolloutPhase = "Deleting"
+)
+
+// FirewallRollout describes the replacement of an outdated firewall by a new one.
+type FirewallRollout struct {
+	// Phase is the current phase of the rollout.
+	Phase FirewallRolloutPhase
+	// OldMachineID is the machine id of the firewall that gets replaced.
+	OldMachineID string
+	// NewMachineID is the machine id of the firewall that replaces the old one.
+	NewMachineID string
+}
+
+// DeletionPlan contains the metal resources that are released when the infrastructure is deleted.
+type DeletionPlan struct {
+	// Timestamp is the point in time when the plan was computed.
+	Timestamp metav1.Time
+	// Firewalls are the machine ids of the firewalls that are deleted.
+	Firewalls []string
+	// IPsToFree are the ephemeral IPs of the cluster that are released.
+	IPsToFree []string
+	// IPsToUpdate are the ephemeral IPs that are also used by other clusters, only the tags of this cluster are removed.
+	IPsToUpdate []string
+	// Networks are the ids of the private networks that are released.
+	Networks []string
+}
+
+// DeletionStatus contains the progress of the release of the metal resources of the cluster. Released resources are
+// skipped when the deletion is repeated.
+type DeletionStatus struct {
+	// ReleasedIPs are the ephemeral IPs which were released or from which the tags of the cluster were removed.
+	ReleasedIPs []string
+	// ReleasedNetworks are the ids of the networks which were released.
+	ReleasedNetworks []string
+	// Failures are the resources whose release failed on the last attempt.
+	Failures []DeletionFailure
+}
+
+// DeletionFailure is a metal resource whose release failed.
+type DeletionFailure struct {
+	// Kind is the kind of the resource, either ip or network.
+	Kind string
+	// ID is the id of the resource, the address in case of an ip.
+	ID string
+	// Error is the reason the release failed.
+	Error string
 }
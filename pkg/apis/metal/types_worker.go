@@ -27,3 +27,32 @@ type MachineImage struct {
 	// Image is the path to the image.
 	Image string
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkerConfig contains the metal specific configuration of a worker pool. Additional networks of the machines are not
+// supported until the MetalMachineClass of the machine-controller-manager fork in use allows to configure networks.
+type WorkerConfig struct {
+	metav1.TypeMeta
+
+	// ExtraTags are additional tags of the machines of the worker pool in the form key=value.
+	ExtraTags []string
+	// PartitionID is the partition the machines of a worker pool without zones are placed into instead of the
+	// partition of the infrastructure.
+	PartitionID *string
+	// ImageOverrides replace the metal images of machine image versions from the controller configuration.
+	ImageOverrides []ImageOverride
+	// UserDataSnippets are appended to the user data of the machines, they must be in the format of the user data
+	// generated for the operating system of the worker pool.
+	UserDataSnippets []string
+}
+
+// ImageOverride is a metal image used for a machine image version instead of the one from the controller configuration.
+type ImageOverride struct {
+	// Name is the logical name of the machine image.
+	Name string
+	// Version is the logical version of the machine image.
+	Version string
+	// Image is the id of the metal image.
+	Image string
+}
@@ -7,3 +7,11 @@ import (
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
 	return RegisterDefaults(scheme)
 }
+
+// SetDefaults_InfrastructureConfig sets the defaults of the infrastructure config.
+func SetDefaults_InfrastructureConfig(obj *InfrastructureConfig) {
+	if obj.Firewall.Replicas == nil {
+		replicas := int32(1)
+		obj.Firewall.Replicas = &replicas
+	}
+}
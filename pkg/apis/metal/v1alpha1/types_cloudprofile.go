@@ -16,6 +16,35 @@ type CloudProfileConfig struct {
 	FirewallNetworks map[string]map[string]string `json:"firewallNetworks,omitempty"`
 	// IAMConfig contains the config for all AuthN/AuthZ related components, can be overriden in shoots control plane config
 	IAMConfig *IAMConfig `json:"iamconfig" optional:"true"`
+	// IPv6Partitions is a list of partitions which offer dual-stack node networks.
+	// +optional
+	IPv6Partitions []string `json:"ipv6Partitions,omitempty"`
+	// FirewallIgnitionSnippets references ConfigMaps with ignition snippets which are merged into the user data of
+	// firewalls.
+	// +optional
+	FirewallIgnitionSnippets []FirewallIgnitionSnippets `json:"firewallIgnitionSnippets,omitempty"`
+}
+
+// FirewallIgnitionSnippets references a ConfigMap on the seed with ignition snippets which are merged into the user
+// data of firewalls. Every value of the ConfigMap is a container linux config snippet, they are merged in the order
+// of their keys. Firewalls are replaced one after another if their snippets change.
+type FirewallIgnitionSnippets struct {
+	// ConfigMapRef references the ConfigMap containing the snippets.
+	ConfigMapRef ConfigMapReference `json:"configMapRef"`
+	// Partitions restricts the snippets to firewalls in the given partitions, they apply to all partitions if empty.
+	// +optional
+	Partitions []string `json:"partitions,omitempty"`
+	// Images restricts the snippets to firewalls with the given images, they apply to all images if empty.
+	// +optional
+	Images []string `json:"images,omitempty"`
+}
+
+// ConfigMapReference references a ConfigMap in a namespace.
+type ConfigMapReference struct {
+	// Name is the name of the ConfigMap.
+	Name string `json:"name"`
+	// Namespace is the namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
 }
 
 // IAMConfig contains the config for all AuthN/AuthZ related components
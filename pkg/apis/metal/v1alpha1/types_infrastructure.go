@@ -12,12 +12,127 @@ type InfrastructureConfig struct {
 	Firewall        Firewall `json:"firewall"`
 	PartitionID     string   `json:"partitionID"`
 	ProjectID       string   `json:"projectID"`
+	// NodeNetworkID is the id of an existing private network of the project which is used as node network.
+	// The network is not released on deletion of the cluster.
+	// +optional
+	NodeNetworkID *string `json:"nodeNetworkID,omitempty"`
+	// NodeNetwork contains the configuration of the node network allocated for the cluster.
+	// +optional
+	NodeNetwork *NodeNetwork `json:"nodeNetwork,omitempty"`
+	// AdditionalPartitionIDs are partitions of the region the cluster is spread to in addition to PartitionID. Every
+	// partition gets its own node network and firewalls, worker pools are spread over them with their zones.
+	// Shoots with additional partitions are rejected as long as their node networks are allocated outside of the nodes
+	// cidr of the shoot and the cloud-controller-manager only knows the node network of PartitionID.
+	// +optional
+	AdditionalPartitionIDs []string `json:"additionalPartitionIDs,omitempty"`
+}
+
+// NodeNetwork contains the configuration of the node network allocated for the cluster.
+type NodeNetwork struct {
+	// PrefixLength is the expected prefix length of the node network. It does not set the length of the allocated
+	// network: the metal-api allocates node networks with the private network prefix length of the partition, the
+	// reconciliation fails if this length differs from it.
+	// +optional
+	PrefixLength *int32 `json:"prefixLength,omitempty"`
+	// AdditionalPrefix is a prefix which is added to the node network in addition to the allocated one.
+	// +optional
+	AdditionalPrefix *string `json:"additionalPrefix,omitempty"`
+	// DualStack requests a node network with an IPv4 and an IPv6 prefix.
+	// +optional
+	DualStack bool `json:"dualStack,omitempty"`
 }
 
 type Firewall struct {
 	Size     string   `json:"size"`
 	Image    string   `json:"image"`
 	Networks []string `json:"networks"`
+	// Replicas is the amount of firewall machines that are deployed for the cluster, defaults to one.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Rules contains rules which are applied on the firewall.
+	// +optional
+	Rules *FirewallRules `json:"rules,omitempty"`
+	// EgressIPs are static egress IPs of firewall networks which are kept when the firewall is replaced. As they can
+	// only be attached to one firewall at a time, a firewall with egress IPs is deleted before its replacement is
+	// created and the cluster has no egress in between.
+	// +optional
+	EgressIPs []FirewallEgressIPs `json:"egressIPs,omitempty"`
+	// IgnitionSnippet is a container linux config snippet which is merged into the user data of new firewalls after
+	// the snippets of the cloud profile. It replaces files and units of the cloud profile snippets with the same name.
+	// Firewalls are replaced one after another if the snippet changes.
+	// +optional
+	IgnitionSnippet *string `json:"ignitionSnippet,omitempty"`
+}
+
+// FirewallEgressIPs contains the static egress IPs of a firewall network.
+type FirewallEgressIPs struct {
+	// NetworkID is the id of the firewall network.
+	NetworkID string `json:"networkID"`
+	// IPs are static IPs of the project in the network, they are never released by the extension. If no IPs are
+	// given, a static IP is allocated once and released when the shoot is deleted.
+	// +optional
+	IPs []string `json:"ips,omitempty"`
+}
+
+// FirewallRules contains declarative rules for the traffic passing the firewalls.
+type FirewallRules struct {
+	// Egress is an allow-list of egress traffic leaving the cluster. If set, all other egress traffic is dropped. The
+	// rules are applied to the running firewalls by the firewall-policy-controller.
+	// +optional
+	Egress []EgressRule `json:"egress,omitempty"`
+	// Ingress is an allow-list of traffic entering the cluster from the firewall networks. If there are rules for a
+	// network, all other traffic entering from it is dropped except the replies to connections of the cluster. The rules
+	// only restrict the traffic the firewalls allow otherwise, e.g. for services of type LoadBalancer. They are part of
+	// the user data of the firewalls, the firewalls are replaced one after another if they change.
+	// +optional
+	Ingress []IngressRule `json:"ingress,omitempty"`
+	// RateLimits contains bandwidth limits of networks attached to the firewall. They are part of the user data of the
+	// firewalls, the firewalls are replaced one after another if they change.
+	// +optional
+	RateLimits []RateLimit `json:"rateLimits,omitempty"`
+}
+
+// FirewallProtocol is a transport protocol of a firewall rule.
+type FirewallProtocol string
+
+const (
+	// FirewallProtocolTCP is the protocol for tcp traffic.
+	FirewallProtocolTCP FirewallProtocol = "TCP"
+	// FirewallProtocolUDP is the protocol for udp traffic.
+	FirewallProtocolUDP FirewallProtocol = "UDP"
+)
+
+// EgressRule allows egress traffic to the given destinations.
+type EgressRule struct {
+	// Protocol is the transport protocol of the traffic.
+	Protocol FirewallProtocol `json:"protocol"`
+	// CIDRs are the destination networks of the traffic.
+	CIDRs []string `json:"cidrs"`
+	// Ports are the destination ports of the traffic, all ports are allowed if empty.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// IngressRule allows ingress traffic from an external network.
+type IngressRule struct {
+	// NetworkID is the external network the traffic enters from, it must be one of the firewall networks.
+	NetworkID string `json:"networkID"`
+	// Protocol is the transport protocol of the traffic.
+	Protocol FirewallProtocol `json:"protocol"`
+	// CIDRs are the source networks of the traffic, all sources are allowed if empty.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Ports are the destination ports of the traffic, all ports are allowed if empty.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// RateLimit limits the bandwidth of a network attached to the firewall.
+type RateLimit struct {
+	// NetworkID is the network to limit, it must be one of the firewall networks.
+	NetworkID string `json:"networkID"`
+	// RateLimit is the bandwidth limit in Mbit/s.
+	RateLimit uint32 `json:"rateLimit"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -25,10 +140,183 @@ type Firewall struct {
 // InfrastructureStatus contains information about created infrastructure resources.
 type InfrastructureStatus struct {
 	metav1.TypeMeta `json:",inline"`
-	Firewall        FirewallStatus `json:"firewall"`
+	// Firewalls contains the status of every firewall machine of the cluster.
+	Firewalls []FirewallStatus `json:"firewalls,omitempty"`
+	// Firewall is the status of the single firewall of the cluster.
+	// Deprecated: only read for migrating existing resources to Firewalls.
+	// +optional
+	Firewall *FirewallStatus `json:"firewall,omitempty"`
+	// Rollout contains the state of an ongoing firewall replacement.
+	// +optional
+	Rollout *FirewallRollout `json:"rollout,omitempty"`
+	// NodeNetworkPrefixes contains all prefixes of the node network.
+	// +optional
+	NodeNetworkPrefixes []string `json:"nodeNetworkPrefixes,omitempty"`
+	// AdditionalNodeNetworks contains the node networks of the additional partitions of the cluster.
+	// +optional
+	AdditionalNodeNetworks []PartitionNodeNetwork `json:"additionalNodeNetworks,omitempty"`
+	// DeletionPlan contains the metal resources that are released when the infrastructure is deleted. It is only
+	// computed on request.
+	// +optional
+	DeletionPlan *DeletionPlan `json:"deletionPlan,omitempty"`
+	// EgressIPs are the static egress IPs attached to the firewalls, including the allocated ones.
+	// +optional
+	EgressIPs []FirewallEgressIPs `json:"egressIPs,omitempty"`
+	// FirewallCredentials contains the status of the credentials the firewalls use to access the shoot.
+	// +optional
+	FirewallCredentials *FirewallCredentialsStatus `json:"firewallCredentials,omitempty"`
+	// FirewallProvisioning contains the state of the firewall allocations while not all firewalls are provisioned.
+	// +optional
+	FirewallProvisioning *FirewallProvisioningStatus `json:"firewallProvisioning,omitempty"`
+	// Deletion contains the progress of the release of the metal resources of the cluster while the infrastructure is
+	// deleted.
+	// +optional
+	Deletion *DeletionStatus `json:"deletion,omitempty"`
+}
+
+// PartitionNodeNetwork is the node network of the cluster in a partition.
+type PartitionNodeNetwork struct {
+	// PartitionID is the partition of the node network.
+	PartitionID string `json:"partitionID"`
+	// NetworkID is the id of the node network.
+	NetworkID string `json:"networkID"`
+	// Prefixes contains all prefixes of the node network.
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// FirewallProvisioningStatus contains the state of the firewall allocations of the cluster. It is removed once all
+// firewalls of the cluster are provisioned.
+type FirewallProvisioningStatus struct {
+	// Attempts is the number of firewall allocations since all firewalls of the cluster were provisioned.
+	Attempts int32 `json:"attempts"`
+	// LastError is the reason the last firewall allocation failed.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time the last firewall allocation failed.
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
 }
 
+// FirewallCredentialsStatus contains the status of the kubeconfig the firewall-policy-controller uses to access the
+// shoot.
+type FirewallCredentialsStatus struct {
+	// ExpirationTime is the time the client certificate of the firewall-policy-controller expires.
+	ExpirationTime metav1.Time `json:"expirationTime"`
+	// LastSyncTime is the last time the kubeconfig was written to the shoot.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// FirewallStatus contains the status of a firewall of the cluster.
 type FirewallStatus struct {
 	Succeeded bool   `json:"succeeded"`
 	MachineID string `json:"machineID"`
+	// Phase is the phase of the firewall derived from its allocation and liveliness.
+	// +optional
+	Phase FirewallPhase `json:"phase,omitempty"`
+	// Liveliness is the liveliness of the firewall machine as reported by the metal-api.
+	// +optional
+	Liveliness string `json:"liveliness,omitempty"`
+	// Hostname is the hostname of the firewall.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Size is the size of the firewall machine.
+	// +optional
+	Size string `json:"size,omitempty"`
+	// Image is the image the firewall was allocated with.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Partition is the partition the firewall is located in.
+	// +optional
+	Partition string `json:"partition,omitempty"`
+	// AllocationTimestamp is the point in time when the firewall was allocated.
+	// +optional
+	AllocationTimestamp *metav1.Time `json:"allocationTimestamp,omitempty"`
+	// Networks contains the networks of the firewall and the IPs it acquired in them.
+	// +optional
+	Networks []FirewallNetworkStatus `json:"networks,omitempty"`
+}
+
+// FirewallPhase is the phase of a firewall.
+type FirewallPhase string
+
+const (
+	// FirewallPhaseProvisioning means that the allocation of the firewall has not yet succeeded.
+	FirewallPhaseProvisioning FirewallPhase = "Provisioning"
+	// FirewallPhaseRunning means that the firewall is allocated and alive.
+	FirewallPhaseRunning FirewallPhase = "Running"
+	// FirewallPhaseUnhealthy means that the firewall is allocated but the metal-api does not report it as alive.
+	FirewallPhaseUnhealthy FirewallPhase = "Unhealthy"
+)
+
+// FirewallNetworkStatus contains the status of a network of a firewall.
+type FirewallNetworkStatus struct {
+	// NetworkID is the id of the network.
+	NetworkID string `json:"networkID"`
+	// IPs are the IPs of the firewall in this network.
+	// +optional
+	IPs []string `json:"ips,omitempty"`
+}
+
+// FirewallRolloutPhase is the phase of a firewall replacement.
+type FirewallRolloutPhase string
+
+const (
+	// FirewallRolloutPhaseProvisioning means that the new firewall was created and the rollout waits for its allocation to succeed.
+	FirewallRolloutPhaseProvisioning FirewallRolloutPhase = "Provisioning"
+	// FirewallRolloutPhaseDeleting means that the new firewall is allocated and the old firewall is being deleted.
+	FirewallRolloutPhaseDeleting FirewallRolloutPhase = "Deleting"
+)
+
+// FirewallRollout describes the replacement of an outdated firewall by a new one.
+type FirewallRollout struct {
+	// Phase is the current phase of the rollout.
+	Phase FirewallRolloutPhase `json:"phase"`
+	// OldMachineID is the machine id of the firewall that gets replaced.
+	OldMachineID string `json:"oldMachineID"`
+	// NewMachineID is the machine id of the firewall that replaces the old one.
+	NewMachineID string `json:"newMachineID"`
+}
+
+// DeletionPlan contains the metal resources that are released when the infrastructure is deleted.
+type DeletionPlan struct {
+	// Timestamp is the point in time when the plan was computed.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Firewalls are the machine ids of the firewalls that are deleted.
+	// +optional
+	Firewalls []string `json:"firewalls,omitempty"`
+	// IPsToFree are the ephemeral IPs of the cluster that are released.
+	// +optional
+	IPsToFree []string `json:"ipsToFree,omitempty"`
+	// IPsToUpdate are the ephemeral IPs that are also used by other clusters, only the tags of this cluster are removed.
+	// +optional
+	IPsToUpdate []string `json:"ipsToUpdate,omitempty"`
+	// Networks are the ids of the private networks that are released.
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+}
+
+// DeletionStatus contains the progress of the release of the metal resources of the cluster. Released resources are
+// skipped when the deletion is repeated.
+type DeletionStatus struct {
+	// ReleasedIPs are the ephemeral IPs which were released or from which the tags of the cluster were removed.
+	// +optional
+	ReleasedIPs []string `json:"releasedIPs,omitempty"`
+	// ReleasedNetworks are the ids of the networks which were released.
+	// +optional
+	ReleasedNetworks []string `json:"releasedNetworks,omitempty"`
+	// Failures are the resources whose release failed on the last attempt.
+	// +optional
+	Failures []DeletionFailure `json:"failures,omitempty"`
+}
+
+// DeletionFailure is a metal resource whose release failed.
+type DeletionFailure struct {
+	// Kind is the kind of the resource, either ip or network.
+	Kind string `json:"kind"`
+	// ID is the id of the resource, the address in case of an ip.
+	ID string `json:"id"`
+	// Error is the reason the release failed.
+	Error string `json:"error"`
 }
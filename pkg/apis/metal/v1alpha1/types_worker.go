@@ -18,3 +18,36 @@ type WorkerStatus struct {
 	// reconciliation is possible.
 	MachineImages []config.MachineImage `json:"machineImages,omitempty"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WorkerConfig contains the metal specific configuration of a worker pool. Additional networks of the machines are not
+// supported until the MetalMachineClass of the machine-controller-manager fork in use allows to configure networks.
+type WorkerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ExtraTags are additional tags of the machines of the worker pool in the form key=value.
+	// +optional
+	ExtraTags []string `json:"extraTags,omitempty"`
+	// PartitionID is the partition the machines of a worker pool without zones are placed into instead of the
+	// partition of the infrastructure.
+	// +optional
+	PartitionID *string `json:"partitionID,omitempty"`
+	// ImageOverrides replace the metal images of machine image versions from the controller configuration.
+	// +optional
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+	// UserDataSnippets are appended to the user data of the machines, they must be in the format of the user data
+	// generated for the operating system of the worker pool.
+	// +optional
+	UserDataSnippets []string `json:"userDataSnippets,omitempty"`
+}
+
+// ImageOverride is a metal image used for a machine image version instead of the one from the controller configuration.
+type ImageOverride struct {
+	// Name is the logical name of the machine image.
+	Name string `json:"name"`
+	// Version is the logical version of the machine image.
+	Version string `json:"version"`
+	// Image is the id of the metal image.
+	Image string `json:"image"`
+}
@@ -25,6 +25,7 @@ import (
 
 	config "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
 	metal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -56,6 +57,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ConfigMapReference)(nil), (*metal.ConfigMapReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference(a.(*ConfigMapReference), b.(*metal.ConfigMapReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.ConfigMapReference)(nil), (*ConfigMapReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference(a.(*metal.ConfigMapReference), b.(*ConfigMapReference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ConnectorConfig)(nil), (*metal.ConnectorConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ConnectorConfig_To_metal_ConnectorConfig(a.(*ConnectorConfig), b.(*metal.ConnectorConfig), scope)
 	}); err != nil {
@@ -76,6 +87,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeletionFailure)(nil), (*metal.DeletionFailure)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DeletionFailure_To_metal_DeletionFailure(a.(*DeletionFailure), b.(*metal.DeletionFailure), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.DeletionFailure)(nil), (*DeletionFailure)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_DeletionFailure_To_v1alpha1_DeletionFailure(a.(*metal.DeletionFailure), b.(*DeletionFailure), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeletionPlan)(nil), (*metal.DeletionPlan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DeletionPlan_To_metal_DeletionPlan(a.(*DeletionPlan), b.(*metal.DeletionPlan), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.DeletionPlan)(nil), (*DeletionPlan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_DeletionPlan_To_v1alpha1_DeletionPlan(a.(*metal.DeletionPlan), b.(*DeletionPlan), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeletionStatus)(nil), (*metal.DeletionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DeletionStatus_To_metal_DeletionStatus(a.(*DeletionStatus), b.(*metal.DeletionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.DeletionStatus)(nil), (*DeletionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_DeletionStatus_To_v1alpha1_DeletionStatus(a.(*metal.DeletionStatus), b.(*DeletionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EgressRule)(nil), (*metal.EgressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EgressRule_To_metal_EgressRule(a.(*EgressRule), b.(*metal.EgressRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.EgressRule)(nil), (*EgressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_EgressRule_To_v1alpha1_EgressRule(a.(*metal.EgressRule), b.(*EgressRule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Firewall)(nil), (*metal.Firewall)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Firewall_To_metal_Firewall(a.(*Firewall), b.(*metal.Firewall), scope)
 	}); err != nil {
@@ -86,6 +137,76 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FirewallCredentialsStatus)(nil), (*metal.FirewallCredentialsStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallCredentialsStatus_To_metal_FirewallCredentialsStatus(a.(*FirewallCredentialsStatus), b.(*metal.FirewallCredentialsStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallCredentialsStatus)(nil), (*FirewallCredentialsStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallCredentialsStatus_To_v1alpha1_FirewallCredentialsStatus(a.(*metal.FirewallCredentialsStatus), b.(*FirewallCredentialsStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallEgressIPs)(nil), (*metal.FirewallEgressIPs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallEgressIPs_To_metal_FirewallEgressIPs(a.(*FirewallEgressIPs), b.(*metal.FirewallEgressIPs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallEgressIPs)(nil), (*FirewallEgressIPs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallEgressIPs_To_v1alpha1_FirewallEgressIPs(a.(*metal.FirewallEgressIPs), b.(*FirewallEgressIPs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallIgnitionSnippets)(nil), (*metal.FirewallIgnitionSnippets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallIgnitionSnippets_To_metal_FirewallIgnitionSnippets(a.(*FirewallIgnitionSnippets), b.(*metal.FirewallIgnitionSnippets), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallIgnitionSnippets)(nil), (*FirewallIgnitionSnippets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallIgnitionSnippets_To_v1alpha1_FirewallIgnitionSnippets(a.(*metal.FirewallIgnitionSnippets), b.(*FirewallIgnitionSnippets), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallNetworkStatus)(nil), (*metal.FirewallNetworkStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallNetworkStatus_To_metal_FirewallNetworkStatus(a.(*FirewallNetworkStatus), b.(*metal.FirewallNetworkStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallNetworkStatus)(nil), (*FirewallNetworkStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallNetworkStatus_To_v1alpha1_FirewallNetworkStatus(a.(*metal.FirewallNetworkStatus), b.(*FirewallNetworkStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallProvisioningStatus)(nil), (*metal.FirewallProvisioningStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallProvisioningStatus_To_metal_FirewallProvisioningStatus(a.(*FirewallProvisioningStatus), b.(*metal.FirewallProvisioningStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallProvisioningStatus)(nil), (*FirewallProvisioningStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallProvisioningStatus_To_v1alpha1_FirewallProvisioningStatus(a.(*metal.FirewallProvisioningStatus), b.(*FirewallProvisioningStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallRollout)(nil), (*metal.FirewallRollout)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallRollout_To_metal_FirewallRollout(a.(*FirewallRollout), b.(*metal.FirewallRollout), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallRollout)(nil), (*FirewallRollout)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallRollout_To_v1alpha1_FirewallRollout(a.(*metal.FirewallRollout), b.(*FirewallRollout), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FirewallRules)(nil), (*metal.FirewallRules)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FirewallRules_To_metal_FirewallRules(a.(*FirewallRules), b.(*metal.FirewallRules), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.FirewallRules)(nil), (*FirewallRules)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_FirewallRules_To_v1alpha1_FirewallRules(a.(*metal.FirewallRules), b.(*FirewallRules), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*FirewallStatus)(nil), (*metal.FirewallStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_FirewallStatus_To_metal_FirewallStatus(a.(*FirewallStatus), b.(*metal.FirewallStatus), scope)
 	}); err != nil {
@@ -116,6 +237,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageOverride)(nil), (*metal.ImageOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ImageOverride_To_metal_ImageOverride(a.(*ImageOverride), b.(*metal.ImageOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.ImageOverride)(nil), (*ImageOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_ImageOverride_To_v1alpha1_ImageOverride(a.(*metal.ImageOverride), b.(*ImageOverride), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InfrastructureConfig)(nil), (*metal.InfrastructureConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InfrastructureConfig_To_metal_InfrastructureConfig(a.(*InfrastructureConfig), b.(*metal.InfrastructureConfig), scope)
 	}); err != nil {
@@ -136,6 +267,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*IngressRule)(nil), (*metal.IngressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_IngressRule_To_metal_IngressRule(a.(*IngressRule), b.(*metal.IngressRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.IngressRule)(nil), (*IngressRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_IngressRule_To_v1alpha1_IngressRule(a.(*metal.IngressRule), b.(*IngressRule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*IssuerConfig)(nil), (*metal.IssuerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_IssuerConfig_To_metal_IssuerConfig(a.(*IssuerConfig), b.(*metal.IssuerConfig), scope)
 	}); err != nil {
@@ -156,6 +297,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeNetwork)(nil), (*metal.NodeNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeNetwork_To_metal_NodeNetwork(a.(*NodeNetwork), b.(*metal.NodeNetwork), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.NodeNetwork)(nil), (*NodeNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_NodeNetwork_To_v1alpha1_NodeNetwork(a.(*metal.NodeNetwork), b.(*NodeNetwork), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PartitionNodeNetwork)(nil), (*metal.PartitionNodeNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PartitionNodeNetwork_To_metal_PartitionNodeNetwork(a.(*PartitionNodeNetwork), b.(*metal.PartitionNodeNetwork), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.PartitionNodeNetwork)(nil), (*PartitionNodeNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_PartitionNodeNetwork_To_v1alpha1_PartitionNodeNetwork(a.(*metal.PartitionNodeNetwork), b.(*PartitionNodeNetwork), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RateLimit)(nil), (*metal.RateLimit)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RateLimit_To_metal_RateLimit(a.(*RateLimit), b.(*metal.RateLimit), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.RateLimit)(nil), (*RateLimit)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_RateLimit_To_v1alpha1_RateLimit(a.(*metal.RateLimit), b.(*RateLimit), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WorkerConfig)(nil), (*metal.WorkerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_WorkerConfig_To_metal_WorkerConfig(a.(*WorkerConfig), b.(*metal.WorkerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*metal.WorkerConfig)(nil), (*WorkerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_metal_WorkerConfig_To_v1alpha1_WorkerConfig(a.(*metal.WorkerConfig), b.(*WorkerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*WorkerStatus)(nil), (*metal.WorkerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_WorkerStatus_To_metal_WorkerStatus(a.(*WorkerStatus), b.(*metal.WorkerStatus), scope)
 	}); err != nil {
@@ -193,6 +374,8 @@ func autoConvert_v1alpha1_CloudProfileConfig_To_metal_CloudProfileConfig(in *Clo
 	out.FirewallImages = *(*[]string)(unsafe.Pointer(&in.FirewallImages))
 	out.FirewallNetworks = *(*map[string]map[string]string)(unsafe.Pointer(&in.FirewallNetworks))
 	out.IAMConfig = (*metal.IAMConfig)(unsafe.Pointer(in.IAMConfig))
+	out.IPv6Partitions = *(*[]string)(unsafe.Pointer(&in.IPv6Partitions))
+	out.FirewallIgnitionSnippets = *(*[]metal.FirewallIgnitionSnippets)(unsafe.Pointer(&in.FirewallIgnitionSnippets))
 	return nil
 }
 
@@ -205,6 +388,8 @@ func autoConvert_metal_CloudProfileConfig_To_v1alpha1_CloudProfileConfig(in *met
 	out.FirewallImages = *(*[]string)(unsafe.Pointer(&in.FirewallImages))
 	out.FirewallNetworks = *(*map[string]map[string]string)(unsafe.Pointer(&in.FirewallNetworks))
 	out.IAMConfig = (*IAMConfig)(unsafe.Pointer(in.IAMConfig))
+	out.IPv6Partitions = *(*[]string)(unsafe.Pointer(&in.IPv6Partitions))
+	out.FirewallIgnitionSnippets = *(*[]FirewallIgnitionSnippets)(unsafe.Pointer(&in.FirewallIgnitionSnippets))
 	return nil
 }
 
@@ -213,6 +398,28 @@ func Convert_metal_CloudProfileConfig_To_v1alpha1_CloudProfileConfig(in *metal.C
 	return autoConvert_metal_CloudProfileConfig_To_v1alpha1_CloudProfileConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference(in *ConfigMapReference, out *metal.ConfigMapReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference is an autogenerated conversion function.
+func Convert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference(in *ConfigMapReference, out *metal.ConfigMapReference, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference(in, out, s)
+}
+
+func autoConvert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference(in *metal.ConfigMapReference, out *ConfigMapReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	return nil
+}
+
+// Convert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference is an autogenerated conversion function.
+func Convert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference(in *metal.ConfigMapReference, out *ConfigMapReference, s conversion.Scope) error {
+	return autoConvert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference(in, out, s)
+}
+
 func autoConvert_v1alpha1_ConnectorConfig_To_metal_ConnectorConfig(in *ConnectorConfig, out *metal.ConnectorConfig, s conversion.Scope) error {
 	out.IdmApiUrl = in.IdmApiUrl
 	out.IdmApiUser = in.IdmApiUser
@@ -283,10 +490,114 @@ func Convert_metal_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in *metal.C
 	return autoConvert_metal_ControlPlaneConfig_To_v1alpha1_ControlPlaneConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_DeletionFailure_To_metal_DeletionFailure(in *DeletionFailure, out *metal.DeletionFailure, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.ID = in.ID
+	out.Error = in.Error
+	return nil
+}
+
+// Convert_v1alpha1_DeletionFailure_To_metal_DeletionFailure is an autogenerated conversion function.
+func Convert_v1alpha1_DeletionFailure_To_metal_DeletionFailure(in *DeletionFailure, out *metal.DeletionFailure, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DeletionFailure_To_metal_DeletionFailure(in, out, s)
+}
+
+func autoConvert_metal_DeletionFailure_To_v1alpha1_DeletionFailure(in *metal.DeletionFailure, out *DeletionFailure, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.ID = in.ID
+	out.Error = in.Error
+	return nil
+}
+
+// Convert_metal_DeletionFailure_To_v1alpha1_DeletionFailure is an autogenerated conversion function.
+func Convert_metal_DeletionFailure_To_v1alpha1_DeletionFailure(in *metal.DeletionFailure, out *DeletionFailure, s conversion.Scope) error {
+	return autoConvert_metal_DeletionFailure_To_v1alpha1_DeletionFailure(in, out, s)
+}
+
+func autoConvert_v1alpha1_DeletionPlan_To_metal_DeletionPlan(in *DeletionPlan, out *metal.DeletionPlan, s conversion.Scope) error {
+	out.Timestamp = in.Timestamp
+	out.Firewalls = *(*[]string)(unsafe.Pointer(&in.Firewalls))
+	out.IPsToFree = *(*[]string)(unsafe.Pointer(&in.IPsToFree))
+	out.IPsToUpdate = *(*[]string)(unsafe.Pointer(&in.IPsToUpdate))
+	out.Networks = *(*[]string)(unsafe.Pointer(&in.Networks))
+	return nil
+}
+
+// Convert_v1alpha1_DeletionPlan_To_metal_DeletionPlan is an autogenerated conversion function.
+func Convert_v1alpha1_DeletionPlan_To_metal_DeletionPlan(in *DeletionPlan, out *metal.DeletionPlan, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DeletionPlan_To_metal_DeletionPlan(in, out, s)
+}
+
+func autoConvert_metal_DeletionPlan_To_v1alpha1_DeletionPlan(in *metal.DeletionPlan, out *DeletionPlan, s conversion.Scope) error {
+	out.Timestamp = in.Timestamp
+	out.Firewalls = *(*[]string)(unsafe.Pointer(&in.Firewalls))
+	out.IPsToFree = *(*[]string)(unsafe.Pointer(&in.IPsToFree))
+	out.IPsToUpdate = *(*[]string)(unsafe.Pointer(&in.IPsToUpdate))
+	out.Networks = *(*[]string)(unsafe.Pointer(&in.Networks))
+	return nil
+}
+
+// Convert_metal_DeletionPlan_To_v1alpha1_DeletionPlan is an autogenerated conversion function.
+func Convert_metal_DeletionPlan_To_v1alpha1_DeletionPlan(in *metal.DeletionPlan, out *DeletionPlan, s conversion.Scope) error {
+	return autoConvert_metal_DeletionPlan_To_v1alpha1_DeletionPlan(in, out, s)
+}
+
+func autoConvert_v1alpha1_DeletionStatus_To_metal_DeletionStatus(in *DeletionStatus, out *metal.DeletionStatus, s conversion.Scope) error {
+	out.ReleasedIPs = *(*[]string)(unsafe.Pointer(&in.ReleasedIPs))
+	out.ReleasedNetworks = *(*[]string)(unsafe.Pointer(&in.ReleasedNetworks))
+	out.Failures = *(*[]metal.DeletionFailure)(unsafe.Pointer(&in.Failures))
+	return nil
+}
+
+// Convert_v1alpha1_DeletionStatus_To_metal_DeletionStatus is an autogenerated conversion function.
+func Convert_v1alpha1_DeletionStatus_To_metal_DeletionStatus(in *DeletionStatus, out *metal.DeletionStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DeletionStatus_To_metal_DeletionStatus(in, out, s)
+}
+
+func autoConvert_metal_DeletionStatus_To_v1alpha1_DeletionStatus(in *metal.DeletionStatus, out *DeletionStatus, s conversion.Scope) error {
+	out.ReleasedIPs = *(*[]string)(unsafe.Pointer(&in.ReleasedIPs))
+	out.ReleasedNetworks = *(*[]string)(unsafe.Pointer(&in.ReleasedNetworks))
+	out.Failures = *(*[]DeletionFailure)(unsafe.Pointer(&in.Failures))
+	return nil
+}
+
+// Convert_metal_DeletionStatus_To_v1alpha1_DeletionStatus is an autogenerated conversion function.
+func Convert_metal_DeletionStatus_To_v1alpha1_DeletionStatus(in *metal.DeletionStatus, out *DeletionStatus, s conversion.Scope) error {
+	return autoConvert_metal_DeletionStatus_To_v1alpha1_DeletionStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_EgressRule_To_metal_EgressRule(in *EgressRule, out *metal.EgressRule, s conversion.Scope) error {
+	out.Protocol = metal.FirewallProtocol(in.Protocol)
+	out.CIDRs = *(*[]string)(unsafe.Pointer(&in.CIDRs))
+	out.Ports = *(*[]int32)(unsafe.Pointer(&in.Ports))
+	return nil
+}
+
+// Convert_v1alpha1_EgressRule_To_metal_EgressRule is an autogenerated conversion function.
+func Convert_v1alpha1_EgressRule_To_metal_EgressRule(in *EgressRule, out *metal.EgressRule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EgressRule_To_metal_EgressRule(in, out, s)
+}
+
+func autoConvert_metal_EgressRule_To_v1alpha1_EgressRule(in *metal.EgressRule, out *EgressRule, s conversion.Scope) error {
+	out.Protocol = FirewallProtocol(in.Protocol)
+	out.CIDRs = *(*[]string)(unsafe.Pointer(&in.CIDRs))
+	out.Ports = *(*[]int32)(unsafe.Pointer(&in.Ports))
+	return nil
+}
+
+// Convert_metal_EgressRule_To_v1alpha1_EgressRule is an autogenerated conversion function.
+func Convert_metal_EgressRule_To_v1alpha1_EgressRule(in *metal.EgressRule, out *EgressRule, s conversion.Scope) error {
+	return autoConvert_metal_EgressRule_To_v1alpha1_EgressRule(in, out, s)
+}
+
 func autoConvert_v1alpha1_Firewall_To_metal_Firewall(in *Firewall, out *metal.Firewall, s conversion.Scope) error {
 	out.Size = in.Size
 	out.Image = in.Image
 	out.Networks = *(*[]string)(unsafe.Pointer(&in.Networks))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Rules = (*metal.FirewallRules)(unsafe.Pointer(in.Rules))
+	out.EgressIPs = *(*[]metal.FirewallEgressIPs)(unsafe.Pointer(&in.EgressIPs))
+	out.IgnitionSnippet = (*string)(unsafe.Pointer(in.IgnitionSnippet))
 	return nil
 }
 
@@ -299,6 +610,10 @@ func autoConvert_metal_Firewall_To_v1alpha1_Firewall(in *metal.Firewall, out *Fi
 	out.Size = in.Size
 	out.Image = in.Image
 	out.Networks = *(*[]string)(unsafe.Pointer(&in.Networks))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.Rules = (*FirewallRules)(unsafe.Pointer(in.Rules))
+	out.EgressIPs = *(*[]FirewallEgressIPs)(unsafe.Pointer(&in.EgressIPs))
+	out.IgnitionSnippet = (*string)(unsafe.Pointer(in.IgnitionSnippet))
 	return nil
 }
 
@@ -307,9 +622,183 @@ func Convert_metal_Firewall_To_v1alpha1_Firewall(in *metal.Firewall, out *Firewa
 	return autoConvert_metal_Firewall_To_v1alpha1_Firewall(in, out, s)
 }
 
+func autoConvert_v1alpha1_FirewallCredentialsStatus_To_metal_FirewallCredentialsStatus(in *FirewallCredentialsStatus, out *metal.FirewallCredentialsStatus, s conversion.Scope) error {
+	out.ExpirationTime = in.ExpirationTime
+	out.LastSyncTime = (*v1.Time)(unsafe.Pointer(in.LastSyncTime))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallCredentialsStatus_To_metal_FirewallCredentialsStatus is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallCredentialsStatus_To_metal_FirewallCredentialsStatus(in *FirewallCredentialsStatus, out *metal.FirewallCredentialsStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallCredentialsStatus_To_metal_FirewallCredentialsStatus(in, out, s)
+}
+
+func autoConvert_metal_FirewallCredentialsStatus_To_v1alpha1_FirewallCredentialsStatus(in *metal.FirewallCredentialsStatus, out *FirewallCredentialsStatus, s conversion.Scope) error {
+	out.ExpirationTime = in.ExpirationTime
+	out.LastSyncTime = (*v1.Time)(unsafe.Pointer(in.LastSyncTime))
+	return nil
+}
+
+// Convert_metal_FirewallCredentialsStatus_To_v1alpha1_FirewallCredentialsStatus is an autogenerated conversion function.
+func Convert_metal_FirewallCredentialsStatus_To_v1alpha1_FirewallCredentialsStatus(in *metal.FirewallCredentialsStatus, out *FirewallCredentialsStatus, s conversion.Scope) error {
+	return autoConvert_metal_FirewallCredentialsStatus_To_v1alpha1_FirewallCredentialsStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallEgressIPs_To_metal_FirewallEgressIPs(in *FirewallEgressIPs, out *metal.FirewallEgressIPs, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.IPs = *(*[]string)(unsafe.Pointer(&in.IPs))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallEgressIPs_To_metal_FirewallEgressIPs is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallEgressIPs_To_metal_FirewallEgressIPs(in *FirewallEgressIPs, out *metal.FirewallEgressIPs, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallEgressIPs_To_metal_FirewallEgressIPs(in, out, s)
+}
+
+func autoConvert_metal_FirewallEgressIPs_To_v1alpha1_FirewallEgressIPs(in *metal.FirewallEgressIPs, out *FirewallEgressIPs, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.IPs = *(*[]string)(unsafe.Pointer(&in.IPs))
+	return nil
+}
+
+// Convert_metal_FirewallEgressIPs_To_v1alpha1_FirewallEgressIPs is an autogenerated conversion function.
+func Convert_metal_FirewallEgressIPs_To_v1alpha1_FirewallEgressIPs(in *metal.FirewallEgressIPs, out *FirewallEgressIPs, s conversion.Scope) error {
+	return autoConvert_metal_FirewallEgressIPs_To_v1alpha1_FirewallEgressIPs(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallIgnitionSnippets_To_metal_FirewallIgnitionSnippets(in *FirewallIgnitionSnippets, out *metal.FirewallIgnitionSnippets, s conversion.Scope) error {
+	if err := Convert_v1alpha1_ConfigMapReference_To_metal_ConfigMapReference(&in.ConfigMapRef, &out.ConfigMapRef, s); err != nil {
+		return err
+	}
+	out.Partitions = *(*[]string)(unsafe.Pointer(&in.Partitions))
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallIgnitionSnippets_To_metal_FirewallIgnitionSnippets is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallIgnitionSnippets_To_metal_FirewallIgnitionSnippets(in *FirewallIgnitionSnippets, out *metal.FirewallIgnitionSnippets, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallIgnitionSnippets_To_metal_FirewallIgnitionSnippets(in, out, s)
+}
+
+func autoConvert_metal_FirewallIgnitionSnippets_To_v1alpha1_FirewallIgnitionSnippets(in *metal.FirewallIgnitionSnippets, out *FirewallIgnitionSnippets, s conversion.Scope) error {
+	if err := Convert_metal_ConfigMapReference_To_v1alpha1_ConfigMapReference(&in.ConfigMapRef, &out.ConfigMapRef, s); err != nil {
+		return err
+	}
+	out.Partitions = *(*[]string)(unsafe.Pointer(&in.Partitions))
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_metal_FirewallIgnitionSnippets_To_v1alpha1_FirewallIgnitionSnippets is an autogenerated conversion function.
+func Convert_metal_FirewallIgnitionSnippets_To_v1alpha1_FirewallIgnitionSnippets(in *metal.FirewallIgnitionSnippets, out *FirewallIgnitionSnippets, s conversion.Scope) error {
+	return autoConvert_metal_FirewallIgnitionSnippets_To_v1alpha1_FirewallIgnitionSnippets(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallNetworkStatus_To_metal_FirewallNetworkStatus(in *FirewallNetworkStatus, out *metal.FirewallNetworkStatus, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.IPs = *(*[]string)(unsafe.Pointer(&in.IPs))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallNetworkStatus_To_metal_FirewallNetworkStatus is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallNetworkStatus_To_metal_FirewallNetworkStatus(in *FirewallNetworkStatus, out *metal.FirewallNetworkStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallNetworkStatus_To_metal_FirewallNetworkStatus(in, out, s)
+}
+
+func autoConvert_metal_FirewallNetworkStatus_To_v1alpha1_FirewallNetworkStatus(in *metal.FirewallNetworkStatus, out *FirewallNetworkStatus, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.IPs = *(*[]string)(unsafe.Pointer(&in.IPs))
+	return nil
+}
+
+// Convert_metal_FirewallNetworkStatus_To_v1alpha1_FirewallNetworkStatus is an autogenerated conversion function.
+func Convert_metal_FirewallNetworkStatus_To_v1alpha1_FirewallNetworkStatus(in *metal.FirewallNetworkStatus, out *FirewallNetworkStatus, s conversion.Scope) error {
+	return autoConvert_metal_FirewallNetworkStatus_To_v1alpha1_FirewallNetworkStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallProvisioningStatus_To_metal_FirewallProvisioningStatus(in *FirewallProvisioningStatus, out *metal.FirewallProvisioningStatus, s conversion.Scope) error {
+	out.Attempts = in.Attempts
+	out.LastError = in.LastError
+	out.LastErrorTime = (*v1.Time)(unsafe.Pointer(in.LastErrorTime))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallProvisioningStatus_To_metal_FirewallProvisioningStatus is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallProvisioningStatus_To_metal_FirewallProvisioningStatus(in *FirewallProvisioningStatus, out *metal.FirewallProvisioningStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallProvisioningStatus_To_metal_FirewallProvisioningStatus(in, out, s)
+}
+
+func autoConvert_metal_FirewallProvisioningStatus_To_v1alpha1_FirewallProvisioningStatus(in *metal.FirewallProvisioningStatus, out *FirewallProvisioningStatus, s conversion.Scope) error {
+	out.Attempts = in.Attempts
+	out.LastError = in.LastError
+	out.LastErrorTime = (*v1.Time)(unsafe.Pointer(in.LastErrorTime))
+	return nil
+}
+
+// Convert_metal_FirewallProvisioningStatus_To_v1alpha1_FirewallProvisioningStatus is an autogenerated conversion function.
+func Convert_metal_FirewallProvisioningStatus_To_v1alpha1_FirewallProvisioningStatus(in *metal.FirewallProvisioningStatus, out *FirewallProvisioningStatus, s conversion.Scope) error {
+	return autoConvert_metal_FirewallProvisioningStatus_To_v1alpha1_FirewallProvisioningStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallRollout_To_metal_FirewallRollout(in *FirewallRollout, out *metal.FirewallRollout, s conversion.Scope) error {
+	out.Phase = metal.FirewallRolloutPhase(in.Phase)
+	out.OldMachineID = in.OldMachineID
+	out.NewMachineID = in.NewMachineID
+	return nil
+}
+
+// Convert_v1alpha1_FirewallRollout_To_metal_FirewallRollout is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallRollout_To_metal_FirewallRollout(in *FirewallRollout, out *metal.FirewallRollout, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallRollout_To_metal_FirewallRollout(in, out, s)
+}
+
+func autoConvert_metal_FirewallRollout_To_v1alpha1_FirewallRollout(in *metal.FirewallRollout, out *FirewallRollout, s conversion.Scope) error {
+	out.Phase = FirewallRolloutPhase(in.Phase)
+	out.OldMachineID = in.OldMachineID
+	out.NewMachineID = in.NewMachineID
+	return nil
+}
+
+// Convert_metal_FirewallRollout_To_v1alpha1_FirewallRollout is an autogenerated conversion function.
+func Convert_metal_FirewallRollout_To_v1alpha1_FirewallRollout(in *metal.FirewallRollout, out *FirewallRollout, s conversion.Scope) error {
+	return autoConvert_metal_FirewallRollout_To_v1alpha1_FirewallRollout(in, out, s)
+}
+
+func autoConvert_v1alpha1_FirewallRules_To_metal_FirewallRules(in *FirewallRules, out *metal.FirewallRules, s conversion.Scope) error {
+	out.Egress = *(*[]metal.EgressRule)(unsafe.Pointer(&in.Egress))
+	out.Ingress = *(*[]metal.IngressRule)(unsafe.Pointer(&in.Ingress))
+	out.RateLimits = *(*[]metal.RateLimit)(unsafe.Pointer(&in.RateLimits))
+	return nil
+}
+
+// Convert_v1alpha1_FirewallRules_To_metal_FirewallRules is an autogenerated conversion function.
+func Convert_v1alpha1_FirewallRules_To_metal_FirewallRules(in *FirewallRules, out *metal.FirewallRules, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FirewallRules_To_metal_FirewallRules(in, out, s)
+}
+
+func autoConvert_metal_FirewallRules_To_v1alpha1_FirewallRules(in *metal.FirewallRules, out *FirewallRules, s conversion.Scope) error {
+	out.Egress = *(*[]EgressRule)(unsafe.Pointer(&in.Egress))
+	out.Ingress = *(*[]IngressRule)(unsafe.Pointer(&in.Ingress))
+	out.RateLimits = *(*[]RateLimit)(unsafe.Pointer(&in.RateLimits))
+	return nil
+}
+
+// Convert_metal_FirewallRules_To_v1alpha1_FirewallRules is an autogenerated conversion function.
+func Convert_metal_FirewallRules_To_v1alpha1_FirewallRules(in *metal.FirewallRules, out *FirewallRules, s conversion.Scope) error {
+	return autoConvert_metal_FirewallRules_To_v1alpha1_FirewallRules(in, out, s)
+}
+
 func autoConvert_v1alpha1_FirewallStatus_To_metal_FirewallStatus(in *FirewallStatus, out *metal.FirewallStatus, s conversion.Scope) error {
 	out.Succeeded = in.Succeeded
 	out.MachineID = in.MachineID
+	out.Phase = metal.FirewallPhase(in.Phase)
+	out.Liveliness = in.Liveliness
+	out.Hostname = in.Hostname
+	out.Size = in.Size
+	out.Image = in.Image
+	out.Partition = in.Partition
+	out.AllocationTimestamp = (*v1.Time)(unsafe.Pointer(in.AllocationTimestamp))
+	out.Networks = *(*[]metal.FirewallNetworkStatus)(unsafe.Pointer(&in.Networks))
 	return nil
 }
 
@@ -321,6 +810,14 @@ func Convert_v1alpha1_FirewallStatus_To_metal_FirewallStatus(in *FirewallStatus,
 func autoConvert_metal_FirewallStatus_To_v1alpha1_FirewallStatus(in *metal.FirewallStatus, out *FirewallStatus, s conversion.Scope) error {
 	out.Succeeded = in.Succeeded
 	out.MachineID = in.MachineID
+	out.Phase = FirewallPhase(in.Phase)
+	out.Liveliness = in.Liveliness
+	out.Hostname = in.Hostname
+	out.Size = in.Size
+	out.Image = in.Image
+	out.Partition = in.Partition
+	out.AllocationTimestamp = (*v1.Time)(unsafe.Pointer(in.AllocationTimestamp))
+	out.Networks = *(*[]FirewallNetworkStatus)(unsafe.Pointer(&in.Networks))
 	return nil
 }
 
@@ -375,12 +872,39 @@ func Convert_metal_IDMConfig_To_v1alpha1_IDMConfig(in *metal.IDMConfig, out *IDM
 	return autoConvert_metal_IDMConfig_To_v1alpha1_IDMConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_ImageOverride_To_metal_ImageOverride(in *ImageOverride, out *metal.ImageOverride, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_v1alpha1_ImageOverride_To_metal_ImageOverride is an autogenerated conversion function.
+func Convert_v1alpha1_ImageOverride_To_metal_ImageOverride(in *ImageOverride, out *metal.ImageOverride, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ImageOverride_To_metal_ImageOverride(in, out, s)
+}
+
+func autoConvert_metal_ImageOverride_To_v1alpha1_ImageOverride(in *metal.ImageOverride, out *ImageOverride, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_metal_ImageOverride_To_v1alpha1_ImageOverride is an autogenerated conversion function.
+func Convert_metal_ImageOverride_To_v1alpha1_ImageOverride(in *metal.ImageOverride, out *ImageOverride, s conversion.Scope) error {
+	return autoConvert_metal_ImageOverride_To_v1alpha1_ImageOverride(in, out, s)
+}
+
 func autoConvert_v1alpha1_InfrastructureConfig_To_metal_InfrastructureConfig(in *InfrastructureConfig, out *metal.InfrastructureConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha1_Firewall_To_metal_Firewall(&in.Firewall, &out.Firewall, s); err != nil {
 		return err
 	}
 	out.PartitionID = in.PartitionID
 	out.ProjectID = in.ProjectID
+	out.NodeNetworkID = (*string)(unsafe.Pointer(in.NodeNetworkID))
+	out.NodeNetwork = (*metal.NodeNetwork)(unsafe.Pointer(in.NodeNetwork))
+	out.AdditionalPartitionIDs = *(*[]string)(unsafe.Pointer(&in.AdditionalPartitionIDs))
 	return nil
 }
 
@@ -395,6 +919,9 @@ func autoConvert_metal_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(in
 	}
 	out.PartitionID = in.PartitionID
 	out.ProjectID = in.ProjectID
+	out.NodeNetworkID = (*string)(unsafe.Pointer(in.NodeNetworkID))
+	out.NodeNetwork = (*NodeNetwork)(unsafe.Pointer(in.NodeNetwork))
+	out.AdditionalPartitionIDs = *(*[]string)(unsafe.Pointer(&in.AdditionalPartitionIDs))
 	return nil
 }
 
@@ -404,9 +931,16 @@ func Convert_metal_InfrastructureConfig_To_v1alpha1_InfrastructureConfig(in *met
 }
 
 func autoConvert_v1alpha1_InfrastructureStatus_To_metal_InfrastructureStatus(in *InfrastructureStatus, out *metal.InfrastructureStatus, s conversion.Scope) error {
-	if err := Convert_v1alpha1_FirewallStatus_To_metal_FirewallStatus(&in.Firewall, &out.Firewall, s); err != nil {
-		return err
-	}
+	out.Firewalls = *(*[]metal.FirewallStatus)(unsafe.Pointer(&in.Firewalls))
+	out.Firewall = (*metal.FirewallStatus)(unsafe.Pointer(in.Firewall))
+	out.Rollout = (*metal.FirewallRollout)(unsafe.Pointer(in.Rollout))
+	out.NodeNetworkPrefixes = *(*[]string)(unsafe.Pointer(&in.NodeNetworkPrefixes))
+	out.AdditionalNodeNetworks = *(*[]metal.PartitionNodeNetwork)(unsafe.Pointer(&in.AdditionalNodeNetworks))
+	out.DeletionPlan = (*metal.DeletionPlan)(unsafe.Pointer(in.DeletionPlan))
+	out.EgressIPs = *(*[]metal.FirewallEgressIPs)(unsafe.Pointer(&in.EgressIPs))
+	out.FirewallCredentials = (*metal.FirewallCredentialsStatus)(unsafe.Pointer(in.FirewallCredentials))
+	out.FirewallProvisioning = (*metal.FirewallProvisioningStatus)(unsafe.Pointer(in.FirewallProvisioning))
+	out.Deletion = (*metal.DeletionStatus)(unsafe.Pointer(in.Deletion))
 	return nil
 }
 
@@ -416,9 +950,16 @@ func Convert_v1alpha1_InfrastructureStatus_To_metal_InfrastructureStatus(in *Inf
 }
 
 func autoConvert_metal_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in *metal.InfrastructureStatus, out *InfrastructureStatus, s conversion.Scope) error {
-	if err := Convert_metal_FirewallStatus_To_v1alpha1_FirewallStatus(&in.Firewall, &out.Firewall, s); err != nil {
-		return err
-	}
+	out.Firewalls = *(*[]FirewallStatus)(unsafe.Pointer(&in.Firewalls))
+	out.Firewall = (*FirewallStatus)(unsafe.Pointer(in.Firewall))
+	out.Rollout = (*FirewallRollout)(unsafe.Pointer(in.Rollout))
+	out.NodeNetworkPrefixes = *(*[]string)(unsafe.Pointer(&in.NodeNetworkPrefixes))
+	out.AdditionalNodeNetworks = *(*[]PartitionNodeNetwork)(unsafe.Pointer(&in.AdditionalNodeNetworks))
+	out.DeletionPlan = (*DeletionPlan)(unsafe.Pointer(in.DeletionPlan))
+	out.EgressIPs = *(*[]FirewallEgressIPs)(unsafe.Pointer(&in.EgressIPs))
+	out.FirewallCredentials = (*FirewallCredentialsStatus)(unsafe.Pointer(in.FirewallCredentials))
+	out.FirewallProvisioning = (*FirewallProvisioningStatus)(unsafe.Pointer(in.FirewallProvisioning))
+	out.Deletion = (*DeletionStatus)(unsafe.Pointer(in.Deletion))
 	return nil
 }
 
@@ -427,6 +968,32 @@ func Convert_metal_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in *met
 	return autoConvert_metal_InfrastructureStatus_To_v1alpha1_InfrastructureStatus(in, out, s)
 }
 
+func autoConvert_v1alpha1_IngressRule_To_metal_IngressRule(in *IngressRule, out *metal.IngressRule, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.Protocol = metal.FirewallProtocol(in.Protocol)
+	out.CIDRs = *(*[]string)(unsafe.Pointer(&in.CIDRs))
+	out.Ports = *(*[]int32)(unsafe.Pointer(&in.Ports))
+	return nil
+}
+
+// Convert_v1alpha1_IngressRule_To_metal_IngressRule is an autogenerated conversion function.
+func Convert_v1alpha1_IngressRule_To_metal_IngressRule(in *IngressRule, out *metal.IngressRule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_IngressRule_To_metal_IngressRule(in, out, s)
+}
+
+func autoConvert_metal_IngressRule_To_v1alpha1_IngressRule(in *metal.IngressRule, out *IngressRule, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.Protocol = FirewallProtocol(in.Protocol)
+	out.CIDRs = *(*[]string)(unsafe.Pointer(&in.CIDRs))
+	out.Ports = *(*[]int32)(unsafe.Pointer(&in.Ports))
+	return nil
+}
+
+// Convert_metal_IngressRule_To_v1alpha1_IngressRule is an autogenerated conversion function.
+func Convert_metal_IngressRule_To_v1alpha1_IngressRule(in *metal.IngressRule, out *IngressRule, s conversion.Scope) error {
+	return autoConvert_metal_IngressRule_To_v1alpha1_IngressRule(in, out, s)
+}
+
 func autoConvert_v1alpha1_IssuerConfig_To_metal_IssuerConfig(in *IssuerConfig, out *metal.IssuerConfig, s conversion.Scope) error {
 	out.Url = in.Url
 	out.ClientId = in.ClientId
@@ -477,6 +1044,102 @@ func Convert_metal_NamespaceGroupConfig_To_v1alpha1_NamespaceGroupConfig(in *met
 	return autoConvert_metal_NamespaceGroupConfig_To_v1alpha1_NamespaceGroupConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_NodeNetwork_To_metal_NodeNetwork(in *NodeNetwork, out *metal.NodeNetwork, s conversion.Scope) error {
+	out.PrefixLength = (*int32)(unsafe.Pointer(in.PrefixLength))
+	out.AdditionalPrefix = (*string)(unsafe.Pointer(in.AdditionalPrefix))
+	out.DualStack = in.DualStack
+	return nil
+}
+
+// Convert_v1alpha1_NodeNetwork_To_metal_NodeNetwork is an autogenerated conversion function.
+func Convert_v1alpha1_NodeNetwork_To_metal_NodeNetwork(in *NodeNetwork, out *metal.NodeNetwork, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeNetwork_To_metal_NodeNetwork(in, out, s)
+}
+
+func autoConvert_metal_NodeNetwork_To_v1alpha1_NodeNetwork(in *metal.NodeNetwork, out *NodeNetwork, s conversion.Scope) error {
+	out.PrefixLength = (*int32)(unsafe.Pointer(in.PrefixLength))
+	out.AdditionalPrefix = (*string)(unsafe.Pointer(in.AdditionalPrefix))
+	out.DualStack = in.DualStack
+	return nil
+}
+
+// Convert_metal_NodeNetwork_To_v1alpha1_NodeNetwork is an autogenerated conversion function.
+func Convert_metal_NodeNetwork_To_v1alpha1_NodeNetwork(in *metal.NodeNetwork, out *NodeNetwork, s conversion.Scope) error {
+	return autoConvert_metal_NodeNetwork_To_v1alpha1_NodeNetwork(in, out, s)
+}
+
+func autoConvert_v1alpha1_PartitionNodeNetwork_To_metal_PartitionNodeNetwork(in *PartitionNodeNetwork, out *metal.PartitionNodeNetwork, s conversion.Scope) error {
+	out.PartitionID = in.PartitionID
+	out.NetworkID = in.NetworkID
+	out.Prefixes = *(*[]string)(unsafe.Pointer(&in.Prefixes))
+	return nil
+}
+
+// Convert_v1alpha1_PartitionNodeNetwork_To_metal_PartitionNodeNetwork is an autogenerated conversion function.
+func Convert_v1alpha1_PartitionNodeNetwork_To_metal_PartitionNodeNetwork(in *PartitionNodeNetwork, out *metal.PartitionNodeNetwork, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PartitionNodeNetwork_To_metal_PartitionNodeNetwork(in, out, s)
+}
+
+func autoConvert_metal_PartitionNodeNetwork_To_v1alpha1_PartitionNodeNetwork(in *metal.PartitionNodeNetwork, out *PartitionNodeNetwork, s conversion.Scope) error {
+	out.PartitionID = in.PartitionID
+	out.NetworkID = in.NetworkID
+	out.Prefixes = *(*[]string)(unsafe.Pointer(&in.Prefixes))
+	return nil
+}
+
+// Convert_metal_PartitionNodeNetwork_To_v1alpha1_PartitionNodeNetwork is an autogenerated conversion function.
+func Convert_metal_PartitionNodeNetwork_To_v1alpha1_PartitionNodeNetwork(in *metal.PartitionNodeNetwork, out *PartitionNodeNetwork, s conversion.Scope) error {
+	return autoConvert_metal_PartitionNodeNetwork_To_v1alpha1_PartitionNodeNetwork(in, out, s)
+}
+
+func autoConvert_v1alpha1_RateLimit_To_metal_RateLimit(in *RateLimit, out *metal.RateLimit, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.RateLimit = in.RateLimit
+	return nil
+}
+
+// Convert_v1alpha1_RateLimit_To_metal_RateLimit is an autogenerated conversion function.
+func Convert_v1alpha1_RateLimit_To_metal_RateLimit(in *RateLimit, out *metal.RateLimit, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RateLimit_To_metal_RateLimit(in, out, s)
+}
+
+func autoConvert_metal_RateLimit_To_v1alpha1_RateLimit(in *metal.RateLimit, out *RateLimit, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.RateLimit = in.RateLimit
+	return nil
+}
+
+// Convert_metal_RateLimit_To_v1alpha1_RateLimit is an autogenerated conversion function.
+func Convert_metal_RateLimit_To_v1alpha1_RateLimit(in *metal.RateLimit, out *RateLimit, s conversion.Scope) error {
+	return autoConvert_metal_RateLimit_To_v1alpha1_RateLimit(in, out, s)
+}
+
+func autoConvert_v1alpha1_WorkerConfig_To_metal_WorkerConfig(in *WorkerConfig, out *metal.WorkerConfig, s conversion.Scope) error {
+	out.ExtraTags = *(*[]string)(unsafe.Pointer(&in.ExtraTags))
+	out.PartitionID = (*string)(unsafe.Pointer(in.PartitionID))
+	out.ImageOverrides = *(*[]metal.ImageOverride)(unsafe.Pointer(&in.ImageOverrides))
+	out.UserDataSnippets = *(*[]string)(unsafe.Pointer(&in.UserDataSnippets))
+	return nil
+}
+
+// Convert_v1alpha1_WorkerConfig_To_metal_WorkerConfig is an autogenerated conversion function.
+func Convert_v1alpha1_WorkerConfig_To_metal_WorkerConfig(in *WorkerConfig, out *metal.WorkerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_WorkerConfig_To_metal_WorkerConfig(in, out, s)
+}
+
+func autoConvert_metal_WorkerConfig_To_v1alpha1_WorkerConfig(in *metal.WorkerConfig, out *WorkerConfig, s conversion.Scope) error {
+	out.ExtraTags = *(*[]string)(unsafe.Pointer(&in.ExtraTags))
+	out.PartitionID = (*string)(unsafe.Pointer(in.PartitionID))
+	out.ImageOverrides = *(*[]ImageOverride)(unsafe.Pointer(&in.ImageOverrides))
+	out.UserDataSnippets = *(*[]string)(unsafe.Pointer(&in.UserDataSnippets))
+	return nil
+}
+
+// Convert_metal_WorkerConfig_To_v1alpha1_WorkerConfig is an autogenerated conversion function.
+func Convert_metal_WorkerConfig_To_v1alpha1_WorkerConfig(in *metal.WorkerConfig, out *WorkerConfig, s conversion.Scope) error {
+	return autoConvert_metal_WorkerConfig_To_v1alpha1_WorkerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_WorkerStatus_To_metal_WorkerStatus(in *WorkerStatus, out *metal.WorkerStatus, s conversion.Scope) error {
 	out.MachineImages = *(*[]metal.MachineImage)(unsafe.Pointer(&in.MachineImages))
 	return nil
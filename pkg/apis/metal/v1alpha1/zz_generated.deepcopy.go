@@ -79,6 +79,18 @@ func (in *CloudProfileConfig) DeepCopyInto(out *CloudProfileConfig) {
 		*out = new(IAMConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPv6Partitions != nil {
+		in, out := &in.IPv6Partitions, &out.IPv6Partitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FirewallIgnitionSnippets != nil {
+		in, out := &in.FirewallIgnitionSnippets, &out.FirewallIgnitionSnippets
+		*out = make([]FirewallIgnitionSnippets, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -100,6 +112,22 @@ func (in *CloudProfileConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectorConfig) DeepCopyInto(out *ConnectorConfig) {
 	*out = *in
@@ -151,6 +179,116 @@ func (in *ControlPlaneConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionFailure) DeepCopyInto(out *DeletionFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionFailure.
+func (in *DeletionFailure) DeepCopy() *DeletionFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPlan) DeepCopyInto(out *DeletionPlan) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	if in.Firewalls != nil {
+		in, out := &in.Firewalls, &out.Firewalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPsToFree != nil {
+		in, out := &in.IPsToFree, &out.IPsToFree
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPsToUpdate != nil {
+		in, out := &in.IPsToUpdate, &out.IPsToUpdate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPlan.
+func (in *DeletionPlan) DeepCopy() *DeletionPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionStatus) DeepCopyInto(out *DeletionStatus) {
+	*out = *in
+	if in.ReleasedIPs != nil {
+		in, out := &in.ReleasedIPs, &out.ReleasedIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReleasedNetworks != nil {
+		in, out := &in.ReleasedNetworks, &out.ReleasedNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]DeletionFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionStatus.
+func (in *DeletionStatus) DeepCopy() *DeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressRule) DeepCopyInto(out *EgressRule) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressRule.
+func (in *EgressRule) DeepCopy() *EgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Firewall) DeepCopyInto(out *Firewall) {
 	*out = *in
@@ -159,6 +297,28 @@ func (in *Firewall) DeepCopyInto(out *Firewall) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(FirewallRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressIPs != nil {
+		in, out := &in.EgressIPs, &out.EgressIPs
+		*out = make([]FirewallEgressIPs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IgnitionSnippet != nil {
+		in, out := &in.IgnitionSnippet, &out.IgnitionSnippet
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -172,9 +332,181 @@ func (in *Firewall) DeepCopy() *Firewall {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallCredentialsStatus) DeepCopyInto(out *FirewallCredentialsStatus) {
+	*out = *in
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallCredentialsStatus.
+func (in *FirewallCredentialsStatus) DeepCopy() *FirewallCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallEgressIPs) DeepCopyInto(out *FirewallEgressIPs) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallEgressIPs.
+func (in *FirewallEgressIPs) DeepCopy() *FirewallEgressIPs {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallEgressIPs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallIgnitionSnippets) DeepCopyInto(out *FirewallIgnitionSnippets) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallIgnitionSnippets.
+func (in *FirewallIgnitionSnippets) DeepCopy() *FirewallIgnitionSnippets {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallIgnitionSnippets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallNetworkStatus) DeepCopyInto(out *FirewallNetworkStatus) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallNetworkStatus.
+func (in *FirewallNetworkStatus) DeepCopy() *FirewallNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallProvisioningStatus) DeepCopyInto(out *FirewallProvisioningStatus) {
+	*out = *in
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallProvisioningStatus.
+func (in *FirewallProvisioningStatus) DeepCopy() *FirewallProvisioningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallProvisioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallRollout) DeepCopyInto(out *FirewallRollout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallRollout.
+func (in *FirewallRollout) DeepCopy() *FirewallRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallRules) DeepCopyInto(out *FirewallRules) {
+	*out = *in
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]EgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]IngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = make([]RateLimit, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallRules.
+func (in *FirewallRules) DeepCopy() *FirewallRules {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FirewallStatus) DeepCopyInto(out *FirewallStatus) {
 	*out = *in
+	if in.AllocationTimestamp != nil {
+		in, out := &in.AllocationTimestamp, &out.AllocationTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]FirewallNetworkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -240,11 +572,42 @@ func (in *IDMConfig) DeepCopy() *IDMConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverride) DeepCopyInto(out *ImageOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverride.
+func (in *ImageOverride) DeepCopy() *ImageOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfrastructureConfig) DeepCopyInto(out *InfrastructureConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.Firewall.DeepCopyInto(&out.Firewall)
+	if in.NodeNetworkID != nil {
+		in, out := &in.NodeNetworkID, &out.NodeNetworkID
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeNetwork != nil {
+		in, out := &in.NodeNetwork, &out.NodeNetwork
+		*out = new(NodeNetwork)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalPartitionIDs != nil {
+		in, out := &in.AdditionalPartitionIDs, &out.AdditionalPartitionIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -270,7 +633,62 @@ func (in *InfrastructureConfig) DeepCopyObject() runtime.Object {
 func (in *InfrastructureStatus) DeepCopyInto(out *InfrastructureStatus) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.Firewall = in.Firewall
+	if in.Firewalls != nil {
+		in, out := &in.Firewalls, &out.Firewalls
+		*out = make([]FirewallStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(FirewallStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(FirewallRollout)
+		**out = **in
+	}
+	if in.NodeNetworkPrefixes != nil {
+		in, out := &in.NodeNetworkPrefixes, &out.NodeNetworkPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalNodeNetworks != nil {
+		in, out := &in.AdditionalNodeNetworks, &out.AdditionalNodeNetworks
+		*out = make([]PartitionNodeNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionPlan != nil {
+		in, out := &in.DeletionPlan, &out.DeletionPlan
+		*out = new(DeletionPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EgressIPs != nil {
+		in, out := &in.EgressIPs, &out.EgressIPs
+		*out = make([]FirewallEgressIPs, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FirewallCredentials != nil {
+		in, out := &in.FirewallCredentials, &out.FirewallCredentials
+		*out = new(FirewallCredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FirewallProvisioning != nil {
+		in, out := &in.FirewallProvisioning, &out.FirewallProvisioning
+		*out = new(FirewallProvisioningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(DeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -292,6 +710,32 @@ func (in *InfrastructureStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
+func (in *IngressRule) DeepCopy() *IngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IssuerConfig) DeepCopyInto(out *IssuerConfig) {
 	*out = *in
@@ -324,6 +768,114 @@ func (in *NamespaceGroupConfig) DeepCopy() *NamespaceGroupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetwork) DeepCopyInto(out *NodeNetwork) {
+	*out = *in
+	if in.PrefixLength != nil {
+		in, out := &in.PrefixLength, &out.PrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdditionalPrefix != nil {
+		in, out := &in.AdditionalPrefix, &out.AdditionalPrefix
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetwork.
+func (in *NodeNetwork) DeepCopy() *NodeNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionNodeNetwork) DeepCopyInto(out *PartitionNodeNetwork) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionNodeNetwork.
+func (in *PartitionNodeNetwork) DeepCopy() *PartitionNodeNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionNodeNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.ExtraTags != nil {
+		in, out := &in.ExtraTags, &out.ExtraTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PartitionID != nil {
+		in, out := &in.PartitionID, &out.PartitionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make([]ImageOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserDataSnippets != nil {
+		in, out := &in.UserDataSnippets, &out.UserDataSnippets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerConfig.
+func (in *WorkerConfig) DeepCopy() *WorkerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkerConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerStatus) DeepCopyInto(out *WorkerStatus) {
 	*out = *in
@@ -12,5 +12,10 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&InfrastructureConfig{}, func(obj interface{}) { SetObjectDefaults_InfrastructureConfig(obj.(*InfrastructureConfig)) })
 	return nil
 }
+
+func SetObjectDefaults_InfrastructureConfig(in *InfrastructureConfig) {
+	SetDefaults_InfrastructureConfig(in)
+}
@@ -42,5 +42,21 @@ func ValidateCloudProfileConfig(cloudProfileConfig *apismetal.CloudProfileConfig
 		}
 	}
 
+	firewallIgnitionSnippetsPath := field.NewPath("firewallIgnitionSnippets")
+	for i, snippets := range cloudProfileConfig.FirewallIgnitionSnippets {
+		snippetsPath := firewallIgnitionSnippetsPath.Index(i)
+		if snippets.ConfigMapRef.Name == "" {
+			allErrs = append(allErrs, field.Required(snippetsPath.Child("configMapRef", "name"), "name of the config map must be specified"))
+		}
+		if snippets.ConfigMapRef.Namespace == "" {
+			allErrs = append(allErrs, field.Required(snippetsPath.Child("configMapRef", "namespace"), "namespace of the config map must be specified"))
+		}
+		for j, partitionID := range snippets.Partitions {
+			if !availableZones.Has(partitionID) {
+				allErrs = append(allErrs, field.Invalid(snippetsPath.Child("partitions").Index(j), partitionID, fmt.Sprintf("the partition of the ignition snippets must be contained in the configured zones in the cloud profile: %v", availableZones.List())))
+			}
+		}
+	}
+
 	return allErrs
 }
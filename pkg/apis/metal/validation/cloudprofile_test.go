@@ -92,5 +92,43 @@ var _ = Describe("CloudProfileConfig validation", func() {
 				"Detail": Equal("the partition of the firewall network must be contained in the configured zones in the cloud profile: [partition-a partition-b partition-c]"),
 			}))
 		})
+
+		It("should pass properly configured firewall ignition snippets", func() {
+			cloudProfileConfig.FirewallIgnitionSnippets = []apismetal.FirewallIgnitionSnippets{
+				{
+					ConfigMapRef: apismetal.ConfigMapReference{Name: "snippets", Namespace: "garden"},
+					Partitions:   []string{"partition-a"},
+				},
+			}
+
+			errorList := ValidateCloudProfileConfig(cloudProfileConfig, cloudProfile)
+
+			Expect(errorList).To(BeEmpty())
+		})
+
+		It("should prevent firewall ignition snippets without config map reference or with unknown partitions", func() {
+			cloudProfileConfig.FirewallIgnitionSnippets = []apismetal.FirewallIgnitionSnippets{
+				{
+					Partitions: []string{"random-partition"},
+				},
+			}
+
+			errorList := ValidateCloudProfileConfig(cloudProfileConfig, cloudProfile)
+
+			Expect(errorList).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("firewallIgnitionSnippets[0].configMapRef.name"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("firewallIgnitionSnippets[0].configMapRef.namespace"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("firewallIgnitionSnippets[0].partitions[0]"),
+				})),
+			))
+		})
 	})
 })
@@ -2,12 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 
 	"github.com/gardener/gardener/pkg/apis/core"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/ignition"
 
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -32,6 +35,14 @@ func ValidateInfrastructureConfigAgainstCloudProfile(infra *apismetal.Infrastruc
 		allErrs = append(allErrs, field.Required(firewallPath.Child("networks"), "at least one external network needs to be defined as otherwise the cluster will under no circumstances be able to bootstrap"))
 	}
 
+	if infra.NodeNetwork != nil && infra.NodeNetwork.DualStack {
+		for _, partitionID := range helper.PartitionIDs(infra) {
+			if cloudProfileConfig == nil || !sets.NewString(cloudProfileConfig.IPv6Partitions...).Has(partitionID) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeNetwork", "dualStack"), fmt.Sprintf("partition %q does not offer dual-stack node networks", partitionID)))
+			}
+		}
+	}
+
 	if cloudProfileConfig == nil {
 		return allErrs
 	}
@@ -64,6 +75,11 @@ func validateInfrastructureConfigZones(infra *apismetal.InfrastructureConfig, zo
 	if !availableZones.Has(infra.PartitionID) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("partitionID"), infra.PartitionID, fmt.Sprintf("supported values: %v", availableZones.UnsortedList())))
 	}
+	for i, partitionID := range infra.AdditionalPartitionIDs {
+		if !availableZones.Has(partitionID) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalPartitionIDs").Index(i), partitionID, fmt.Sprintf("supported values: %v", availableZones.UnsortedList())))
+		}
+	}
 
 	return allErrs
 }
@@ -79,6 +95,18 @@ func ValidateInfrastructureConfig(infra *apismetal.InfrastructureConfig) field.E
 		allErrs = append(allErrs, field.Required(field.NewPath("partitionID"), "partitionID must be specified"))
 	}
 
+	if infra.NodeNetworkID != nil && *infra.NodeNetworkID == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("nodeNetworkID"), "nodeNetworkID must not be an empty string"))
+	}
+
+	if infra.NodeNetwork != nil {
+		allErrs = append(allErrs, validateNodeNetwork(infra, field.NewPath("nodeNetwork"))...)
+	}
+
+	if len(infra.AdditionalPartitionIDs) > 0 {
+		allErrs = append(allErrs, validateAdditionalPartitions(infra, field.NewPath("additionalPartitionIDs"))...)
+	}
+
 	firewallPath := field.NewPath("firewall")
 	if infra.Firewall.Image == "" {
 		allErrs = append(allErrs, field.Required(firewallPath.Child("image"), "firewall image must be specified"))
@@ -91,16 +119,264 @@ func ValidateInfrastructureConfig(infra *apismetal.InfrastructureConfig) field.E
 			allErrs = append(allErrs, field.Required(firewallPath.Child("networks").Index(i), "firewall network must not be an empty string"))
 		}
 	}
+	if infra.Firewall.Replicas != nil && *infra.Firewall.Replicas < 1 {
+		allErrs = append(allErrs, field.Invalid(firewallPath.Child("replicas"), *infra.Firewall.Replicas, "firewall replicas must be at least one"))
+	}
+	if infra.Firewall.Rules != nil {
+		allErrs = append(allErrs, validateFirewallRules(infra.Firewall.Rules, infra.Firewall.Networks, firewallPath.Child("rules"))...)
+	}
+	if len(infra.Firewall.EgressIPs) > 0 {
+		allErrs = append(allErrs, validateFirewallEgressIPs(infra.Firewall, firewallPath)...)
+	}
+	if infra.Firewall.IgnitionSnippet != nil {
+		if _, err := ignition.ParseSnippet(*infra.Firewall.IgnitionSnippet); err != nil {
+			allErrs = append(allErrs, field.Invalid(firewallPath.Child("ignitionSnippet"), *infra.Firewall.IgnitionSnippet, fmt.Sprintf("must be a valid container linux config snippet: %v", err)))
+		}
+	}
 
 	return allErrs
 }
 
+// validateNodeNetwork validates the node network configuration of the given `InfrastructureConfig`.
+func validateNodeNetwork(infra *apismetal.InfrastructureConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	nodeNetwork := infra.NodeNetwork
+
+	if nodeNetwork.PrefixLength != nil {
+		if infra.NodeNetworkID != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("prefixLength"), "prefix length cannot be requested for an existing node network"))
+		} else if *nodeNetwork.PrefixLength < 8 || *nodeNetwork.PrefixLength > 30 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("prefixLength"), *nodeNetwork.PrefixLength, "prefix length must be between 8 and 30"))
+		}
+	}
+
+	if nodeNetwork.AdditionalPrefix != nil {
+		if _, _, err := net.ParseCIDR(*nodeNetwork.AdditionalPrefix); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalPrefix"), *nodeNetwork.AdditionalPrefix, "must be a valid cidr"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAdditionalPartitions validates the additional partitions of the given `InfrastructureConfig`.
+func validateAdditionalPartitions(infra *apismetal.InfrastructureConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	partitions := sets.NewString(infra.PartitionID)
+	for i, partitionID := range infra.AdditionalPartitionIDs {
+		if partitionID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "additional partition must not be an empty string"))
+		} else if partitions.Has(partitionID) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), partitionID))
+		}
+		partitions.Insert(partitionID)
+	}
+
+	if infra.NodeNetworkID != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "an existing node network can only be used in a single partition"))
+	}
+	if infra.NodeNetwork != nil && infra.NodeNetwork.AdditionalPrefix != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "an additional node network prefix can only be added in a single partition"))
+	}
+	if len(infra.Firewall.EgressIPs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "static egress ips can only be attached to the firewall of a single partition"))
+	}
+
+	return allErrs
+}
+
+// ValidateInfrastructureConfigAgainstNetworking validates the node network of the given `InfrastructureConfig` against
+// the networking of the shoot.
+func ValidateInfrastructureConfigAgainstNetworking(infra *apismetal.InfrastructureConfig, networking core.Networking, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the metal-api allocates the node networks of the additional partitions from the private super networks of the
+	// partitions, their nodes would get addresses outside of the nodes cidr which the cloud-controller-manager does not
+	// know about
+	if len(infra.AdditionalPartitionIDs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalPartitionIDs"), "the node networks of additional partitions are not part of the nodes cidr of the shoot"))
+	}
+
+	if infra.NodeNetwork == nil {
+		return allErrs
+	}
+
+	nodeNetworkPath := fldPath.Child("nodeNetwork")
+
+	if infra.NodeNetwork.PrefixLength != nil && networking.Nodes != nil {
+		allErrs = append(allErrs, field.Forbidden(nodeNetworkPath.Child("prefixLength"), "prefix length cannot be requested if the nodes cidr of the shoot is set"))
+	}
+
+	if infra.NodeNetwork.AdditionalPrefix == nil {
+		return allErrs
+	}
+
+	_, additionalPrefix, err := net.ParseCIDR(*infra.NodeNetwork.AdditionalPrefix)
+	if err != nil {
+		// reported by ValidateInfrastructureConfig
+		return allErrs
+	}
+
+	shootCIDRs := []struct {
+		name string
+		cidr *string
+	}{
+		{name: "pods", cidr: networking.Pods},
+		{name: "services", cidr: networking.Services},
+	}
+	for _, c := range shootCIDRs {
+		if c.cidr == nil {
+			continue
+		}
+		_, other, err := net.ParseCIDR(*c.cidr)
+		if err != nil {
+			continue
+		}
+		if additionalPrefix.Contains(other.IP) || other.Contains(additionalPrefix.IP) {
+			allErrs = append(allErrs, field.Invalid(nodeNetworkPath.Child("additionalPrefix"), *infra.NodeNetwork.AdditionalPrefix, fmt.Sprintf("must not overlap with the %s cidr %s", c.name, *c.cidr)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFirewallEgressIPs validates the static egress IPs of the given firewall.
+func validateFirewallEgressIPs(firewall apismetal.Firewall, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if firewall.Replicas != nil && *firewall.Replicas > 1 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("egressIPs"), "static egress ips can only be attached to a single firewall replica"))
+	}
+
+	firewallNetworks := sets.NewString(firewall.Networks...)
+	egressNetworks := sets.NewString()
+	ips := sets.NewString()
+	for i, egress := range firewall.EgressIPs {
+		egressPath := fldPath.Child("egressIPs").Index(i)
+		if !firewallNetworks.Has(egress.NetworkID) {
+			allErrs = append(allErrs, field.Invalid(egressPath.Child("networkID"), egress.NetworkID, fmt.Sprintf("network must be one of the firewall networks: %v", firewall.Networks)))
+		} else if egressNetworks.Has(egress.NetworkID) {
+			allErrs = append(allErrs, field.Duplicate(egressPath.Child("networkID"), egress.NetworkID))
+		}
+		egressNetworks.Insert(egress.NetworkID)
+
+		for j, ip := range egress.IPs {
+			ipPath := egressPath.Child("ips").Index(j)
+			if net.ParseIP(ip) == nil {
+				allErrs = append(allErrs, field.Invalid(ipPath, ip, "must be a valid ip address"))
+			} else if ips.Has(ip) {
+				allErrs = append(allErrs, field.Duplicate(ipPath, ip))
+			}
+			ips.Insert(ip)
+		}
+	}
+
+	return allErrs
+}
+
+// validateFirewallRules validates the given `FirewallRules` against the networks of the firewall.
+func validateFirewallRules(rules *apismetal.FirewallRules, networks []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	firewallNetworks := sets.NewString(networks...)
+
+	for i, rule := range rules.Egress {
+		rulePath := fldPath.Child("egress").Index(i)
+		allErrs = append(allErrs, validateFirewallProtocol(rule.Protocol, rulePath.Child("protocol"))...)
+		if len(rule.CIDRs) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("cidrs"), "at least one destination cidr must be specified"))
+		}
+		allErrs = append(allErrs, validateFirewallCIDRs(rule.CIDRs, rulePath.Child("cidrs"))...)
+		allErrs = append(allErrs, validateFirewallPorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+
+	for i, rule := range rules.Ingress {
+		rulePath := fldPath.Child("ingress").Index(i)
+		if !firewallNetworks.Has(rule.NetworkID) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("networkID"), rule.NetworkID, fmt.Sprintf("network must be one of the firewall networks: %v", networks)))
+		}
+		allErrs = append(allErrs, validateFirewallProtocol(rule.Protocol, rulePath.Child("protocol"))...)
+		allErrs = append(allErrs, validateFirewallCIDRs(rule.CIDRs, rulePath.Child("cidrs"))...)
+		allErrs = append(allErrs, validateFirewallPorts(rule.Ports, rulePath.Child("ports"))...)
+	}
+
+	limitedNetworks := sets.NewString()
+	for i, limit := range rules.RateLimits {
+		limitPath := fldPath.Child("rateLimits").Index(i)
+		if !firewallNetworks.Has(limit.NetworkID) {
+			allErrs = append(allErrs, field.Invalid(limitPath.Child("networkID"), limit.NetworkID, fmt.Sprintf("network must be one of the firewall networks: %v", networks)))
+		} else if limitedNetworks.Has(limit.NetworkID) {
+			allErrs = append(allErrs, field.Duplicate(limitPath.Child("networkID"), limit.NetworkID))
+		}
+		limitedNetworks.Insert(limit.NetworkID)
+		if limit.RateLimit == 0 {
+			allErrs = append(allErrs, field.Invalid(limitPath.Child("rateLimit"), limit.RateLimit, "rate limit must be greater than zero"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateFirewallProtocol(protocol apismetal.FirewallProtocol, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch protocol {
+	case apismetal.FirewallProtocolTCP, apismetal.FirewallProtocolUDP:
+	case "":
+		allErrs = append(allErrs, field.Required(fldPath, "protocol must be specified"))
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, protocol, []string{string(apismetal.FirewallProtocolTCP), string(apismetal.FirewallProtocolUDP)}))
+	}
+
+	return allErrs
+}
+
+func validateFirewallCIDRs(cidrs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, "must be a valid cidr"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateFirewallPorts(ports []int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, port := range ports {
+		if port < 1 || port > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), port, "port must be between 1 and 65535"))
+		}
+	}
+
+	return allErrs
+}
+
+func isDualStack(infra *apismetal.InfrastructureConfig) bool {
+	return infra.NodeNetwork != nil && infra.NodeNetwork.DualStack
+}
+
 // ValidateInfrastructureConfigUpdate validates a InfrastructureConfig object.
 func ValidateInfrastructureConfigUpdate(oldConfig, newConfig *apismetal.InfrastructureConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.ProjectID, oldConfig.ProjectID, field.NewPath("projectID"))...)
 	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.PartitionID, oldConfig.PartitionID, field.NewPath("partitionID"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newConfig.NodeNetworkID, oldConfig.NodeNetworkID, field.NewPath("nodeNetworkID"))...)
+
+	// the node networks and firewalls of removed partitions would not be released
+	newPartitions := sets.NewString(newConfig.AdditionalPartitionIDs...)
+	for _, partitionID := range oldConfig.AdditionalPartitionIDs {
+		if !newPartitions.Has(partitionID) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("additionalPartitionIDs"), fmt.Sprintf("partition %q cannot be removed from the cluster", partitionID)))
+		}
+	}
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(isDualStack(newConfig), isDualStack(oldConfig), field.NewPath("nodeNetwork", "dualStack"))...)
 
 	var oldNetworks []string
 	for _, network := range oldConfig.Firewall.Networks {
@@ -86,6 +86,16 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				}))))
 			})
 
+			It("should forbid additional partitions which are not specified in CloudProfile", func() {
+				infrastructureConfig.AdditionalPartitionIDs = []string{"partition-b", "not-available"}
+				errorList := ValidateInfrastructureConfigAgainstCloudProfile(infrastructureConfig, shoot, cloudProfile, cloudProfileConfig, field.NewPath("spec"))
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("spec.additionalPartitionIDs[1]"),
+				}))
+			})
+
 			It("should forbid firewall image is not specified in CloudProfileConfig", func() {
 				infrastructureConfig.Firewall.Image = "no-image"
 				errorList := ValidateInfrastructureConfigAgainstCloudProfile(infrastructureConfig, shoot, cloudProfile, cloudProfileConfig, field.NewPath("spec"))
@@ -97,6 +107,25 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				}))))
 			})
 
+			It("should forbid dual-stack node networks in partitions without IPv6", func() {
+				infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{DualStack: true}
+				errorList := ValidateInfrastructureConfigAgainstCloudProfile(infrastructureConfig, shoot, cloudProfile, cloudProfileConfig, field.NewPath("spec"))
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("spec.nodeNetwork.dualStack"),
+					"Detail": Equal("partition \"partition-a\" does not offer dual-stack node networks"),
+				}))
+			})
+
+			It("should allow dual-stack node networks in partitions with IPv6", func() {
+				infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{DualStack: true}
+				cloudProfileConfig.IPv6Partitions = []string{"partition-a"}
+				errorList := ValidateInfrastructureConfigAgainstCloudProfile(infrastructureConfig, shoot, cloudProfile, cloudProfileConfig, field.NewPath("spec"))
+
+				Expect(errorList).To(BeEmpty())
+			})
+
 			It("should forbid because no firewall networks given", func() {
 				infrastructureConfig.Firewall.Networks = nil
 				errorList := ValidateInfrastructureConfigAgainstCloudProfile(infrastructureConfig, shoot, cloudProfile, cloudProfileConfig, field.NewPath("spec"))
@@ -136,6 +165,19 @@ var _ = Describe("InfrastructureConfig validation", func() {
 				}))
 			})
 
+			It("should forbid empty node network id", func() {
+				nodeNetworkID := ""
+				infrastructureConfig.NodeNetworkID = &nodeNetworkID
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeRequired),
+					"Field":  Equal("nodeNetworkID"),
+					"Detail": Equal("nodeNetworkID must not be an empty string"),
+				}))
+			})
+
 			It("should forbid empty firewall image", func() {
 				infrastructureConfig.Firewall.Image = ""
 
@@ -171,6 +213,387 @@ var _ = Describe("InfrastructureConfig validation", func() {
 					"Detail": Equal("firewall network must not be an empty string"),
 				}))
 			})
+
+			It("should forbid less than one firewall replica", func() {
+				replicas := int32(0)
+				infrastructureConfig.Firewall.Replicas = &replicas
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.replicas"),
+					"Detail": Equal("firewall replicas must be at least one"),
+				}))
+			})
+
+			It("should allow multiple firewall replicas", func() {
+				replicas := int32(2)
+				infrastructureConfig.Firewall.Replicas = &replicas
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+		})
+
+		Context("Additional partitions", func() {
+			It("should allow spreading the cluster over multiple partitions", func() {
+				infrastructureConfig.AdditionalPartitionIDs = []string{"partition-b", "partition-c"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid empty and duplicate partitions", func() {
+				infrastructureConfig.AdditionalPartitionIDs = []string{"", "partition-a"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("additionalPartitionIDs[0]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("additionalPartitionIDs[1]"),
+				}))
+			})
+
+			It("should forbid an existing node network and static egress ips", func() {
+				nodeNetworkID := "network-1"
+				infrastructureConfig.NodeNetworkID = &nodeNetworkID
+				infrastructureConfig.Firewall.EgressIPs = []apismetal.FirewallEgressIPs{{NetworkID: "internet"}}
+				infrastructureConfig.AdditionalPartitionIDs = []string{"partition-b"}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("additionalPartitionIDs"),
+					"Detail": Equal("an existing node network can only be used in a single partition"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeForbidden),
+					"Field":  Equal("additionalPartitionIDs"),
+					"Detail": Equal("static egress ips can only be attached to the firewall of a single partition"),
+				}))
+			})
+		})
+
+		Context("Node network", func() {
+			It("should allow valid node network configuration", func() {
+				prefixLength := int32(22)
+				additionalPrefix := "10.1.0.0/24"
+				infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+					PrefixLength:     &prefixLength,
+					AdditionalPrefix: &additionalPrefix,
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid invalid node network configuration", func() {
+				prefixLength := int32(31)
+				additionalPrefix := "10.1.0.0"
+				infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+					PrefixLength:     &prefixLength,
+					AdditionalPrefix: &additionalPrefix,
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("nodeNetwork.prefixLength"),
+					"Detail": Equal("prefix length must be between 8 and 30"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("nodeNetwork.additionalPrefix"),
+					"Detail": Equal("must be a valid cidr"),
+				}))
+			})
+
+			It("should forbid requesting a prefix length for an existing node network", func() {
+				prefixLength := int32(22)
+				nodeNetworkID := "network-1"
+				infrastructureConfig.NodeNetworkID = &nodeNetworkID
+				infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+					PrefixLength: &prefixLength,
+				}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("nodeNetwork.prefixLength"),
+				}))
+			})
+		})
+
+		Context("Firewall rules", func() {
+			BeforeEach(func() {
+				infrastructureConfig.Firewall.Rules = &apismetal.FirewallRules{
+					Egress: []apismetal.EgressRule{
+						{
+							Protocol: apismetal.FirewallProtocolTCP,
+							CIDRs:    []string{"1.2.3.0/24"},
+							Ports:    []int32{443},
+						},
+					},
+					Ingress: []apismetal.IngressRule{
+						{
+							NetworkID: "internet",
+							Protocol:  apismetal.FirewallProtocolUDP,
+							Ports:     []int32{53},
+						},
+					},
+					RateLimits: []apismetal.RateLimit{
+						{
+							NetworkID: "internet",
+							RateLimit: 100,
+						},
+					},
+				}
+			})
+
+			It("should allow valid rules", func() {
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid invalid egress rules", func() {
+				infrastructureConfig.Firewall.Rules.Egress[0].Protocol = "ICMP"
+				infrastructureConfig.Firewall.Rules.Egress[0].CIDRs = []string{"1.2.3.4"}
+				infrastructureConfig.Firewall.Rules.Egress[0].Ports = []int32{0}
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("firewall.rules.egress[0].protocol"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.rules.egress[0].cidrs[0]"),
+					"Detail": Equal("must be a valid cidr"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.rules.egress[0].ports[0]"),
+					"Detail": Equal("port must be between 1 and 65535"),
+				}))
+			})
+
+			It("should forbid egress rules without destination", func() {
+				infrastructureConfig.Firewall.Rules.Egress[0].CIDRs = nil
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeRequired),
+					"Field":  Equal("firewall.rules.egress[0].cidrs"),
+					"Detail": Equal("at least one destination cidr must be specified"),
+				}))
+			})
+
+			It("should forbid ingress rules for networks not attached to the firewall", func() {
+				infrastructureConfig.Firewall.Rules.Ingress[0].NetworkID = "mpls"
+				infrastructureConfig.Firewall.Rules.Ingress[0].Protocol = ""
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.rules.ingress[0].networkID"),
+					"Detail": Equal("network must be one of the firewall networks: [internet]"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeRequired),
+					"Field":  Equal("firewall.rules.ingress[0].protocol"),
+					"Detail": Equal("protocol must be specified"),
+				}))
+			})
+
+			It("should forbid invalid rate limits", func() {
+				infrastructureConfig.Firewall.Rules.RateLimits = append(infrastructureConfig.Firewall.Rules.RateLimits, apismetal.RateLimit{
+					NetworkID: "internet",
+				}, apismetal.RateLimit{
+					NetworkID: "mpls",
+					RateLimit: 10,
+				})
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.rules.rateLimits[2].networkID"),
+					"Detail": Equal("network must be one of the firewall networks: [internet]"),
+				}, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("firewall.rules.rateLimits[1].networkID"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.rules.rateLimits[1].rateLimit"),
+					"Detail": Equal("rate limit must be greater than zero"),
+				}))
+			})
+		})
+
+		Context("Firewall egress ips", func() {
+			BeforeEach(func() {
+				infrastructureConfig.Firewall.EgressIPs = []apismetal.FirewallEgressIPs{
+					{
+						NetworkID: "internet",
+						IPs:       []string{"212.1.2.3"},
+					},
+				}
+			})
+
+			It("should allow valid egress ips", func() {
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should allow requesting the allocation of an egress ip", func() {
+				infrastructureConfig.Firewall.EgressIPs[0].IPs = nil
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid invalid egress ips", func() {
+				infrastructureConfig.Firewall.EgressIPs[0].IPs = []string{"212.1.2.3", "212.1.2.3", "1.2.3.0/24"}
+				infrastructureConfig.Firewall.EgressIPs = append(infrastructureConfig.Firewall.EgressIPs, apismetal.FirewallEgressIPs{
+					NetworkID: "mpls",
+				})
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("firewall.egressIPs[0].ips[1]"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.egressIPs[0].ips[2]"),
+					"Detail": Equal("must be a valid ip address"),
+				}, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("firewall.egressIPs[1].networkID"),
+					"Detail": Equal("network must be one of the firewall networks: [internet]"),
+				}))
+			})
+
+			It("should forbid egress ips with multiple firewall replicas", func() {
+				replicas := int32(2)
+				infrastructureConfig.Firewall.Replicas = &replicas
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("firewall.egressIPs"),
+				}))
+			})
+		})
+
+		Context("ignition snippet", func() {
+			It("should pass a valid ignition snippet", func() {
+				snippet := "storage:\n  files:\n  - path: /etc/motd\n    filesystem: root\n    contents:\n      inline: hello\n"
+				infrastructureConfig.Firewall.IgnitionSnippet = &snippet
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(BeEmpty())
+			})
+
+			It("should forbid snippets with sections other than files and units", func() {
+				snippet := "passwd:\n  users:\n  - name: core\n"
+				infrastructureConfig.Firewall.IgnitionSnippet = &snippet
+
+				errorList := ValidateInfrastructureConfig(infrastructureConfig)
+
+				Expect(errorList).To(ConsistOfFields(Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("firewall.ignitionSnippet"),
+				}))
+			})
+		})
+	})
+
+	Describe("#ValidateInfrastructureConfigAgainstNetworking", func() {
+		var (
+			networking core.Networking
+		)
+
+		BeforeEach(func() {
+			pods := "10.240.0.0/13"
+			services := "10.248.0.0/18"
+			networking = core.Networking{
+				Pods:     &pods,
+				Services: &services,
+			}
+		})
+
+		It("should allow an additional prefix which does not overlap", func() {
+			additionalPrefix := "10.1.0.0/24"
+			infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+				AdditionalPrefix: &additionalPrefix,
+			}
+
+			errorList := ValidateInfrastructureConfigAgainstNetworking(infrastructureConfig, networking, field.NewPath("spec"))
+
+			Expect(errorList).To(BeEmpty())
+		})
+
+		It("should forbid an additional prefix overlapping with pods and services", func() {
+			additionalPrefix := "10.240.0.0/12"
+			infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+				AdditionalPrefix: &additionalPrefix,
+			}
+
+			errorList := ValidateInfrastructureConfigAgainstNetworking(infrastructureConfig, networking, field.NewPath("spec"))
+
+			Expect(errorList).To(ConsistOfFields(Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("spec.nodeNetwork.additionalPrefix"),
+				"Detail": Equal("must not overlap with the pods cidr 10.240.0.0/13"),
+			}, Fields{
+				"Type":   Equal(field.ErrorTypeInvalid),
+				"Field":  Equal("spec.nodeNetwork.additionalPrefix"),
+				"Detail": Equal("must not overlap with the services cidr 10.248.0.0/18"),
+			}))
+		})
+
+		It("should forbid additional partitions", func() {
+			nodes := "10.250.0.0/16"
+			networking.Nodes = &nodes
+			infrastructureConfig.AdditionalPartitionIDs = []string{"partition-b"}
+
+			errorList := ValidateInfrastructureConfigAgainstNetworking(infrastructureConfig, networking, field.NewPath("spec"))
+
+			Expect(errorList).To(ConsistOfFields(Fields{
+				"Type":   Equal(field.ErrorTypeForbidden),
+				"Field":  Equal("spec.additionalPartitionIDs"),
+				"Detail": Equal("the node networks of additional partitions are not part of the nodes cidr of the shoot"),
+			}))
+		})
+
+		It("should forbid a prefix length if the nodes cidr is set", func() {
+			nodes := "10.250.0.0/16"
+			prefixLength := int32(22)
+			networking.Nodes = &nodes
+			infrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{
+				PrefixLength: &prefixLength,
+			}
+
+			errorList := ValidateInfrastructureConfigAgainstNetworking(infrastructureConfig, networking, field.NewPath("spec"))
+
+			Expect(errorList).To(ConsistOfFields(Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("spec.nodeNetwork.prefixLength"),
+			}))
 		})
 	})
 
@@ -203,6 +626,51 @@ var _ = Describe("InfrastructureConfig validation", func() {
 			}))))
 		})
 
+		It("should not allow changing node network", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			nodeNetworkID := "network-1"
+			newInfrastructureConfig.NodeNetworkID = &nodeNetworkID
+
+			errorList := ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("nodeNetworkID"),
+			}))))
+		})
+
+		It("should not allow enabling dual-stack", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.NodeNetwork = &apismetal.NodeNetwork{DualStack: true}
+
+			errorList := ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("nodeNetwork.dualStack"),
+			}))))
+		})
+
+		It("should allow adding partitions", func() {
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.AdditionalPartitionIDs = []string{"partition-b"}
+
+			Expect(ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig)).To(BeEmpty())
+		})
+
+		It("should not allow removing partitions", func() {
+			infrastructureConfig.AdditionalPartitionIDs = []string{"partition-b"}
+			newInfrastructureConfig := infrastructureConfig.DeepCopy()
+			newInfrastructureConfig.AdditionalPartitionIDs = nil
+
+			errorList := ValidateInfrastructureConfigUpdate(infrastructureConfig, newInfrastructureConfig)
+
+			Expect(errorList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeForbidden),
+				"Field": Equal("additionalPartitionIDs"),
+			}))))
+		})
+
 		It("should not allow adding networks", func() {
 			newInfrastructureConfig := infrastructureConfig.DeepCopy()
 			newInfrastructureConfig.Firewall.Networks = append(newInfrastructureConfig.Firewall.Networks, "b")
@@ -6,14 +6,19 @@ import (
 
 	"github.com/gardener/gardener/pkg/apis/core"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-// ValidateWorkers validates the workers of a Shoot.
-func ValidateWorkers(workers []core.Worker, cloudProfile *gardencorev1beta1.CloudProfile, fldPath *field.Path) field.ErrorList {
+// ValidateWorkers validates the workers of a Shoot. The zones of the workers must be partitions of the given
+// `InfrastructureConfig`.
+func ValidateWorkers(workers []core.Worker, infra *apismetal.InfrastructureConfig, cloudProfile *gardencorev1beta1.CloudProfile, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	partitions := helper.PartitionIDs(infra)
+
 	availableImages := sets.NewString()
 	for _, image := range cloudProfile.Spec.MachineImages {
 		for _, version := range image.Versions {
@@ -26,8 +31,15 @@ func ValidateWorkers(workers []core.Worker, cloudProfile *gardencorev1beta1.Clou
 			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("volume"), "volumes are not yet supported and must be nil"))
 		}
 
-		if len(worker.Zones) != 0 {
-			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("zones"), "zone spreading is not supported, specify partition via infrastructure config"))
+		zones := sets.NewString()
+		for j, zone := range worker.Zones {
+			zonePath := fldPath.Index(i).Child("zones").Index(j)
+			if !sets.NewString(partitions...).Has(zone) {
+				allErrs = append(allErrs, field.NotSupported(zonePath, zone, partitions))
+			} else if zones.Has(zone) {
+				allErrs = append(allErrs, field.Duplicate(zonePath, zone))
+			}
+			zones.Insert(zone)
 		}
 
 		wantedImage := worker.Machine.Image.Name + "-" + worker.Machine.Image.Version
@@ -3,6 +3,7 @@ package validation_test
 import (
 	"github.com/gardener/gardener/pkg/apis/core"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
 	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -16,11 +17,16 @@ import (
 var _ = Describe("Shoot validation", func() {
 	Describe("#ValidateWorkerConfig", func() {
 		var (
-			cloudProfile *gardencorev1beta1.CloudProfile
-			workers      []core.Worker
+			infrastructureConfig *apismetal.InfrastructureConfig
+			cloudProfile         *gardencorev1beta1.CloudProfile
+			workers              []core.Worker
 		)
 
 		BeforeEach(func() {
+			infrastructureConfig = &apismetal.InfrastructureConfig{
+				PartitionID:            "partition-a",
+				AdditionalPartitionIDs: []string{"partition-b"},
+			}
 			cloudProfile = &gardencorev1beta1.CloudProfile{
 				Spec: gardencorev1beta1.CloudProfileSpec{
 					MachineImages: []gardencorev1beta1.MachineImage{
@@ -52,7 +58,7 @@ var _ = Describe("Shoot validation", func() {
 		})
 
 		It("should pass because workers are configured correctly", func() {
-			errorList := ValidateWorkers(workers, cloudProfile, field.NewPath("workers"))
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
 
 			Expect(errorList).To(BeEmpty())
 		})
@@ -62,7 +68,7 @@ var _ = Describe("Shoot validation", func() {
 				Type: strPtr("fancy-storage"),
 			}
 
-			errorList := ValidateWorkers(workers, cloudProfile, field.NewPath("workers"))
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
 
 			Expect(errorList).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
@@ -73,16 +79,27 @@ var _ = Describe("Shoot validation", func() {
 			))
 		})
 
-		It("zones must be empty", func() {
-			workers[0].Zones = []string{"a"}
+		It("should allow spreading workers over the partitions of the cluster", func() {
+			workers[0].Zones = []string{"partition-a", "partition-b"}
+
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
+
+			Expect(errorList).To(BeEmpty())
+		})
 
-			errorList := ValidateWorkers(workers, cloudProfile, field.NewPath("workers"))
+		It("zones must be partitions of the cluster", func() {
+			workers[0].Zones = []string{"partition-a", "partition-c", "partition-a"}
+
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
 
 			Expect(errorList).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
-					"Type":   Equal(field.ErrorTypeRequired),
-					"Field":  Equal("workers[0].zones"),
-					"Detail": Equal("zone spreading is not supported, specify partition via infrastructure config"),
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("workers[0].zones[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("workers[0].zones[2]"),
 				})),
 			))
 		})
@@ -90,7 +107,7 @@ var _ = Describe("Shoot validation", func() {
 		It("image must be present in cloud profile", func() {
 			workers[0].Machine.Image.Name = "coreos"
 
-			errorList := ValidateWorkers(workers, cloudProfile, field.NewPath("workers"))
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
 
 			Expect(errorList).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
@@ -104,7 +121,7 @@ var _ = Describe("Shoot validation", func() {
 		It("image version must be present in cloud profile", func() {
 			workers[0].Machine.Image.Version = "1.0"
 
-			errorList := ValidateWorkers(workers, cloudProfile, field.NewPath("workers"))
+			errorList := ValidateWorkers(workers, infrastructureConfig, cloudProfile, field.NewPath("workers"))
 
 			Expect(errorList).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/gardener/gardener/pkg/apis/core"
+	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// reservedTagPrefixes are the prefixes of the tag keys which are set by the extension and cannot be given as extra tags.
+var reservedTagPrefixes = []string{
+	"cluster.metal-stack.io/",
+	"machine.metal-stack.io/",
+	"kubernetes.io/",
+	"node.kubernetes.io/",
+	"topology.kubernetes.io/",
+}
+
+// ValidateWorkerConfig validates the WorkerConfig of the given worker pool. A partition override is only allowed for
+// pools without zones and must be one of the partitions of the given `InfrastructureConfig`.
+func ValidateWorkerConfig(workerConfig *apismetal.WorkerConfig, worker core.Worker, infra *apismetal.InfrastructureConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, t := range workerConfig.ExtraTags {
+		tagPath := fldPath.Child("extraTags").Index(i)
+		parsed, ok := tags.Parse(t)
+		if !ok {
+			allErrs = append(allErrs, field.Invalid(tagPath, t, "tag must be of the form key=value"))
+			continue
+		}
+		for _, prefix := range reservedTagPrefixes {
+			if strings.HasPrefix(parsed.Key, prefix) {
+				allErrs = append(allErrs, field.Forbidden(tagPath, "tags with the prefix "+prefix+" are set by the extension"))
+				break
+			}
+		}
+	}
+
+	if workerConfig.PartitionID != nil {
+		partitionPath := fldPath.Child("partitionID")
+		partitions := helper.PartitionIDs(infra)
+		if len(worker.Zones) > 0 {
+			allErrs = append(allErrs, field.Forbidden(partitionPath, "partition can only be overridden for worker pools without zones"))
+		} else if !sets.NewString(partitions...).Has(*workerConfig.PartitionID) {
+			allErrs = append(allErrs, field.NotSupported(partitionPath, *workerConfig.PartitionID, partitions))
+		}
+	}
+
+	images := sets.NewString()
+	for i, override := range workerConfig.ImageOverrides {
+		overridePath := fldPath.Child("imageOverrides").Index(i)
+		if override.Name == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("name"), "name must be specified"))
+		}
+		if override.Version == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("version"), "version must be specified"))
+		}
+		if override.Image == "" {
+			allErrs = append(allErrs, field.Required(overridePath.Child("image"), "image must be specified"))
+		}
+		image := override.Name + "-" + override.Version
+		if images.Has(image) {
+			allErrs = append(allErrs, field.Duplicate(overridePath, image))
+		}
+		images.Insert(image)
+	}
+
+	for i, snippet := range workerConfig.UserDataSnippets {
+		if strings.TrimSpace(snippet) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("userDataSnippets").Index(i), "user data snippet must not be empty"))
+		}
+	}
+
+	return allErrs
+}
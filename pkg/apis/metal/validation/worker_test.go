@@ -0,0 +1,117 @@
+package validation_test
+
+import (
+	"github.com/gardener/gardener/pkg/apis/core"
+	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Worker validation", func() {
+	Describe("#ValidateWorkerConfig", func() {
+		var (
+			infrastructureConfig *apismetal.InfrastructureConfig
+			worker               core.Worker
+			workerConfig         *apismetal.WorkerConfig
+			fldPath              = field.NewPath("workers").Index(0).Child("providerConfig")
+		)
+
+		BeforeEach(func() {
+			infrastructureConfig = &apismetal.InfrastructureConfig{
+				PartitionID:            "partition-a",
+				AdditionalPartitionIDs: []string{"partition-b"},
+			}
+			worker = core.Worker{Name: "worker"}
+			workerConfig = &apismetal.WorkerConfig{
+				ExtraTags:   []string{"team=storage"},
+				PartitionID: strPtr("partition-b"),
+				ImageOverrides: []apismetal.ImageOverride{
+					{Name: "ubuntu", Version: "19.04", Image: "ubuntu-19.04-custom"},
+				},
+				UserDataSnippets: []string{"echo hello"},
+			}
+		})
+
+		It("should pass because the worker config is configured correctly", func() {
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should pass for an empty worker config", func() {
+			Expect(ValidateWorkerConfig(&apismetal.WorkerConfig{}, worker, infrastructureConfig, fldPath)).To(BeEmpty())
+		})
+
+		It("should forbid malformed and reserved tags", func() {
+			workerConfig.ExtraTags = []string{"team", "cluster.metal-stack.io/id=other-cluster", "topology.kubernetes.io/zone=partition-c"}
+
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("workers[0].providerConfig.extraTags[0]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("workers[0].providerConfig.extraTags[1]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("workers[0].providerConfig.extraTags[2]"),
+				})),
+			))
+		})
+
+		It("should only allow partitions of the infrastructure", func() {
+			workerConfig.PartitionID = strPtr("partition-c")
+
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("workers[0].providerConfig.partitionID"),
+				})),
+			))
+		})
+
+		It("should forbid a partition override for pools with zones", func() {
+			worker.Zones = []string{"partition-a"}
+
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("workers[0].providerConfig.partitionID"),
+				})),
+			))
+		})
+
+		It("should require complete and distinct image overrides", func() {
+			workerConfig.ImageOverrides = []apismetal.ImageOverride{
+				{Name: "ubuntu", Version: "19.04", Image: "ubuntu-19.04-custom"},
+				{Name: "ubuntu", Version: "19.04"},
+			}
+
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("workers[0].providerConfig.imageOverrides[1].image"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("workers[0].providerConfig.imageOverrides[1]"),
+				})),
+			))
+		})
+
+		It("should forbid empty user data snippets", func() {
+			workerConfig.UserDataSnippets = []string{"echo hello", "  "}
+
+			Expect(ValidateWorkerConfig(workerConfig, worker, infrastructureConfig, fldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("workers[0].providerConfig.userDataSnippets[1]"),
+				})),
+			))
+		})
+	})
+})
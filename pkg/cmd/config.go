@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
 	configloader "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config/loader"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 
+	healthcheckconfig "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config"
 	"github.com/spf13/pflag"
 )
 
@@ -66,6 +69,44 @@ func (c *Config) ApplyETCDStorage(etcdStorage *config.ETCDStorage) {
 	*etcdStorage = c.Config.ETCD.Storage
 }
 
+// ApplyOrphanCollection sets the given orphan collection configuration to that of this Config.
+func (c *Config) ApplyOrphanCollection(orphanCollection **config.OrphanCollection) {
+	*orphanCollection = c.Config.OrphanCollection
+}
+
+// ApplyMetalAPIClient sets the given options of the metal client pool to the metal-api client configuration of this
+// Config, options which are not configured are left unchanged.
+func (c *Config) ApplyMetalAPIClient(opts *metalclient.PoolOptions) {
+	cfg := c.Config.MetalAPIClient
+	if cfg == nil {
+		return
+	}
+	if cfg.QPS != nil {
+		opts.QPS = *cfg.QPS
+	}
+	if cfg.Burst != nil {
+		opts.Burst = *cfg.Burst
+	}
+	if cfg.CacheTTL != nil {
+		opts.CacheTTL = cfg.CacheTTL.Duration
+	}
+}
+
+// ApplyFirewallProvisioningTimeout sets the given firewall provisioning timeout to that of this Config if it is
+// configured.
+func (c *Config) ApplyFirewallProvisioningTimeout(timeout *time.Duration) {
+	if c.Config.FirewallProvisioningTimeout != nil {
+		*timeout = c.Config.FirewallProvisioningTimeout.Duration
+	}
+}
+
+// ApplyHealthCheckConfig sets the given health check configuration to that of this Config if it is configured.
+func (c *Config) ApplyHealthCheckConfig(healthCheckConfig *healthcheckconfig.HealthCheckConfig) {
+	if c.Config.HealthCheckConfig != nil {
+		healthCheckConfig.SyncPeriod = c.Config.HealthCheckConfig.SyncPeriod
+	}
+}
+
 // Options initializes empty config.ControllerConfiguration, applies the set values and returns it.
 func (c *Config) Options() config.ControllerConfiguration {
 	var cfg config.ControllerConfiguration
@@ -3,13 +3,16 @@ package cmd
 import (
 	controllercmd "github.com/gardener/gardener-extensions/pkg/controller/cmd"
 	extensionscontrolplanecontroller "github.com/gardener/gardener-extensions/pkg/controller/controlplane"
+	extensionshealthcheckcontroller "github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
 	extensionsinfrastructurecontroller "github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 	extensionsworkercontroller "github.com/gardener/gardener-extensions/pkg/controller/worker"
 	webhookcmd "github.com/gardener/gardener-extensions/pkg/webhook/cmd"
 	extensioncontrolplanewebhook "github.com/gardener/gardener-extensions/pkg/webhook/controlplane"
 	extensionshootwebhook "github.com/gardener/gardener-extensions/pkg/webhook/shoot"
 	controlplanecontroller "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/controlplane"
+	healthcheckcontroller "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/healthcheck"
 	infrastructurecontroller "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/infrastructure"
+	orphancontroller "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/orphan"
 	workercontroller "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/worker"
 	controlplanewebhook "github.com/metal-stack/gardener-extension-provider-metal/pkg/webhook/controlplane"
 	controlplaneexposurewebhook "github.com/metal-stack/gardener-extension-provider-metal/pkg/webhook/controlplaneexposure"
@@ -22,6 +25,8 @@ func ControllerSwitchOptions() *controllercmd.SwitchOptions {
 		controllercmd.Switch(extensionsinfrastructurecontroller.ControllerName, infrastructurecontroller.AddToManager),
 		controllercmd.Switch(extensionscontrolplanecontroller.ControllerName, controlplanecontroller.AddToManager),
 		controllercmd.Switch(extensionsworkercontroller.ControllerName, workercontroller.AddToManager),
+		controllercmd.Switch(orphancontroller.ControllerName, orphancontroller.AddToManager),
+		controllercmd.Switch(extensionshealthcheckcontroller.ControllerName, healthcheckcontroller.AddToManager),
 	)
 }
 
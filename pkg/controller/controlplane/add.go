@@ -7,6 +7,7 @@ import (
 	"github.com/gardener/gardener-extensions/pkg/util"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/imagevector"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 	"github.com/spf13/pflag"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -111,15 +112,24 @@ type AddOptions struct {
 	IgnoreOperationAnnotation bool
 	// ShootWebhooks specifies the list of desired shoot webhooks.
 	ShootWebhooks []admissionregistrationv1beta1.MutatingWebhook
+	// MetalClientFactory creates the metal clients of the controller, metalclient.DefaultClientFactory is used if it
+	// is nil.
+	MetalClientFactory metalclient.ClientFactory
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
+	valuesProvider := NewValuesProvider(mgr, logger, *AccOpts.config, *AuthOpts.config)
+	if opts.MetalClientFactory != nil {
+		if _, err := metalclient.ClientFactoryInto(opts.MetalClientFactory, valuesProvider); err != nil {
+			return err
+		}
+	}
 
 	return controlplane.Add(mgr, controlplane.AddArgs{
 		Actuator: genericactuator.NewActuator(metal.Name, controlPlaneSecrets, nil, configChart, controlPlaneChart, cpShootChart,
-			storageClassChart, nil, NewValuesProvider(mgr, logger, *AccOpts.config, *AuthOpts.config), extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
+			storageClassChart, nil, valuesProvider, extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
 			imagevector.ImageVector(), "", opts.ShootWebhooks, mgr.GetWebhookServer().Port, logger),
 		ControllerOptions: opts.Controller,
 		Predicates:        controlplane.DefaultPredicates(opts.IgnoreOperationAnnotation),
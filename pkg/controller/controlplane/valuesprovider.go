@@ -19,7 +19,6 @@ import (
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
 
 	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
-	metalgo "github.com/metal-stack/metal-go"
 
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
 
@@ -51,25 +50,32 @@ import (
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Object names
 const (
-	cloudControllerManagerDeploymentName = "cloud-controller-manager"
+	cloudControllerManagerDeploymentName = metal.CloudControllerManagerDeploymentName
 	cloudControllerManagerServerName     = "cloud-controller-manager-server"
-	groupRolebindingControllerName       = "group-rolebinding-controller"
-	limitValidatingWebhookDeploymentName = "limit-validating-webhook"
+	groupRolebindingControllerName       = metal.GroupRolebindingControllerDeploymentName
+	limitValidatingWebhookDeploymentName = metal.LimitValidatingWebhookDeploymentName
 	limitValidatingWebhookServerName     = "limit-validating-webhook-server"
-	accountingExporterName               = "accounting-exporter"
-	authNWebhookDeploymentName           = "kube-jwt-authn-webhook"
+	accountingExporterName               = metal.AccountingExporterDeploymentName
+	authNWebhookDeploymentName           = metal.AuthNWebhookDeploymentName
 	authNWebhookServerName               = "kube-jwt-authn-webhook-server"
 	droptailerNamespace                  = "firewall"
 	droptailerClientSecretName           = "droptailer-client"
 	droptailerServerSecretName           = "droptailer-server"
 )
 
+// Event reasons
+const (
+	eventReasonNodeNetworkNotFound = "NodeNetworkNotFound"
+	eventReasonProjectNotFound     = "ProjectNotFound"
+)
+
 var controlPlaneSecrets = &secrets.Secrets{
 	CertificateSecretConfigs: map[string]*secrets.CertificateSecretConfig{
 		v1alpha1constants.SecretNameCACluster: {
@@ -226,6 +232,7 @@ var cpShootChart = &chart.Chart{
 		{Type: &networkingv1.NetworkPolicy{}, Name: "egress-allow-any"},
 		{Type: &networkingv1.NetworkPolicy{}, Name: "egress-allow-https"},
 		{Type: &networkingv1.NetworkPolicy{}, Name: "egress-allow-ntp"},
+		{Type: &networkingv1.NetworkPolicy{}, Name: "firewall-egress-rules"},
 
 		// accounting controller
 		{Type: &rbacv1.ClusterRole{}, Name: "system:accounting-exporter"},
@@ -274,10 +281,12 @@ var storageClassChart = &chart.Chart{
 // NewValuesProvider creates a new ValuesProvider for the generic actuator.
 func NewValuesProvider(mgr manager.Manager, logger logr.Logger, accConfig AccountingConfig, authConfig AuthConfig) genericactuator.ValuesProvider {
 	return &valuesProvider{
-		mgr:              mgr,
-		logger:           logger.WithName("metal-values-provider"),
-		accountingConfig: accConfig,
-		authConfig:       authConfig,
+		mgr:                mgr,
+		logger:             logger.WithName("metal-values-provider"),
+		recorder:           mgr.GetEventRecorderFor(controlplane.ControllerName),
+		accountingConfig:   accConfig,
+		authConfig:         authConfig,
+		metalClientFactory: metalclient.DefaultClientFactory,
 	}
 }
 
@@ -287,9 +296,12 @@ type valuesProvider struct {
 	restConfig       *rest.Config
 	client           client.Client
 	logger           logr.Logger
+	recorder         record.EventRecorder
 	accountingConfig AccountingConfig
 	authConfig       AuthConfig
 	mgr              manager.Manager
+
+	metalClientFactory metalclient.ClientFactory
 }
 
 // InjectScheme injects the given scheme into the valuesProvider.
@@ -308,6 +320,11 @@ func (vp *valuesProvider) InjectClient(client client.Client) error {
 	return nil
 }
 
+func (vp *valuesProvider) InjectMetalClientFactory(factory metalclient.ClientFactory) error {
+	vp.metalClientFactory = factory
+	return nil
+}
+
 // GetConfigChartValues returns the values for the config chart applied by the generic actuator.
 func (vp *valuesProvider) GetConfigChartValues(
 	ctx context.Context,
@@ -363,7 +380,7 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 		return nil, err
 	}
 
-	mclient, err := metalclient.NewClient(ctx, vp.client, &cp.Spec.SecretRef)
+	mclient, err := vp.metalClientFactory.NewClient(ctx, vp.client, &cp.Spec.SecretRef)
 	if err != nil {
 		return nil, err
 	}
@@ -371,6 +388,7 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 	// Get CCM chart values
 	chartValues, err := getCCMChartValues(cpConfig, infrastructureConfig, cp, cluster, checksums, scaledDown, mclient)
 	if err != nil {
+		vp.recorder.Eventf(cp, corev1.EventTypeWarning, eventReasonNodeNetworkNotFound, "Could not find the node network of the cluster in project %q: %v", infrastructureConfig.ProjectID, err)
 		return nil, err
 	}
 
@@ -381,6 +399,7 @@ func (vp *valuesProvider) GetControlPlaneChartValues(
 
 	accValues, err := getAccountingExporterChartValues(vp.accountingConfig, cluster, infrastructureConfig, mclient)
 	if err != nil {
+		vp.recorder.Eventf(cp, corev1.EventTypeWarning, eventReasonProjectNotFound, "Could not look up project %q: %v", infrastructureConfig.ProjectID, err)
 		return nil, err
 	}
 
@@ -427,9 +446,35 @@ func (vp *valuesProvider) GetControlPlaneShootChartValues(ctx context.Context, c
 		vp.logger.Error(err, "error deploying droptailer certs")
 	}
 
+	infrastructureConfig := &apismetal.InfrastructureConfig{}
+	if _, _, err := vp.decoder.Decode(cluster.Shoot.Spec.Provider.InfrastructureConfig.Raw, nil, infrastructureConfig); err != nil {
+		return nil, errors.Wrapf(err, "could not decode providerConfig of infrastructure")
+	}
+
+	merge(values, getFirewallEgressRulesChartValues(infrastructureConfig))
+
 	return values, nil
 }
 
+// getFirewallEgressRulesChartValues returns the values for the network policy the firewall-policy-controller derives the
+// egress rules of the firewalls from. Changed rules are applied to the running firewalls this way.
+func getFirewallEgressRulesChartValues(infrastructureConfig *apismetal.InfrastructureConfig) map[string]interface{} {
+	var rules []map[string]interface{}
+	if infrastructureConfig.Firewall.Rules != nil {
+		for _, rule := range infrastructureConfig.Firewall.Rules.Egress {
+			rules = append(rules, map[string]interface{}{
+				"protocol": string(rule.Protocol),
+				"cidrs":    rule.CIDRs,
+				"ports":    rule.Ports,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"firewallEgressRules": rules,
+	}
+}
+
 // GetLimitValidationWebhookChartValues returns the values for the LimitValidationWebhook.
 func (vp *valuesProvider) getControlPlaneShootLimitValidationWebhookChartValues(ctx context.Context, cp *extensionsv1alpha1.ControlPlane, cluster *extensionscontroller.Cluster) (map[string]interface{}, error) {
 	secretName := limitValidatingWebhookServerName
@@ -560,7 +605,7 @@ func getCCMChartValues(
 	cluster *extensionscontroller.Cluster,
 	checksums map[string]string,
 	scaledDown bool,
-	mclient *metalgo.Driver,
+	mclient metalclient.Client,
 ) (map[string]interface{}, error) {
 	projectID := infrastructure.ProjectID
 	nodeCIDR := cluster.Shoot.Spec.Networking.Nodes
@@ -571,7 +616,7 @@ func getCCMChartValues(
 
 	privateNetwork, err := metalclient.GetPrivateNetworkFromNodeNetwork(mclient, projectID, *nodeCIDR)
 	if err != nil {
-		return nil, err
+		return nil, metalclient.ReconcileError(err)
 	}
 
 	values := map[string]interface{}{
@@ -622,7 +667,7 @@ func getAuthNGroupRoleChartValues(cpConfig *apismetal.ControlPlaneConfig, cluste
 	return values, nil
 }
 
-func getAccountingExporterChartValues(accountingConfig AccountingConfig, cluster *extensionscontroller.Cluster, infrastructure *apismetal.InfrastructureConfig, mclient *metalgo.Driver) (map[string]interface{}, error) {
+func getAccountingExporterChartValues(accountingConfig AccountingConfig, cluster *extensionscontroller.Cluster, infrastructure *apismetal.InfrastructureConfig, mclient metalclient.Client) (map[string]interface{}, error) {
 	annotations := cluster.Shoot.GetAnnotations()
 	partitionID := infrastructure.PartitionID
 	projectID := infrastructure.ProjectID
@@ -632,7 +677,7 @@ func getAccountingExporterChartValues(accountingConfig AccountingConfig, cluster
 
 	resp, err := mclient.ProjectGet(projectID)
 	if err != nil {
-		return nil, err
+		return nil, metalclient.ReconcileError(err)
 	}
 	project := resp.Project
 
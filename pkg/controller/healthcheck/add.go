@@ -0,0 +1,105 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+	healthcheckconfig "github.com/gardener/gardener-extensions/pkg/controller/healthcheck/config"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck/general"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck/worker"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	defaultSyncPeriod = 30 * time.Second
+
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{
+		HealthCheckConfig: healthcheckconfig.HealthCheckConfig{
+			SyncPeriod: metav1.Duration{Duration: defaultSyncPeriod},
+		},
+	}
+)
+
+// AddOptions are options to apply when adding the health check controllers to the manager.
+type AddOptions struct {
+	// Controller are the controller.Options.
+	Controller controller.Options
+	// HealthCheckConfig contains the sync period of the health checks.
+	HealthCheckConfig healthcheckconfig.HealthCheckConfig
+	// MetalClientFactory creates the metal clients of the infrastructure health check,
+	// metalclient.DefaultClientFactory is used if it is nil.
+	MetalClientFactory metalclient.ClientFactory
+}
+
+// RegisterHealthChecks registers the health checks of the infrastructure, control plane and worker resources of the
+// metal provider. Their results are written as conditions into the extension resources, gardener reports them on the
+// shoot.
+func RegisterHealthChecks(mgr manager.Manager, opts AddOptions) error {
+	args := healthcheck.DefaultAddArgs{
+		Controller:        opts.Controller,
+		HealthCheckConfig: opts.HealthCheckConfig,
+	}
+
+	metalClientFactory := opts.MetalClientFactory
+	if metalClientFactory == nil {
+		metalClientFactory = metalclient.DefaultClientFactory
+	}
+
+	if err := healthcheck.DefaultRegistration(
+		metal.Type,
+		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.InfrastructureResource),
+		func() runtime.Object { return &extensionsv1alpha1.Infrastructure{} },
+		mgr,
+		args,
+		nil,
+		map[healthcheck.HealthCheck]string{
+			NewInfrastructureHealthChecker(metalClientFactory): string(gardencorev1beta1.ShootSystemComponentsHealthy),
+		}); err != nil {
+		return err
+	}
+
+	if err := healthcheck.DefaultRegistration(
+		metal.Type,
+		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.ControlPlaneResource),
+		func() runtime.Object { return &extensionsv1alpha1.ControlPlane{} },
+		mgr,
+		args,
+		nil,
+		map[healthcheck.HealthCheck]string{
+			general.NewSeedDeploymentHealthChecker(metal.CloudControllerManagerDeploymentName):     string(gardencorev1beta1.ShootControlPlaneHealthy),
+			general.NewSeedDeploymentHealthChecker(metal.AuthNWebhookDeploymentName):               string(gardencorev1beta1.ShootControlPlaneHealthy),
+			general.NewSeedDeploymentHealthChecker(metal.AccountingExporterDeploymentName):         string(gardencorev1beta1.ShootControlPlaneHealthy),
+			general.NewSeedDeploymentHealthChecker(metal.GroupRolebindingControllerDeploymentName): string(gardencorev1beta1.ShootControlPlaneHealthy),
+			general.NewSeedDeploymentHealthChecker(metal.LimitValidatingWebhookDeploymentName):     string(gardencorev1beta1.ShootControlPlaneHealthy),
+		}); err != nil {
+		return err
+	}
+
+	return healthcheck.DefaultRegistration(
+		metal.Type,
+		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.WorkerResource),
+		func() runtime.Object { return &extensionsv1alpha1.Worker{} },
+		mgr,
+		args,
+		nil,
+		map[healthcheck.HealthCheck]string{
+			general.NewSeedDeploymentHealthChecker(metal.MachineControllerManagerName): string(gardencorev1beta1.ShootControlPlaneHealthy),
+			worker.NewSufficientNodesChecker():                                         string(gardencorev1beta1.ShootEveryNodeReady),
+		})
+}
+
+// AddToManager adds the health check controllers with the default Options to the manager.
+func AddToManager(mgr manager.Manager) error {
+	return RegisterHealthChecks(mgr, DefaultAddOptions)
+}
@@ -0,0 +1,144 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	metalgo "github.com/metal-stack/metal-go"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// InfrastructureHealthChecker checks the metal resources of a shoot's infrastructure, i.e. that the firewalls of the
+// cluster are allocated and that the node network still exists.
+type InfrastructureHealthChecker struct {
+	logger      logr.Logger
+	seedClient  client.Client
+	shootClient client.Client
+
+	metalClientFactory metalclient.ClientFactory
+}
+
+// NewInfrastructureHealthChecker returns a health check for the metal resources of the infrastructure.
+func NewInfrastructureHealthChecker(metalClientFactory metalclient.ClientFactory) healthcheck.HealthCheck {
+	return &InfrastructureHealthChecker{
+		metalClientFactory: metalClientFactory,
+	}
+}
+
+// InjectSeedClient injects the seed client
+func (healthChecker *InfrastructureHealthChecker) InjectSeedClient(seedClient client.Client) {
+	healthChecker.seedClient = seedClient
+}
+
+// InjectShootClient injects the shoot client
+func (healthChecker *InfrastructureHealthChecker) InjectShootClient(shootClient client.Client) {
+	healthChecker.shootClient = shootClient
+}
+
+// SetLoggerSuffix injects the logger
+func (healthChecker *InfrastructureHealthChecker) SetLoggerSuffix(provider, extension string) {
+	healthChecker.logger = log.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-infrastructure", provider, extension))
+}
+
+// DeepCopy clones the healthCheck struct by making a copy and returning the pointer to that new copy
+func (healthChecker *InfrastructureHealthChecker) DeepCopy() healthcheck.HealthCheck {
+	copy := *healthChecker
+	return &copy
+}
+
+// Check executes the health check. Errors of the metal-api are returned as errors such that the condition becomes
+// unknown instead of unhealthy.
+func (healthChecker *InfrastructureHealthChecker) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	infrastructure := &extensionsv1alpha1.Infrastructure{}
+	if err := healthChecker.seedClient.Get(ctx, request, infrastructure); err != nil {
+		err := fmt.Errorf("failed to retrieve infrastructure '%s' in namespace '%s': %v", request.Name, request.Namespace, err)
+		healthChecker.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+	if infrastructure.DeletionTimestamp != nil {
+		return &healthcheck.SingleCheckResult{IsHealthy: true}, nil
+	}
+
+	infrastructureConfig, err := helper.InfrastructureConfigFromInfrastructure(infrastructure)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, healthChecker.seedClient, infrastructure.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Shoot == nil {
+		return nil, fmt.Errorf("cluster %s does not contain a shoot", infrastructure.Namespace)
+	}
+
+	mclient, err := healthChecker.metalClientFactory.NewClient(ctx, healthChecker.seedClient, &infrastructure.Spec.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterTag := metaltags.ClusterID(string(cluster.Shoot.GetUID()))
+	resp, err := mclient.FirewallFind(&metalgo.FirewallFindRequest{
+		MachineFindRequest: metalgo.MachineFindRequest{
+			AllocationProject: &infrastructureConfig.ProjectID,
+			Tags:              []string{clusterTag},
+		},
+	})
+	if err != nil {
+		healthChecker.logger.Error(err, "Health check failed")
+		return nil, err
+	}
+	if len(resp.Firewalls) == 0 {
+		return unhealthy(healthChecker.logger, "FirewallMissing", fmt.Errorf("no firewall found for cluster of infrastructure %s in namespace %s", infrastructure.Name, infrastructure.Namespace))
+	}
+	for _, fw := range resp.Firewalls {
+		if fw.Allocation == nil || fw.Allocation.Succeeded == nil || !*fw.Allocation.Succeeded {
+			return unhealthy(healthChecker.logger, "FirewallNotAllocated", fmt.Errorf("firewall %s has not been allocated successfully", *fw.ID))
+		}
+	}
+
+	if infrastructureConfig.NodeNetworkID != nil {
+		_, err := mclient.NetworkGet(*infrastructureConfig.NodeNetworkID)
+		if metalclient.IsNotFound(err) {
+			return unhealthy(healthChecker.logger, "NodeNetworkMissing", fmt.Errorf("node network %s does not exist", *infrastructureConfig.NodeNetworkID))
+		}
+		if err != nil {
+			healthChecker.logger.Error(err, "Health check failed")
+			return nil, err
+		}
+	} else if infrastructure.Status.NodesCIDR != nil {
+		networks, err := metalclient.GetPrivateNetworksFromNodeNetwork(mclient, infrastructureConfig.ProjectID, *infrastructure.Status.NodesCIDR)
+		if err != nil {
+			healthChecker.logger.Error(err, "Health check failed")
+			return nil, err
+		}
+		if len(networks) == 0 {
+			return unhealthy(healthChecker.logger, "NodeNetworkMissing", fmt.Errorf("no node network with prefix %s found in project %s", *infrastructure.Status.NodesCIDR, infrastructureConfig.ProjectID))
+		}
+	}
+
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: true,
+	}, nil
+}
+
+func unhealthy(logger logr.Logger, reason string, err error) (*healthcheck.SingleCheckResult, error) {
+	logger.Error(err, "Health check failed")
+	return &healthcheck.SingleCheckResult{
+		IsHealthy: false,
+		Detail:    err.Error(),
+		Reason:    reason,
+	}, nil
+}
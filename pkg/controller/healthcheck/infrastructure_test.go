@@ -0,0 +1,148 @@
+package healthcheck_test
+
+import (
+	"context"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/install"
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/healthcheck"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	"github.com/gardener/gardener-extensions/pkg/controller/healthcheck"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("InfrastructureHealthChecker", func() {
+	const (
+		namespace = "shoot--foo--bar"
+		clusterID = "cluster-id"
+		project   = "project-1"
+		nodeCIDR  = "10.0.0.0/22"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		scheme         *runtime.Scheme
+		metalClient    *metalfake.Client
+		infrastructure *extensionsv1alpha1.Infrastructure
+		cluster        *extensionsv1alpha1.Cluster
+		request        = types.NamespacedName{Namespace: namespace, Name: "infrastructure"}
+	)
+
+	newFirewall := func(succeeded bool) *models.V1FirewallResponse {
+		id := "firewall-1"
+		projectID := project
+		return &models.V1FirewallResponse{
+			ID:   &id,
+			Tags: []string{tag.ClusterID + "=" + clusterID},
+			Allocation: &models.V1MachineAllocation{
+				Project:   &projectID,
+				Succeeded: &succeeded,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		install.Install(scheme)
+
+		networkID := "private-network"
+		metalClient = metalfake.NewClient()
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:        &networkID,
+			Prefixes:  []string{nodeCIDR},
+			Projectid: project,
+		})
+
+		cidr := nodeCIDR
+		infrastructure = &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: request.Name, Namespace: namespace},
+			Spec: extensionsv1alpha1.InfrastructureSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type: metal.Type,
+					ProviderConfig: &runtime.RawExtension{
+						Raw: []byte(`{
+  "apiVersion": "metal.provider.extensions.gardener.cloud/v1alpha1",
+  "kind": "InfrastructureConfig",
+  "projectID": "project-1",
+  "partitionID": "partition-a",
+  "firewall": {"size": "c1-xlarge-x86", "image": "firewall-1", "networks": ["internet"]}
+}`),
+					},
+				},
+				SecretRef: corev1.SecretReference{Name: "cloudprovider", Namespace: namespace},
+			},
+			Status: extensionsv1alpha1.InfrastructureStatus{NodesCIDR: &cidr},
+		}
+		cluster = &extensionsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+			Spec: extensionsv1alpha1.ClusterSpec{
+				Shoot: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "core.gardener.cloud/v1beta1", "kind": "Shoot", "metadata": {"name": "bar", "uid": "` + clusterID + `"}}`),
+				},
+			},
+		}
+	})
+
+	check := func() (*healthcheck.SingleCheckResult, error) {
+		checker := NewInfrastructureHealthChecker(metalClient)
+		checker.InjectSeedClient(fake.NewFakeClientWithScheme(scheme, infrastructure, cluster))
+		checker.SetLoggerSuffix(metal.Type, "infrastructure")
+		return checker.Check(ctx, request)
+	}
+
+	It("should be healthy if the firewall is allocated and the node network exists", func() {
+		metalClient.AddFirewall(newFirewall(true))
+
+		result, err := check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeTrue())
+	})
+	It("should be unhealthy if there is no firewall", func() {
+		result, err := check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal("FirewallMissing"))
+	})
+	It("should be unhealthy if the firewall is not allocated yet", func() {
+		metalClient.AddFirewall(newFirewall(false))
+
+		result, err := check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal("FirewallNotAllocated"))
+	})
+	It("should be unhealthy if the node network does not exist anymore", func() {
+		metalClient.AddFirewall(newFirewall(true))
+		cidr := "10.1.0.0/22"
+		infrastructure.Status.NodesCIDR = &cidr
+
+		result, err := check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeFalse())
+		Expect(result.Reason).To(Equal("NodeNetworkMissing"))
+	})
+	It("should be healthy while the infrastructure is deleted", func() {
+		now := metav1.Now()
+		infrastructure.DeletionTimestamp = &now
+
+		result, err := check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsHealthy).To(BeTrue())
+	})
+})
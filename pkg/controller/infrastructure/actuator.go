@@ -3,14 +3,18 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"time"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
 	metalapiv1alpha1 "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/v1alpha1"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/orphan"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 	"github.com/pkg/errors"
 
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	gardenerkubernetes "github.com/gardener/gardener/pkg/client/kubernetes"
 
@@ -21,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,7 +33,8 @@ import (
 )
 
 type actuator struct {
-	logger logr.Logger
+	logger   logr.Logger
+	recorder record.EventRecorder
 
 	clientset         kubernetes.Interface
 	gardenerClientset gardenerkubernetes.Interface
@@ -37,12 +43,24 @@ type actuator struct {
 	client  client.Client
 	scheme  *runtime.Scheme
 	decoder runtime.Decoder
+
+	metalClientFactory metalclient.ClientFactory
+
+	firewallProvisioningTimeout time.Duration
 }
 
-// NewActuator creates a new Actuator that updates the status of the handled Infrastructure resources.
-func NewActuator() infrastructure.Actuator {
+// NewActuator creates a new Actuator that updates the status of the handled Infrastructure resources. Firewalls which
+// are not provisioned within the given timeout are allocated again, DefaultFirewallProvisioningTimeout is used if it
+// is zero.
+func NewActuator(recorder record.EventRecorder, firewallProvisioningTimeout time.Duration) infrastructure.Actuator {
+	if firewallProvisioningTimeout == 0 {
+		firewallProvisioningTimeout = DefaultFirewallProvisioningTimeout
+	}
 	return &actuator{
-		logger: log.Log.WithName("infrastructure-actuator"),
+		logger:                      log.Log.WithName("infrastructure-actuator"),
+		recorder:                    recorder,
+		metalClientFactory:          metalclient.DefaultClientFactory,
+		firewallProvisioningTimeout: firewallProvisioningTimeout,
 	}
 }
 
@@ -57,6 +75,11 @@ func (a *actuator) InjectClient(client client.Client) error {
 	return nil
 }
 
+func (a *actuator) InjectMetalClientFactory(factory metalclient.ClientFactory) error {
+	a.metalClientFactory = factory
+	return nil
+}
+
 func (a *actuator) InjectConfig(config *rest.Config) error {
 	var err error
 	a.clientset, err = kubernetes.NewForConfig(config)
@@ -74,11 +97,47 @@ func (a *actuator) InjectConfig(config *rest.Config) error {
 }
 
 func (a *actuator) Reconcile(ctx context.Context, config *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
-	return a.reconcile(ctx, config, cluster)
+	if config.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.GardenerOperationMigrate {
+		return recordOperation(operationMigrate, a.migrate(ctx, config, cluster))
+	}
+	return recordOperation(operationReconcile, a.reconcile(ctx, config, cluster))
 }
 
 func (a *actuator) Delete(ctx context.Context, config *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
-	return a.delete(ctx, config, cluster)
+	if config.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.GardenerOperationMigrate {
+		a.logger.Info("infrastructure was migrated to another seed, keeping metal resources", "infrastructure", config.Name)
+		forgetProvisioningFirewalls(config.Namespace)
+		return nil
+	}
+	return recordOperation(operationDelete, a.delete(ctx, config, cluster))
+}
+
+// migrate persists the state of the infrastructure such that the firewalls and the node network can be adopted by the
+// infrastructure controller of the seed the control plane is migrated to. The metal resources are left untouched, they
+// keep the mark of this seed and are recorded as migrated for the orphan collector of this seed.
+func (a *actuator) migrate(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
+	_, infrastructureStatus, err := a.decodeInfrastructure(infrastructure)
+	if err != nil {
+		return err
+	}
+
+	if err := orphan.RecordMigratedCluster(ctx, a.client, string(cluster.Shoot.GetUID())); err != nil {
+		return err
+	}
+
+	restoreNodesCIDR(infrastructure, infrastructureStatus)
+
+	status, err := a.encodeInfrastructureStatus(infrastructureStatus)
+	if err != nil {
+		return err
+	}
+
+	return extensionscontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.client, infrastructure, func() error {
+		infrastructure.Status.State = &runtime.RawExtension{
+			Object: status,
+		}
+		return nil
+	})
 }
 
 func (a *actuator) decodeInfrastructure(infrastructure *extensionsv1alpha1.Infrastructure) (*metalapi.InfrastructureConfig, *metalapi.InfrastructureStatus, error) {
@@ -92,26 +151,62 @@ func (a *actuator) decodeInfrastructure(infrastructure *extensionsv1alpha1.Infra
 		if _, _, err := a.decoder.Decode(infrastructure.Status.ProviderStatus.Raw, nil, infrastructureStatus); err != nil {
 			return nil, nil, fmt.Errorf("could not decode infrastructure status: %+v", err)
 		}
+	} else if infrastructure.Status.State != nil {
+		// the infrastructure was restored after a control plane migration, only the state is carried over
+		if _, _, err := a.decoder.Decode(infrastructure.Status.State.Raw, nil, infrastructureStatus); err != nil {
+			return nil, nil, fmt.Errorf("could not decode infrastructure state: %+v", err)
+		}
+	}
+
+	// infrastructures created before multiple firewalls were supported only carry a single firewall status
+	if infrastructureStatus.Firewall != nil {
+		if len(infrastructureStatus.Firewalls) == 0 && infrastructureStatus.Firewall.MachineID != "" {
+			infrastructureStatus.Firewalls = append(infrastructureStatus.Firewalls, *infrastructureStatus.Firewall)
+		}
+		infrastructureStatus.Firewall = nil
 	}
 
 	return infrastructureConfig, infrastructureStatus, nil
 }
 
-func (a *actuator) updateProviderStatus(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, status metalapi.FirewallStatus, nodeCIDR *string) error {
+func (a *actuator) updateProviderStatus(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureStatus *metalapi.InfrastructureStatus, nodeCIDR *string) error {
+	status, err := a.encodeInfrastructureStatus(infrastructureStatus)
+	if err != nil {
+		return err
+	}
+
 	return extensionscontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.client, infrastructure, func() error {
 		infrastructure.Status.NodesCIDR = nodeCIDR
 		infrastructure.Status.ProviderStatus = &runtime.RawExtension{
-			Object: &metalapiv1alpha1.InfrastructureStatus{
-				TypeMeta: metav1.TypeMeta{
-					APIVersion: metalapiv1alpha1.SchemeGroupVersion.String(),
-					Kind:       "InfrastructureStatus",
-				},
-				Firewall: metalapiv1alpha1.FirewallStatus{
-					Succeeded: status.Succeeded,
-					MachineID: status.MachineID,
-				},
-			},
+			Object: status,
 		}
 		return nil
 	})
 }
+
+func (a *actuator) encodeInfrastructureStatus(infrastructureStatus *metalapi.InfrastructureStatus) (*metalapiv1alpha1.InfrastructureStatus, error) {
+	status := &metalapiv1alpha1.InfrastructureStatus{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metalapiv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "InfrastructureStatus",
+		},
+	}
+	if err := a.scheme.Convert(infrastructureStatus, status, nil); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// restoreNodesCIDR sets the nodes cidr of an infrastructure which was restored from its state after a control plane
+// migration, the existing node network is adopted instead of allocating a new one.
+func restoreNodesCIDR(infrastructure *extensionsv1alpha1.Infrastructure, infrastructureStatus *metalapi.InfrastructureStatus) {
+	if infrastructure.Status.NodesCIDR != nil {
+		return
+	}
+	nodeCIDR, err := nodeCIDRFromPrefixes(infrastructureStatus.NodeNetworkPrefixes)
+	if err != nil {
+		// nothing was restored, the node network is looked up or allocated again
+		return
+	}
+	infrastructure.Status.NodesCIDR = &nodeCIDR
+}
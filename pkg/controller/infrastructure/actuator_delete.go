@@ -2,17 +2,19 @@ package infrastructure
 
 import (
 	"context"
-	"fmt"
-	"github.com/metal-stack/metal-lib/pkg/tag"
 	"time"
 
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
 	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
-	metalgo "github.com/metal-stack/metal-go"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
 
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
@@ -20,73 +22,35 @@ func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha
 	if err != nil {
 		return err
 	}
+	restoreNodesCIDR(infrastructure, infrastructureStatus)
 
-	var (
-		clusterID      = string(cluster.Shoot.GetUID())
-		clusterTag     = fmt.Sprintf("%s=%s", tag.ClusterID, clusterID)
-		firewallStatus = infrastructureStatus.Firewall
-	)
+	clusterID := string(cluster.Shoot.GetUID())
 
-	mclient, err := metalclient.NewClient(ctx, a.client, &infrastructure.Spec.SecretRef)
+	mclient, err := a.metalClientFactory.NewClient(ctx, a.client, &infrastructure.Spec.SecretRef)
 	if err != nil {
 		return err
 	}
 
-	if firewallStatus.MachineID != "" {
-		machineID := decodeMachineID(firewallStatus.MachineID)
-		if machineID != "" {
-			resp, err := mclient.FirewallFind(&metalgo.FirewallFindRequest{
-				MachineFindRequest: metalgo.MachineFindRequest{
-					ID:                &machineID,
-					AllocationProject: &infrastructureConfig.ProjectID,
-					Tags:              []string{clusterTag},
-				},
-			})
-			if err != nil {
-				return &controllererrors.RequeueAfterError{
-					Cause:        err,
-					RequeueAfter: 30 * time.Second,
-				}
-			}
-
-			if len(resp.Firewalls) > 0 {
-				_, err = mclient.MachineDelete(machineID)
-				if err != nil {
-					a.logger.Error(err, "failed to delete firewall", "infrastructure", infrastructure.Name, "firewallID", machineID)
-					return &controllererrors.RequeueAfterError{
-						Cause:        err,
-						RequeueAfter: 30 * time.Second,
-					}
-				}
-			}
-
-			firewallStatus.MachineID = ""
-			err = a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, firewallStatus, infrastructure.Status.NodesCIDR)
-			if err != nil {
-				a.logger.Error(err, "unable to update provider status after firewall deletion", "infrastructure", infrastructure.Name)
-				return &controllererrors.RequeueAfterError{
-					Cause:        err,
-					RequeueAfter: 30 * time.Second,
-				}
-			}
-		}
+	plan, err := planDeletion(mclient, infrastructureConfig, clusterID, infrastructure.Status.NodesCIDR)
+	if err != nil {
+		a.logger.Error(err, "failed to plan deletion of metal resources", "infrastructure", infrastructure.Name, "clusterID", clusterID)
+		return metalclient.ReconcileError(err)
 	}
 
-	projectID := infrastructureConfig.ProjectID
-
-	ipsToFree, ipsToUpdate, err := metalclient.GetEphemeralIPsFromCluster(mclient, projectID, clusterID)
-	if err != nil {
-		a.logger.Error(err, "failed to query ephemeral cluster ips", "infrastructure", infrastructure.Name, "clusterID", clusterID)
-		return &controllererrors.RequeueAfterError{
-			Cause:        err,
-			RequeueAfter: 30 * time.Second,
+	for _, fw := range plan.firewalls {
+		if err := a.deleteFirewall(mclient, infrastructure, *fw.ID, "the infrastructure is deleted"); err != nil {
+			a.logger.Error(err, "failed to delete firewall", "infrastructure", infrastructure.Name, "firewallID", *fw.ID)
+			return metalclient.ReconcileError(err)
 		}
 	}
 
-	for _, ip := range ipsToFree {
-		_, err := mclient.IPFree(*ip.Ipaddress)
+	forgetProvisioningFirewalls(infrastructure.Namespace)
+
+	if len(infrastructureStatus.Firewalls) > 0 {
+		infrastructureStatus.Firewalls = nil
+		err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, infrastructure.Status.NodesCIDR)
 		if err != nil {
-			a.logger.Error(err, "failed to release ephemeral cluster ip", "infrastructure", infrastructure.Name, "ip", *ip.Ipaddress)
+			a.logger.Error(err, "unable to update provider status after firewall deletion", "infrastructure", infrastructure.Name)
 			return &controllererrors.RequeueAfterError{
 				Cause:        err,
 				RequeueAfter: 30 * time.Second,
@@ -94,36 +58,88 @@ func (a *actuator) delete(ctx context.Context, infrastructure *extensionsv1alpha
 		}
 	}
 
-	for _, ip := range ipsToUpdate {
-		err := metalclient.UpdateIPInCluster(mclient, ip, clusterID)
-		if err != nil {
-			a.logger.Error(err, "failed to remove cluster tags from ip which is member of other clusters", "infrastructure", infrastructure.Name, "ip", *ip.Ipaddress)
-			return &controllererrors.RequeueAfterError{
-				Cause:        err,
-				RequeueAfter: 30 * time.Second,
+	deletion := infrastructureStatus.Deletion
+	if deletion == nil {
+		deletion = &metalapi.DeletionStatus{}
+		infrastructureStatus.Deletion = deletion
+	}
+	deletion.Failures = nil
+
+	releasedIPs := sets.NewString(deletion.ReleasedIPs...)
+	var ipTasks []releaseTask
+	for _, ip := range plan.ipsToFree {
+		address := *ip.Ipaddress
+		if releasedIPs.Has(address) {
+			continue
+		}
+		ipTasks = append(ipTasks, releaseTask{kind: releaseKindIP, id: address, release: func() error {
+			_, err := mclient.IPFree(address)
+			if metalclient.IgnoreNotFound(err) != nil {
+				a.logger.Error(err, "failed to release ephemeral cluster ip", "infrastructure", infrastructure.Name, "ip", address)
+				a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonIPReleaseFailed, "Could not release ip %s: %v", address, err)
+				return err
 			}
+			a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonIPReleased, "Released ip %s", address)
+			return nil
+		}})
+	}
+	for _, ip := range plan.ipsToUpdate {
+		ip := ip
+		address := *ip.Ipaddress
+		if releasedIPs.Has(address) {
+			continue
 		}
+		ipTasks = append(ipTasks, releaseTask{kind: releaseKindIP, id: address, release: func() error {
+			err := metalclient.RemoveIPFromCluster(mclient, ip, clusterID)
+			if err != nil {
+				a.logger.Error(err, "failed to remove cluster tags from ip which is member of other clusters", "infrastructure", infrastructure.Name, "ip", address)
+				a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonIPUntagFailed, "Could not remove the cluster tags from ip %s: %v", address, err)
+				return err
+			}
+			a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonIPUntagged, "Removed the cluster tags from ip %s which is still used by other clusters", address)
+			return nil
+		}})
 	}
+	failed := recordRelease(deletion, ipTasks, releaseAll(ipTasks, releaseWorkers))
 
-	if infrastructure.Status.NodesCIDR != nil {
-		privateNetworks, err := metalclient.GetPrivateNetworksFromNodeNetwork(mclient, projectID, *infrastructure.Status.NodesCIDR)
-		if err != nil {
-			a.logger.Error(err, "failed to query private network", "infrastructure", infrastructure.Name, "nodeCIDR", *infrastructure.Status.NodesCIDR)
-			return &controllererrors.RequeueAfterError{
-				Cause:        err,
-				RequeueAfter: 30 * time.Second,
+	if infrastructureConfig.NodeNetworkID != nil {
+		a.logger.Info("not releasing node network as it was provided by the user", "infrastructure", infrastructure.Name, "networkID", *infrastructureConfig.NodeNetworkID)
+		a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonNodeNetworkKept, "Not releasing node network %q because it was provided by the user", *infrastructureConfig.NodeNetworkID)
+	}
+
+	// networks are released after the ips, a network which still contains an ip that could not be released fails and is
+	// retried with the next attempt
+	releasedNetworks := sets.NewString(deletion.ReleasedNetworks...)
+	var networkTasks []releaseTask
+	for _, pn := range plan.networks {
+		networkID := *pn.ID
+		if releasedNetworks.Has(networkID) {
+			continue
+		}
+		networkTasks = append(networkTasks, releaseTask{kind: releaseKindNetwork, id: networkID, release: func() error {
+			_, err := mclient.NetworkFree(networkID)
+			if metalclient.IgnoreNotFound(err) != nil {
+				a.logger.Error(err, "failed to release private network", "infrastructure", infrastructure.Name, "networkID", networkID)
+				a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonNetworkReleaseFailed, "Could not release network %q: %v", networkID, err)
+				return err
 			}
+			a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonNetworkReleased, "Released network %q", networkID)
+			return nil
+		}})
+	}
+	failed = append(failed, recordRelease(deletion, networkTasks, releaseAll(networkTasks, releaseWorkers))...)
+
+	if len(ipTasks) > 0 || len(networkTasks) > 0 {
+		if err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, infrastructure.Status.NodesCIDR); err != nil {
+			a.logger.Error(err, "unable to update provider status after releasing metal resources", "infrastructure", infrastructure.Name)
+			failed = append(failed, err)
 		}
+	}
 
-		for _, pn := range privateNetworks {
-			_, err := mclient.NetworkFree(*pn.ID)
-			if err != nil {
-				a.logger.Error(err, "failed to release private network", "infrastructure", infrastructure.Name, "networkID", *pn.ID)
-				return &controllererrors.RequeueAfterError{
-					Cause:        err,
-					RequeueAfter: 30 * time.Second,
-				}
-			}
+	if len(failed) > 0 {
+		return &controllererrors.RequeueAfterError{
+			Cause:        utilerrors.NewAggregate(failed),
+			RequeueAfter: 30 * time.Second,
 		}
 	}
 
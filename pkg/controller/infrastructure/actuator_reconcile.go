@@ -2,17 +2,22 @@ package infrastructure
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"github.com/metal-stack/metal-lib/pkg/tag"
+	"net"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
 	"github.com/google/uuid"
 	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
 
 	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/ignition"
 	metalgo "github.com/metal-stack/metal-go"
-	metalfirewall "github.com/metal-stack/metal-go/api/client/firewall"
+	"github.com/metal-stack/metal-go/api/models"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
@@ -23,11 +28,15 @@ import (
 	"github.com/gardener/gardener/pkg/utils/secrets"
 
 	"github.com/coreos/container-linux-config-transpiler/config/types"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
 	firewallPolicyControllerName = "firewall-policy-controller"
 	droptailerClientName         = "droptailer"
+	firewallRulesName            = "firewall-rules"
 )
 
 func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) error {
@@ -35,19 +44,48 @@ func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1al
 	if err != nil {
 		return err
 	}
+	restoreNodesCIDR(infrastructure, infrastructureStatus)
 
 	var (
-		clusterID      = string(cluster.Shoot.GetUID())
-		clusterTag     = fmt.Sprintf("%s=%s", tag.ClusterID, clusterID)
-		firewallStatus = infrastructureStatus.Firewall
+		clusterID  = string(cluster.Shoot.GetUID())
+		clusterTag = metaltags.ClusterID(clusterID)
 	)
 
-	mclient, err := metalclient.NewClient(ctx, a.client, &infrastructure.Spec.SecretRef)
+	mclient, err := a.metalClientFactory.NewClient(ctx, a.client, &infrastructure.Spec.SecretRef)
 	if err != nil {
 		return err
 	}
 
-	nodeCIDR, err := a.ensureNodeNetwork(ctx, clusterID, mclient, infrastructure, infrastructureConfig, cluster)
+	if _, ok := infrastructure.Annotations[DeletionPlanAnnotation]; ok {
+		if err := a.recordDeletionPlan(ctx, mclient, infrastructure, infrastructureConfig, infrastructureStatus, clusterID); err != nil {
+			return metalclient.ReconcileError(err)
+		}
+	}
+
+	firewallRules, err := renderFirewallRules(mclient, infrastructureConfig.Firewall.Rules)
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+
+	nodeCIDR, nodeNetworkPrefixes, err := a.ensureNodeNetwork(ctx, clusterID, mclient, infrastructure, infrastructureConfig, cluster)
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+	infrastructureStatus.NodeNetworkPrefixes = nodeNetworkPrefixes
+
+	additionalNodeNetworks, err := a.ensureAdditionalNodeNetworks(mclient, infrastructure, clusterID, infrastructureConfig, cluster)
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+	infrastructureStatus.AdditionalNodeNetworks = additionalNodeNetworks
+
+	if dualStack(infrastructureConfig) && !containsIPv6Prefix(nodeNetworkPrefixes) {
+		// the node network exists already, it does not get an IPv6 prefix by reconciling again
+		return metalclient.ReconcileError(fmt.Errorf("dual-stack node network requested but node network has no IPv6 prefix: %v", nodeNetworkPrefixes))
+	}
+
+	infrastructure.Status.NodesCIDR = &nodeCIDR
+	err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, &nodeCIDR)
 	if err != nil {
 		return &controllererrors.RequeueAfterError{
 			Cause:        err,
@@ -55,134 +93,376 @@ func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1al
 		}
 	}
 
-	infrastructure.Status.NodesCIDR = &nodeCIDR
-	err = a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, firewallStatus, &nodeCIDR)
+	egressIPs, err := a.ensureEgressIPs(mclient, infrastructure, infrastructureConfig, infrastructureStatus, cluster, clusterTag)
 	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+	infrastructureStatus.EgressIPs = egressIPs
+
+	if err := a.ensureEphemeralIPsOfSeed(mclient, infrastructure, infrastructureConfig, clusterID, seedName(cluster)); err != nil {
+		return metalclient.ReconcileError(err)
+	}
+
+	if err := a.ensureFirewallCredentials(ctx, infrastructure, cluster, infrastructureStatus); err != nil {
 		return &controllererrors.RequeueAfterError{
 			Cause:        err,
 			RequeueAfter: 30 * time.Second,
 		}
 	}
+	hashes := firewallHashes{
+		rules: firewallRulesHash(firewallRules),
+		seed:  seedName(cluster),
+	}
 
-	if firewallStatus.Succeeded {
-		// verify that the firewall is still there and correctly reconciled
+	resp, err := mclient.FirewallFind(&metalgo.FirewallFindRequest{
+		MachineFindRequest: metalgo.MachineFindRequest{
+			AllocationProject: &infrastructureConfig.ProjectID,
+			Tags:              []string{clusterTag},
+		},
+	})
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+	found, err := getFirewalls(mclient, resp.Firewalls)
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
 
-		resp, err := mclient.FirewallFind(&metalgo.FirewallFindRequest{
-			MachineFindRequest: metalgo.MachineFindRequest{
-				AllocationProject: &infrastructureConfig.ProjectID,
-				Tags:              []string{clusterTag},
-			},
-		})
-		if err != nil {
-			return &controllererrors.RequeueAfterError{
-				Cause:        err,
-				RequeueAfter: 30 * time.Second,
-			}
+	for _, fw := range found {
+		if !containsFirewall(infrastructureStatus.Firewalls, *fw.ID) {
+			a.logger.Info("found firewall of this cluster which is not part of the infrastructure status, adopting it", "clusterid", clusterID, "machineid", *fw.ID)
+			a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonFirewallAdopted, "Adopted firewall %q of this cluster which was not part of the infrastructure status", *fw.ID)
 		}
+	}
+	for _, status := range infrastructureStatus.Firewalls {
+		if findFirewall(found, decodeMachineID(status.MachineID)) == nil {
+			a.logger.Error(fmt.Errorf("firewall does not exist anymore"), "removing firewall from infrastructure status, a new one will be created", "clusterid", clusterID, "machineid", decodeMachineID(status.MachineID))
+			a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallDisappeared, "Firewall %q does not exist anymore, a new one will be created", decodeMachineID(status.MachineID))
+		}
+	}
+
+	firewalls, err := a.deleteStuckFirewalls(mclient, infrastructure, infrastructureStatus, found)
+	if err != nil {
+		return metalclient.ReconcileError(err)
+	}
+	infrastructureStatus.Firewalls = firewallStatuses(firewalls)
 
-		machineID := decodeMachineID(firewallStatus.MachineID)
-		clusterFirewallAmount := len(resp.Firewalls)
+	partitionIDs, partitionReplicas := firewallPartitions(infrastructureConfig, firewalls)
+
+	// the snippets are read on every reconciliation such that changed snippets lead to replaced firewalls
+	snippets := map[string][]types.Config{}
+	hashes.snippets = map[string]string{}
+	for _, partitionID := range partitionIDs {
+		snippets[partitionID], err = a.firewallIgnitionSnippets(ctx, infrastructureConfig, partitionID, cluster)
+		if err != nil {
+			return err
+		}
+		hashes.snippets[partitionID], err = firewallSnippetsHash(snippets[partitionID])
+		if err != nil {
+			return err
+		}
+	}
 
-		if clusterFirewallAmount == 0 {
-			a.logger.Error(err, "firewall does not exist anymore, creating new firewall", "clusterid", clusterID, "machineid", machineID)
+	if infrastructureStatus.Rollout == nil {
+		for _, partitionID := range partitionIDs {
+			partitionFirewalls := firewallsInPartition(firewalls, partitionID)
+			if len(partitionFirewalls) <= partitionReplicas[partitionID] {
+				continue
+			}
+			// a replacement firewall may have been created without the rollout being recorded in the status, e.g. because
+			// the controller was restarted in between. the rollout is resumed instead of deleting the outdated firewall right away.
+			infrastructureStatus.Rollout = findUnrecordedFirewallRollout(partitionFirewalls, infrastructureConfig, hashes, egressIPs)
+			if infrastructureStatus.Rollout != nil {
+				break
+			}
 		}
-		if clusterFirewallAmount > 1 {
-			return fmt.Errorf("multiple firewalls exist for this cluster, which is currently unsupported. delete these firewalls and try to keep the one with machine id %q", machineID)
+	}
+
+	if infrastructureStatus.Rollout != nil {
+		firewalls, err = a.continueFirewallRollout(ctx, mclient, infrastructure, infrastructureStatus, firewalls, &nodeCIDR)
+		if err != nil {
+			return err
 		}
-		if clusterFirewallAmount == 1 {
-			fw := resp.Firewalls[0]
-			if *fw.ID != machineID {
-				a.logger.Error(fmt.Errorf("machine id of this cluster's firewall differs from infrastructure status"), "leaving as it is, but something unexpected must have happened in the past. if you want to get to a clean state, remove the firewall by hand (causes downtime!) and reconcile infrastructure again", "clusterID", clusterID, "expectedMachineID", machineID, "actualMachineID", *fw.ID)
+	}
+
+	// the firewalls are scaled per partition, every partition of the cluster gets the desired amount of replicas
+	var (
+		upToDate, outdated []*models.V1FirewallResponse
+		missing            = map[string]int{}
+		totalMissing       int
+	)
+	for _, partitionID := range partitionIDs {
+		var partitionUpToDate, partitionOutdated []*models.V1FirewallResponse
+		for _, fw := range firewallsInPartition(firewalls, partitionID) {
+			if firewallUpToDate(fw, infrastructureConfig, hashes, egressIPs) {
+				partitionUpToDate = append(partitionUpToDate, fw)
+				continue
 			}
+			partitionOutdated = append(partitionOutdated, fw)
+		}
 
-			if *fw.Size.ID == infrastructureConfig.Firewall.Size && *fw.Allocation.Image.ID == infrastructureConfig.Firewall.Image {
-				return nil
+		// scale down by deleting the surplus firewalls, outdated firewalls and firewalls that are still provisioning are removed first
+		sort.SliceStable(partitionUpToDate, func(i, j int) bool {
+			return firewallSucceeded(partitionUpToDate[i]) && !firewallSucceeded(partitionUpToDate[j])
+		})
+		for len(partitionUpToDate)+len(partitionOutdated) > partitionReplicas[partitionID] {
+			var surplus *models.V1FirewallResponse
+			if len(partitionOutdated) > 0 {
+				surplus = partitionOutdated[len(partitionOutdated)-1]
+				partitionOutdated = partitionOutdated[:len(partitionOutdated)-1]
+			} else {
+				surplus = partitionUpToDate[len(partitionUpToDate)-1]
+				partitionUpToDate = partitionUpToDate[:len(partitionUpToDate)-1]
 			}
 
-			a.logger.Info("firewall spec has changed. deleting old firewall and creating a new one", "clusterid", clusterID, "machineid", machineID)
+			a.logger.Info("too many firewalls exist for this cluster, deleting surplus firewall", "clusterid", clusterID, "partition", partitionID, "machineid", *surplus.ID)
 
-			_, err = mclient.MachineDelete(*fw.ID)
-			if err != nil {
-				return &controllererrors.RequeueAfterError{
-					Cause:        err,
-					RequeueAfter: 30 * time.Second,
-				}
+			if err := a.deleteFirewall(mclient, infrastructure, *surplus.ID, fmt.Sprintf("it exceeds the %d firewalls of partition %q", partitionReplicas[partitionID], partitionID)); err != nil {
+				return metalclient.ReconcileError(err)
 			}
 		}
 
-		firewallStatus.MachineID = ""
-		firewallStatus.Succeeded = false
-		err = a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, firewallStatus, &nodeCIDR)
+		missing[partitionID] = partitionReplicas[partitionID] - len(partitionUpToDate) - len(partitionOutdated)
+		totalMissing += missing[partitionID]
+		upToDate = append(upToDate, partitionUpToDate...)
+		outdated = append(outdated, partitionOutdated...)
+	}
+
+	if len(outdated) == 0 && totalMissing == 0 && provisioningFirewall(upToDate) == nil {
+		infrastructureStatus.FirewallProvisioning = nil
+	}
+
+	infrastructureStatus.Firewalls = firewallStatuses(append(upToDate, outdated...))
+	recordProvisioningFirewalls(infrastructure.Namespace, append(upToDate, outdated...))
+	err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, &nodeCIDR)
+	if err != nil {
+		return err
+	}
+
+	if totalMissing <= 0 && len(outdated) == 0 {
+		return waitForFirewallProvisioning(upToDate)
+	}
+
+	// we need to create firewalls
+	kubeconfig, err := a.createFirewallPolicyControllerKubeconfig(ctx, infrastructure, cluster)
+	if err != nil {
+		return err
+	}
+
+	// createPartitionFirewall creates a firewall in the given partition which is attached to the node network of the partition
+	createPartitionFirewall := func(partitionID string) (*models.V1FirewallResponse, error) {
+		privateNetworkID, err := a.privateNetworkOfPartition(mclient, infrastructureConfig, infrastructureStatus, nodeCIDR, partitionID)
+		if err != nil {
+			return nil, metalclient.ReconcileError(err)
+		}
+
+		firewallUserData, err := a.renderFirewallUserData(kubeconfig, firewallRules, snippets[partitionID])
 		if err != nil {
-			return err
+			return nil, err
 		}
+
+		firewallTags := append([]string{clusterTag}, hashes.tags(partitionID)...)
+		fw, err := a.createFirewall(ctx, mclient, infrastructure, infrastructureConfig, infrastructureStatus, cluster, partitionID, firewallTags, privateNetworkID, firewallUserData, egressIPs)
+		if err != nil {
+			return nil, metalclient.ReconcileError(err)
+		}
+		return fw, nil
 	}
 
-	if firewallStatus.MachineID != "" {
-		// firewall was created, waiting for completion
-		machineID := decodeMachineID(firewallStatus.MachineID)
+	for _, partitionID := range partitionIDs {
+		for i := 0; i < missing[partitionID]; i++ {
+			fw, err := createPartitionFirewall(partitionID)
+			if err != nil {
+				return err
+			}
+			upToDate = append(upToDate, fw)
 
-		resp, err := mclient.FirewallGet(machineID)
-		if err != nil {
-			switch e := err.(type) {
-			case *metalfirewall.FindFirewallDefault:
-				if e.Code() >= 500 {
-					return &controllererrors.RequeueAfterError{
-						Cause:        e,
-						RequeueAfter: 5 * time.Second,
-					}
-				}
-			default:
-				return e
+			infrastructureStatus.Firewalls = append(infrastructureStatus.Firewalls, firewallStatuses([]*models.V1FirewallResponse{fw})...)
+			err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, &nodeCIDR)
+			if err != nil {
+				return err
 			}
 		}
+	}
 
-		allocation := resp.Firewall.Allocation
-		if allocation == nil {
-			return fmt.Errorf("firewall %q was created but has no allocation", machineID)
+	recordProvisioningFirewalls(infrastructure.Namespace, append(upToDate, outdated...))
+
+	if len(outdated) == 0 {
+		return waitForFirewallProvisioning(upToDate)
+	}
+
+	// the firewall spec has changed, the outdated firewalls are replaced one after another.
+	// the new firewall is created before the old one gets deleted such that the cluster does not lose its egress.
+	old := outdated[0]
+
+	if len(egressIPs) > 0 {
+		// static egress ips can only be attached to a single machine, the old firewall has to be deleted before its
+		// replacement can acquire them. the replacement is created on the next reconciliation. this trades the
+		// zero-downtime rollout for stable egress ips: the cluster has no egress until the replacement is running.
+		a.logger.Info("firewall spec has changed, deleting the old firewall to release its static egress ips", "clusterid", clusterID, "machineid", *old.ID)
+
+		if err := a.deleteFirewall(mclient, infrastructure, *old.ID, "its spec has changed and its static egress ips are needed by its replacement"); err != nil {
+			return metalclient.ReconcileError(err)
 		}
 
-		firewallStatus.Succeeded = *resp.Firewall.Allocation.Succeeded
-		return a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, firewallStatus, &nodeCIDR)
+		infrastructureStatus.Firewalls = firewallStatuses(append(upToDate, outdated[1:]...))
+		err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, &nodeCIDR)
+		if err != nil {
+			return err
+		}
+
+		return &controllererrors.RequeueAfterError{
+			Cause:        fmt.Errorf("deleted firewall %q, waiting to create its replacement", *old.ID),
+			RequeueAfter: 30 * time.Second,
+		}
 	}
 
-	// we need to create a firewall
-	uuid, err := uuid.NewUUID()
+	a.logger.Info("firewall spec has changed, creating a new firewall before deleting the old one", "clusterid", clusterID, "machineid", *old.ID)
+
+	fw, err := createPartitionFirewall(firewallPartition(old))
 	if err != nil {
 		return err
 	}
-	// Example values:
-	// cluster.Shoot.Status.TechnicalID  "shoot--dev--johndoe-metal"
-	clusterName := cluster.Shoot.Status.TechnicalID
-	name := clusterName + "-firewall-" + uuid.String()[:5]
 
-	// find private network
-	privateNetwork, err := metalclient.GetPrivateNetworkFromNodeNetwork(mclient, infrastructureConfig.ProjectID, nodeCIDR)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonFirewallRolloutStarted, "Spec of firewall %q has changed, it is deleted as soon as its replacement %q is allocated", *old.ID, *fw.ID)
+
+	infrastructureStatus.Firewalls = append(infrastructureStatus.Firewalls, firewallStatuses([]*models.V1FirewallResponse{fw})...)
+	infrastructureStatus.Rollout = &metalapi.FirewallRollout{
+		Phase:        metalapi.FirewallRolloutPhaseProvisioning,
+		OldMachineID: encodeMachineID(firewallPartition(old), *old.ID),
+		NewMachineID: encodeMachineID(firewallPartition(fw), *fw.ID),
+	}
+	err = a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, &nodeCIDR)
 	if err != nil {
 		return err
 	}
 
-	kubeconfig, err := a.createFirewallPolicyControllerKubeconfig(ctx, infrastructure, cluster)
+	return &controllererrors.RequeueAfterError{
+		Cause:        fmt.Errorf("waiting for firewall %q to be allocated before deleting firewall %q", *fw.ID, *old.ID),
+		RequeueAfter: 30 * time.Second,
+	}
+}
+
+// privateNetworkOfPartition returns the id of the node network of the cluster in the given partition.
+func (a *actuator) privateNetworkOfPartition(mclient metalclient.Client, infrastructureConfig *metalapi.InfrastructureConfig, infrastructureStatus *metalapi.InfrastructureStatus, nodeCIDR, partitionID string) (string, error) {
+	if partitionID == infrastructureConfig.PartitionID {
+		privateNetwork, err := metalclient.GetPrivateNetworkFromNodeNetwork(mclient, infrastructureConfig.ProjectID, nodeCIDR)
+		if err != nil {
+			return "", err
+		}
+		return *privateNetwork.ID, nil
+	}
+	for _, nw := range infrastructureStatus.AdditionalNodeNetworks {
+		if nw.PartitionID == partitionID {
+			return nw.NetworkID, nil
+		}
+	}
+	return "", fmt.Errorf("no node network found in partition %q", partitionID)
+}
+
+// continueFirewallRollout drives an ongoing firewall replacement. The old firewall is deleted as soon as the allocation
+// of the new firewall has succeeded. It returns the firewalls of the cluster that remain after the rollout step.
+func (a *actuator) continueFirewallRollout(ctx context.Context, mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureStatus *metalapi.InfrastructureStatus, firewalls []*models.V1FirewallResponse, nodeCIDR *string) ([]*models.V1FirewallResponse, error) {
+	var (
+		rollout      = infrastructureStatus.Rollout
+		oldMachineID = decodeMachineID(rollout.OldMachineID)
+		newMachineID = decodeMachineID(rollout.NewMachineID)
+	)
+
+	if findFirewall(firewalls, newMachineID) == nil {
+		a.logger.Error(fmt.Errorf("firewall does not exist anymore"), "new firewall of the rollout disappeared, restarting rollout", "oldmachineid", oldMachineID, "newmachineid", newMachineID)
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallRolloutRestarted, "Replacement %q of firewall %q does not exist anymore, restarting the rollout", newMachineID, oldMachineID)
+		infrastructureStatus.Rollout = nil
+		return firewalls, a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, nodeCIDR)
+	}
+
+	if rollout.Phase == metalapi.FirewallRolloutPhaseProvisioning {
+		if !firewallSucceeded(findFirewall(firewalls, newMachineID)) {
+			err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, nodeCIDR)
+			if err != nil {
+				return nil, err
+			}
+			return nil, &controllererrors.RequeueAfterError{
+				Cause:        fmt.Errorf("waiting for firewall %q to be allocated before deleting firewall %q", newMachineID, oldMachineID),
+				RequeueAfter: 30 * time.Second,
+			}
+		}
+
+		rollout.Phase = metalapi.FirewallRolloutPhaseDeleting
+		err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, nodeCIDR)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if findFirewall(firewalls, oldMachineID) != nil {
+		a.logger.Info("new firewall is allocated, deleting old firewall", "oldmachineid", oldMachineID, "newmachineid", newMachineID)
+
+		if err := a.deleteFirewall(mclient, infrastructure, oldMachineID, fmt.Sprintf("its replacement %q is allocated", newMachineID)); err != nil {
+			return nil, metalclient.ReconcileError(err)
+		}
+	}
+
+	var remaining []*models.V1FirewallResponse
+	for _, fw := range firewalls {
+		if *fw.ID != oldMachineID {
+			remaining = append(remaining, fw)
+		}
+	}
+
+	infrastructureStatus.Firewalls = firewallStatuses(remaining)
+	infrastructureStatus.Rollout = nil
+	err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, nodeCIDR)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	firewallUserData, err := a.renderFirewallUserData(kubeconfig)
+	return remaining, nil
+}
+
+// waitForFirewallProvisioning returns an error which requeues the infrastructure while one of the given firewalls is
+// still provisioning, such that firewalls which do not finish provisioning are detected.
+func waitForFirewallProvisioning(firewalls []*models.V1FirewallResponse) error {
+	fw := provisioningFirewall(firewalls)
+	if fw == nil {
+		return nil
+	}
+	return &controllererrors.RequeueAfterError{
+		Cause:        fmt.Errorf("waiting for firewall %q to be provisioned", *fw.ID),
+		RequeueAfter: 30 * time.Second,
+	}
+}
+
+// createFirewall allocates a new firewall in the given partition. The allocation is counted in the provisioning status, a failed allocation
+// is recorded there as well.
+func (a *actuator) createFirewall(ctx context.Context, mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, infrastructureStatus *metalapi.InfrastructureStatus, cluster *extensionscontroller.Cluster, partitionID string, tags []string, privateNetworkID, firewallUserData string, egressIPs []metalapi.FirewallEgressIPs) (*models.V1FirewallResponse, error) {
+	uuid, err := uuid.NewUUID()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	// Example values:
+	// cluster.Shoot.Status.TechnicalID  "shoot--dev--johndoe-metal"
+	clusterName := cluster.Shoot.Status.TechnicalID
+	name := clusterName + "-firewall-" + uuid.String()[:5]
 
 	// assemble firewall allocation request
 	var networks []metalgo.MachineAllocationNetwork
 	network := metalgo.MachineAllocationNetwork{
-		NetworkID:   *privateNetwork.ID,
+		NetworkID:   privateNetworkID,
 		Autoacquire: true,
 	}
 	networks = append(networks, network)
+	// static egress ips are attached instead of acquiring an ephemeral ip in their network
+	staticNetworks := sets.NewString()
+	var ips []string
+	for _, egress := range egressIPs {
+		staticNetworks.Insert(egress.NetworkID)
+		ips = append(ips, egress.IPs...)
+	}
 	for _, n := range infrastructureConfig.Firewall.Networks {
 		network := metalgo.MachineAllocationNetwork{
 			NetworkID:   n,
-			Autoacquire: true,
+			Autoacquire: !staticNetworks.Has(n),
 		}
 		networks = append(networks, network)
 	}
@@ -194,42 +474,75 @@ func (a *actuator) reconcile(ctx context.Context, infrastructure *extensionsv1al
 			Hostname:      name,
 			Size:          infrastructureConfig.Firewall.Size,
 			Project:       infrastructureConfig.ProjectID,
-			Partition:     infrastructureConfig.PartitionID,
+			Partition:     partitionID,
 			Image:         infrastructureConfig.Firewall.Image,
 			SSHPublicKeys: []string{string(infrastructure.Spec.SSHPublicKey)},
 			Networks:      networks,
+			IPs:           ips,
 			UserData:      firewallUserData,
-			Tags:          []string{clusterTag},
+			Tags:          tags,
 		},
 	}
 
 	a.logger.Info("create firewall", "name", createRequest.Name)
 
+	recordFirewallAllocation(infrastructureStatus)
 	fcr, err := mclient.FirewallCreate(createRequest)
 	if err != nil {
 		a.logger.Error(err, "failed to create firewall", "infrastructure", infrastructure.Name)
-		return &controllererrors.RequeueAfterError{
-			Cause:        err,
-			RequeueAfter: 30 * time.Second,
+
+		recordFirewallProvisioningError(infrastructureStatus, fmt.Sprintf("allocation of firewall %q failed: %v", name, err), time.Now())
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallAllocationFailed, "Could not allocate firewall %q: %v", name, err)
+		if err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, infrastructure.Status.NodesCIDR); err != nil {
+			a.logger.Error(err, "unable to record failed firewall allocation in provider status", "infrastructure", infrastructure.Name)
 		}
+		return nil, err
 	}
 
-	machineID := encodeMachineID(*fcr.Firewall.Partition.ID, *fcr.Firewall.ID)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonFirewallCreated, "Created firewall %q with hostname %q in partition %q", *fcr.Firewall.ID, name, partitionID)
 
-	allocation := fcr.Firewall.Allocation
-	if allocation == nil {
-		return fmt.Errorf("firewall %q was created but has no allocation", machineID)
+	if fcr.Firewall.Allocation == nil {
+		return nil, fmt.Errorf("firewall %q was created but has no allocation", *fcr.Firewall.ID)
 	}
 
-	firewallStatus.MachineID = machineID
-	firewallStatus.Succeeded = true
+	return fcr.Firewall, nil
+}
 
-	return a.updateProviderStatus(ctx, infrastructure, infrastructureConfig, firewallStatus, &nodeCIDR)
+// deleteFirewall deletes the firewall with the given machine id and reports the deletion together with its reason as
+// an event. A firewall which does not exist anymore counts as deleted.
+func (a *actuator) deleteFirewall(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, machineID, reason string) error {
+	_, err := mclient.MachineDelete(machineID)
+	if metalclient.IgnoreNotFound(err) != nil {
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallDeletionFailed, "Could not delete firewall %q: %v", machineID, err)
+		return err
+	}
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonFirewallDeleted, "Deleted firewall %q because %s", machineID, reason)
+	return nil
 }
 
-func (a *actuator) ensureNodeNetwork(ctx context.Context, clusterID string, mclient *metalgo.Driver, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, cluster *extensionscontroller.Cluster) (string, error) {
+// ensureNodeNetwork ensures the node network of the cluster and returns the nodes cidr together with all prefixes of
+// the node network.
+func (a *actuator) ensureNodeNetwork(ctx context.Context, clusterID string, mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, cluster *extensionscontroller.Cluster) (string, []string, error) {
+	if infrastructureConfig.NodeNetworkID != nil {
+		// the adopted network is not labeled with the cluster and the seed: the network update of metal-go v0.3.2 only
+		// sends the name, description and prefixes of a network, the labels can only be set on allocation. The network
+		// is therefore never considered by the orphan collector and is not released when the shoot is deleted.
+		nw, err := a.adoptNodeNetwork(mclient, infrastructureConfig, cluster)
+		if err != nil {
+			return "", nil, err
+		}
+		return a.ensureAdditionalNodeNetworkPrefix(mclient, infrastructure, nw, infrastructureConfig)
+	}
 	if cluster.Shoot.Spec.Networking.Nodes != nil {
-		return *cluster.Shoot.Spec.Networking.Nodes, nil
+		if !dualStack(infrastructureConfig) {
+			return *cluster.Shoot.Spec.Networking.Nodes, []string{*cluster.Shoot.Spec.Networking.Nodes}, nil
+		}
+		// the IPv6 prefix is only known to the metal-api
+		nw, err := metalclient.GetPrivateNetworkFromNodeNetwork(mclient, infrastructureConfig.ProjectID, *cluster.Shoot.Spec.Networking.Nodes)
+		if err != nil {
+			return "", nil, err
+		}
+		return *cluster.Shoot.Spec.Networking.Nodes, nw.Prefixes, nil
 	}
 	if infrastructure.Status.NodesCIDR != nil {
 		resp, err := mclient.NetworkFind(&metalgo.NetworkFindRequest{
@@ -238,31 +551,205 @@ func (a *actuator) ensureNodeNetwork(ctx context.Context, clusterID string, mcli
 			Labels:      map[string]string{tag.ClusterID: clusterID},
 		})
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 
-		if len(resp.Networks) != 0 {
-			return *infrastructure.Status.NodesCIDR, nil
+		for _, nw := range resp.Networks {
+			if nodeCIDR, err := nodeCIDRFromPrefixes(nw.Prefixes); err == nil && nodeCIDR == *infrastructure.Status.NodesCIDR {
+				return a.ensureAdditionalNodeNetworkPrefix(mclient, infrastructure, nw, infrastructureConfig)
+			}
 		}
 
-		return "", fmt.Errorf("node network disappeared from cloud provider: %s", *infrastructure.Status.NodesCIDR)
+		return "", nil, fmt.Errorf("node network disappeared from cloud provider: %s", *infrastructure.Status.NodesCIDR)
+	}
+
+	nw, err := a.allocateNodeNetwork(mclient, infrastructure, clusterID, infrastructureConfig.PartitionID, infrastructureConfig, cluster)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return a.ensureAdditionalNodeNetworkPrefix(mclient, infrastructure, nw, infrastructureConfig)
+}
+
+// allocateNodeNetwork allocates a node network for the cluster in the given partition. Requests which the partition
+// cannot fulfill are rejected before allocating, such that no network is allocated and released again on every
+// reconciliation.
+func (a *actuator) allocateNodeNetwork(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, clusterID, partitionID string, infrastructureConfig *metalapi.InfrastructureConfig, cluster *extensionscontroller.Cluster) (*models.V1NetworkResponse, error) {
+	if err := a.checkNodeNetworkPrefixLength(mclient, infrastructure, partitionID, infrastructureConfig); err != nil {
+		return nil, err
+	}
+	if err := a.checkNodeNetworkDualStack(mclient, infrastructure, partitionID, infrastructureConfig); err != nil {
+		return nil, err
 	}
 
 	resp, err := mclient.NetworkAllocate(&metalgo.NetworkAllocateRequest{
 		ProjectID:   infrastructureConfig.ProjectID,
-		PartitionID: infrastructureConfig.PartitionID,
+		PartitionID: partitionID,
 		Name:        cluster.Shoot.GetName(),
 		Description: clusterID,
-		Labels:      map[string]string{tag.ClusterID: clusterID},
+		Labels:      nodeNetworkLabels(clusterID, seedName(cluster)),
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	nodeCIDR := resp.Network.Prefixes[0]
-	a.logger.Info("dynamically allocated node network", "nodeCIDR", nodeCIDR)
+	nodeCIDR, err := nodeCIDRFromPrefixes(resp.Network.Prefixes)
+	if err != nil {
+		return nil, fmt.Errorf("allocated node network %q: %v", *resp.Network.ID, err)
+	}
+	a.logger.Info("dynamically allocated node network", "partition", partitionID, "nodeCIDR", nodeCIDR, "prefixes", resp.Network.Prefixes)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonNodeNetworkAllocated, "Allocated node network %q with prefixes %v in partition %q", *resp.Network.ID, resp.Network.Prefixes, partitionID)
+
+	return resp.Network, nil
+}
+
+// checkNodeNetworkDualStack returns an error if a dual-stack node network is requested but the private super network of
+// the given partition has no IPv6 prefix. The metal-api allocates a child prefix for every prefix of the private super
+// network, so a node network of such a partition never gets an IPv6 prefix.
+func (a *actuator) checkNodeNetworkDualStack(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, partitionID string, infrastructureConfig *metalapi.InfrastructureConfig) error {
+	if !dualStack(infrastructureConfig) {
+		return nil
+	}
+
+	privateSuper := true
+	resp, err := mclient.NetworkFind(&metalgo.NetworkFindRequest{
+		PartitionID:  &partitionID,
+		PrivateSuper: &privateSuper,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nw := range resp.Networks {
+		if containsIPv6Prefix(nw.Prefixes) {
+			return nil
+		}
+	}
+	a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonNodeNetworkRejected, "Partition %q has no IPv6 prefix for node networks although dual-stack was requested", partitionID)
+	return fmt.Errorf("partition %q does not offer dual-stack node networks, its private super network has no IPv6 prefix", partitionID)
+}
+
+// checkNodeNetworkPrefixLength returns an error if the requested prefix length of the node network differs from the
+// length of the private networks of the given partition. The metal-api allocates child prefixes of the partition's
+// private super network with this fixed length, the network allocate request does not allow requesting a length.
+func (a *actuator) checkNodeNetworkPrefixLength(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, partitionID string, infrastructureConfig *metalapi.InfrastructureConfig) error {
+	nodeNetwork := infrastructureConfig.NodeNetwork
+	if nodeNetwork == nil || nodeNetwork.PrefixLength == nil {
+		return nil
+	}
+
+	resp, err := mclient.PartitionGet(partitionID)
+	if err != nil {
+		return err
+	}
 
-	return nodeCIDR, nil
+	length := resp.Partition.Privatenetworkprefixlength
+	if length == 0 {
+		length = defaultPrivateNetworkPrefixLength
+	}
+	if length != *nodeNetwork.PrefixLength {
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonNodeNetworkRejected, "Partition %q offers node networks with prefix length %d only, prefix length %d was requested", partitionID, length, *nodeNetwork.PrefixLength)
+		return fmt.Errorf("partition %q does not offer node networks with prefix length %d, its node networks have prefix length %d", partitionID, *nodeNetwork.PrefixLength, length)
+	}
+	return nil
+}
+
+// ensureAdditionalNodeNetworks ensures the node networks of the additional partitions of the cluster. The node network
+// of a partition is found by the cluster label, it is allocated if there is none yet.
+func (a *actuator) ensureAdditionalNodeNetworks(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, clusterID string, infrastructureConfig *metalapi.InfrastructureConfig, cluster *extensionscontroller.Cluster) ([]metalapi.PartitionNodeNetwork, error) {
+	var nodeNetworks []metalapi.PartitionNodeNetwork
+	for _, partitionID := range infrastructureConfig.AdditionalPartitionIDs {
+		partitionID := partitionID
+
+		resp, err := mclient.NetworkFind(&metalgo.NetworkFindRequest{
+			ProjectID:   &infrastructureConfig.ProjectID,
+			PartitionID: &partitionID,
+			Labels:      map[string]string{tag.ClusterID: clusterID},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var nw *models.V1NetworkResponse
+		for _, n := range resp.Networks {
+			if len(n.Prefixes) != 0 {
+				nw = n
+				break
+			}
+		}
+		if nw == nil {
+			nw, err = a.allocateNodeNetwork(mclient, infrastructure, clusterID, partitionID, infrastructureConfig, cluster)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		nodeNetworks = append(nodeNetworks, metalapi.PartitionNodeNetwork{
+			PartitionID: partitionID,
+			NetworkID:   *nw.ID,
+			Prefixes:    nw.Prefixes,
+		})
+	}
+	return nodeNetworks, nil
+}
+
+// ensureAdditionalNodeNetworkPrefix adds the additional prefix of the node network configuration to the given network
+// if it is not yet present. It returns the nodes cidr together with all prefixes of the network.
+func (a *actuator) ensureAdditionalNodeNetworkPrefix(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, nw *models.V1NetworkResponse, infrastructureConfig *metalapi.InfrastructureConfig) (string, []string, error) {
+	nodeCIDR, err := nodeCIDRFromPrefixes(nw.Prefixes)
+	if err != nil {
+		return "", nil, fmt.Errorf("node network %q: %v", *nw.ID, err)
+	}
+
+	nodeNetwork := infrastructureConfig.NodeNetwork
+	if nodeNetwork == nil || nodeNetwork.AdditionalPrefix == nil {
+		return nodeCIDR, nw.Prefixes, nil
+	}
+
+	for _, prefix := range nw.Prefixes {
+		if prefix == *nodeNetwork.AdditionalPrefix {
+			return nodeCIDR, nw.Prefixes, nil
+		}
+	}
+
+	resp, err := mclient.NetworkAddPrefix(&metalgo.NetworkUpdateRequest{
+		Networkid: *nw.ID,
+		Prefix:    *nodeNetwork.AdditionalPrefix,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.logger.Info("added additional prefix to node network", "networkID", *nw.ID, "prefix", *nodeNetwork.AdditionalPrefix)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonNodeNetworkPrefixAdded, "Added prefix %s to node network %q", *nodeNetwork.AdditionalPrefix, *nw.ID)
+
+	return nodeCIDR, resp.Network.Prefixes, nil
+}
+
+// adoptNodeNetwork verifies that the node network given in the infrastructure config is a private network of the
+// cluster's project and partition.
+func (a *actuator) adoptNodeNetwork(mclient metalclient.Client, infrastructureConfig *metalapi.InfrastructureConfig, cluster *extensionscontroller.Cluster) (*models.V1NetworkResponse, error) {
+	resp, err := mclient.NetworkGet(*infrastructureConfig.NodeNetworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	nw := resp.Network
+	if nw.Projectid != infrastructureConfig.ProjectID {
+		return nil, fmt.Errorf("node network %q does not belong to project %q", *infrastructureConfig.NodeNetworkID, infrastructureConfig.ProjectID)
+	}
+	if nw.Partitionid != infrastructureConfig.PartitionID {
+		return nil, fmt.Errorf("node network %q does not belong to partition %q", *infrastructureConfig.NodeNetworkID, infrastructureConfig.PartitionID)
+	}
+	nodeCIDR, err := nodeCIDRFromPrefixes(nw.Prefixes)
+	if err != nil {
+		return nil, fmt.Errorf("node network %q: %v", *infrastructureConfig.NodeNetworkID, err)
+	}
+	if cluster.Shoot.Spec.Networking.Nodes != nil && *cluster.Shoot.Spec.Networking.Nodes != nodeCIDR {
+		return nil, fmt.Errorf("node network %q has prefix %s which does not match the nodes cidr %s of the shoot", *infrastructureConfig.NodeNetworkID, nodeCIDR, *cluster.Shoot.Spec.Networking.Nodes)
+	}
+
+	return nw, nil
 }
 
 func (a *actuator) createFirewallPolicyControllerKubeconfig(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) (string, error) {
@@ -307,7 +794,112 @@ func (a *actuator) createFirewallPolicyControllerKubeconfig(ctx context.Context,
 	return string(kubeconfig), nil
 }
 
-func (a *actuator) renderFirewallUserData(kubeconfig string) (string, error) {
+// renderFirewallRules renders the ingress rules and the rate limits of the given firewall rules into an nftables ruleset
+// which is applied on the firewall at boot, an empty string is returned if there are neither ingress rules nor rate
+// limits. Traffic entering the firewall from a network is matched by the vrf device of the network. The egress rules
+// are not part of the ruleset, the firewall-policy-controller applies them from the network policies in the shoot.
+func renderFirewallRules(mclient metalclient.Client, rules *metalapi.FirewallRules) (string, error) {
+	if rules == nil || (len(rules.Ingress) == 0 && len(rules.RateLimits) == 0) {
+		return "", nil
+	}
+
+	vrfs := map[string]int64{}
+	vrfOf := func(networkID string) (string, error) {
+		if _, ok := vrfs[networkID]; !ok {
+			resp, err := mclient.NetworkGet(networkID)
+			if err != nil {
+				return "", err
+			}
+			if resp.Network.Vrf == 0 {
+				return "", fmt.Errorf("network %q of firewall rule has no vrf", networkID)
+			}
+			vrfs[networkID] = resp.Network.Vrf
+		}
+		return fmt.Sprintf("vrf%d", vrfs[networkID]), nil
+	}
+
+	var ruleset strings.Builder
+	fmt.Fprintf(&ruleset, "table inet %s {\n", firewallRulesName)
+
+	if len(rules.RateLimits) > 0 {
+		ruleset.WriteString("\tchain ingress {\n")
+		ruleset.WriteString("\t\ttype filter hook prerouting priority 0; policy accept;\n")
+		for _, limit := range rules.RateLimits {
+			vrf, err := vrfOf(limit.NetworkID)
+			if err != nil {
+				return "", err
+			}
+			// the rate limit is given in Mbit/s
+			fmt.Fprintf(&ruleset, "\t\tmeta iifname \"%s\" limit rate over %d kbytes/second drop\n", vrf, uint64(limit.RateLimit)*125)
+		}
+		ruleset.WriteString("\t}\n")
+	}
+
+	if len(rules.Ingress) > 0 {
+		// the chain can only drop traffic, traffic dropped by the rulesets of the firewall itself is not accepted here
+		ruleset.WriteString("\tchain forward {\n")
+		ruleset.WriteString("\t\ttype filter hook forward priority 0; policy accept;\n")
+		ruleset.WriteString("\t\tct state established,related accept\n")
+		restricted := sets.NewString()
+		for _, rule := range rules.Ingress {
+			vrf, err := vrfOf(rule.NetworkID)
+			if err != nil {
+				return "", err
+			}
+			for _, match := range ingressRuleMatches(rule) {
+				fmt.Fprintf(&ruleset, "\t\tmeta iifname \"%s\" %s accept\n", vrf, match)
+			}
+			restricted.Insert(vrf)
+		}
+		for _, vrf := range restricted.List() {
+			fmt.Fprintf(&ruleset, "\t\tmeta iifname \"%s\" drop\n", vrf)
+		}
+		ruleset.WriteString("\t}\n")
+	}
+
+	ruleset.WriteString("}\n")
+
+	return ruleset.String(), nil
+}
+
+// ingressRuleMatches returns the nftables matches of the given ingress rule, one per address family of its sources.
+func ingressRuleMatches(rule metalapi.IngressRule) []string {
+	protocol := strings.ToLower(string(rule.Protocol))
+	destination := fmt.Sprintf("meta l4proto %s", protocol)
+	if len(rule.Ports) > 0 {
+		var ports []string
+		for _, port := range rule.Ports {
+			ports = append(ports, fmt.Sprintf("%d", port))
+		}
+		destination = fmt.Sprintf("%s dport { %s }", protocol, strings.Join(ports, ", "))
+	}
+
+	if len(rule.CIDRs) == 0 {
+		return []string{destination}
+	}
+
+	var v4, v6 []string
+	for _, cidr := range rule.CIDRs {
+		if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+			v6 = append(v6, cidr)
+		} else {
+			v4 = append(v4, cidr)
+		}
+	}
+
+	var matches []string
+	if len(v4) > 0 {
+		matches = append(matches, fmt.Sprintf("ip saddr { %s } %s", strings.Join(v4, ", "), destination))
+	}
+	if len(v6) > 0 {
+		matches = append(matches, fmt.Sprintf("ip6 saddr { %s } %s", strings.Join(v6, ", "), destination))
+	}
+	return matches
+}
+
+// renderFirewallUserData renders the ignition user data of the firewall. The given nftables ruleset of the firewall
+// rules is applied by a oneshot unit, the given snippets are merged into the generated config in their order.
+func (a *actuator) renderFirewallUserData(kubeconfig, rules string, snippets []types.Config) (string, error) {
 	cfg := types.Config{}
 	cfg.Systemd = types.Systemd{}
 
@@ -345,15 +937,49 @@ func (a *actuator) renderFirewallUserData(kubeconfig string) (string, error) {
 	}
 	cfg.Storage.Files = append(cfg.Storage.Files, ignitionFile)
 
-	outCfg, report := types.Convert(cfg, "", nil)
-	if report.IsFatal() {
-		return "", fmt.Errorf("could not transpile ignition config: %s", report.String())
+	if rules != "" {
+		rulesetPath := fmt.Sprintf("/etc/nftables/%s.nft", firewallRulesName)
+		rulesetFile := types.File{
+			Path:       rulesetPath,
+			Filesystem: "root",
+			Mode:       &mode,
+			User: &types.FileUser{
+				Id: &id,
+			},
+			Group: &types.FileGroup{
+				Id: &id,
+			},
+			Contents: types.FileContents{
+				Inline: rules,
+			},
+		}
+		cfg.Storage.Files = append(cfg.Storage.Files, rulesetFile)
+
+		rulesUnit := types.SystemdUnit{
+			Name:    fmt.Sprintf("%s.service", firewallRulesName),
+			Enable:  enabled,
+			Enabled: &enabled,
+			Contents: fmt.Sprintf(`[Unit]
+Description=Apply the ingress rules and rate limits of the firewall networks
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/sbin/nft -f %s
+
+[Install]
+WantedBy=multi-user.target
+`, rulesetPath),
+		}
+		cfg.Systemd.Units = append(cfg.Systemd.Units, rulesUnit)
 	}
 
-	userData, err := json.Marshal(outCfg)
+	merged, err := ignition.Merge(cfg, snippets...)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("could not merge ignition snippets: %w", err)
 	}
 
-	return string(userData), nil
+	return ignition.Transpile(merged)
 }
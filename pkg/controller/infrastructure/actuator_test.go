@@ -0,0 +1,575 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/install"
+	metalv1alpha1 "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/v1alpha1"
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/infrastructure"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	"github.com/go-openapi/strfmt"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+var _ = Describe("Actuator", func() {
+	const (
+		namespace  = "shoot--foo--bar"
+		clusterID  = "cluster-id"
+		firewallID = "firewall-1"
+		partition  = "partition-a"
+		nodeCIDR   = "10.0.0.0/22"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		scheme      *runtime.Scheme
+		metalClient *metalfake.Client
+		recorder    *record.FakeRecorder
+		cluster     *extensionscontroller.Cluster
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		install.Install(scheme)
+
+		var (
+			size      = "c1-xlarge-x86"
+			image     = "firewall-1"
+			id        = firewallID
+			networkID = "private-network"
+			succeeded = true
+			alive     = "Alive"
+			hostname  = "firewall"
+			ip        = "212.1.2.3"
+			egressIP  = "212.1.2.4"
+			ephemeral = "ephemeral"
+			static    = "static"
+			internet  = "internet"
+		)
+		project := "project-1"
+		metalClient = metalfake.NewClient()
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:          &networkID,
+			Prefixes:    []string{nodeCIDR},
+			Projectid:   project,
+			Partitionid: partition,
+			Labels:      map[string]string{tag.ClusterID: clusterID},
+		})
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:       &internet,
+			Prefixes: []string{"212.1.0.0/16"},
+		})
+		metalClient.AddFirewall(&models.V1FirewallResponse{
+			ID:         &id,
+			Liveliness: &alive,
+			Partition:  &models.V1PartitionResponse{ID: &[]string{partition}[0]},
+			Size:       &models.V1SizeResponse{ID: &size},
+			Tags:       []string{tag.ClusterID + "=" + clusterID},
+			Allocation: &models.V1MachineAllocation{
+				Hostname:  &hostname,
+				Project:   &project,
+				Image:     &models.V1ImageResponse{ID: &image},
+				Succeeded: &succeeded,
+				Networks: []*models.V1MachineNetwork{
+					{Networkid: &internet, Ips: []string{egressIP}},
+				},
+			},
+		})
+		metalClient.AddIP(&models.V1IPResponse{
+			Ipaddress: &ip,
+			Type:      &ephemeral,
+			Projectid: &project,
+			Tags:      []string{tag.ClusterServiceFQN + "=" + clusterID + "/default/ingress"},
+		})
+		metalClient.AddIP(&models.V1IPResponse{
+			Ipaddress: &egressIP,
+			Type:      &static,
+			Projectid: &project,
+			Networkid: &internet,
+			Tags:      []string{tag.ClusterID + "=" + clusterID, "firewall.metal-stack.io/egress-ip=allocated"},
+		})
+
+		cluster = &extensionscontroller.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "bar",
+					UID:  clusterID,
+				},
+			},
+		}
+	})
+
+	newActuator := func(objects ...runtime.Object) (client.Client, interface {
+		Reconcile(context.Context, *extensionsv1alpha1.Infrastructure, *extensionscontroller.Cluster) error
+		Delete(context.Context, *extensionsv1alpha1.Infrastructure, *extensionscontroller.Cluster) error
+	}) {
+		c := fake.NewFakeClientWithScheme(scheme, objects...)
+
+		recorder = record.NewFakeRecorder(100)
+		a := NewActuator(recorder, 0)
+		_, err := inject.SchemeInto(scheme, a)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = inject.ClientInto(c, a)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = metalclient.ClientFactoryInto(metalClient, a)
+		Expect(err).NotTo(HaveOccurred())
+
+		return c, a
+	}
+
+	newInfrastructure := func() *extensionsv1alpha1.Infrastructure {
+		return &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "infrastructure", Namespace: namespace},
+			Spec: extensionsv1alpha1.InfrastructureSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type: metal.Type,
+					ProviderConfig: &runtime.RawExtension{
+						Raw: []byte(`{
+  "apiVersion": "metal.provider.extensions.gardener.cloud/v1alpha1",
+  "kind": "InfrastructureConfig",
+  "projectID": "project-1",
+  "partitionID": "partition-a",
+  "firewall": {"size": "c1-xlarge-x86", "image": "firewall-1", "networks": ["internet"]}
+}`),
+					},
+				},
+				SecretRef: corev1.SecretReference{Name: "cloudprovider", Namespace: namespace},
+			},
+		}
+	}
+
+	// writes returns the operations called on the metal client which change metal resources.
+	writes := func() []string {
+		var result []string
+		for _, call := range metalClient.Calls {
+			if !strings.HasSuffix(call, "Find") && !strings.HasSuffix(call, "List") && !strings.HasSuffix(call, "Get") {
+				result = append(result, call)
+			}
+		}
+		return result
+	}
+
+	// events returns the events recorded by the actuator so far.
+	events := func() []string {
+		var result []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				result = append(result, event)
+			default:
+				return result
+			}
+		}
+	}
+
+	decodeStatus := func(raw *runtime.RawExtension) *metalv1alpha1.InfrastructureStatus {
+		Expect(raw).NotTo(BeNil())
+		status := &metalv1alpha1.InfrastructureStatus{}
+		_, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(raw.Raw, nil, status)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	It("should keep the metal resources on migration and adopt them on restore", func() {
+		By("reconciling the infrastructure on the source seed")
+		source := newInfrastructure()
+		cidr := nodeCIDR
+		source.Status.NodesCIDR = &cidr
+		sourceClient, sourceActuator := newActuator(source)
+
+		Expect(sourceActuator.Reconcile(ctx, source, cluster)).To(Succeed())
+		Expect(sourceClient.Get(ctx, kutil.Key(namespace, source.Name), source)).To(Succeed())
+		Expect(source.Status.State).To(BeNil())
+
+		By("migrating the infrastructure away from the source seed")
+		source.Annotations = map[string]string{v1beta1constants.GardenerOperation: v1beta1constants.GardenerOperationMigrate}
+		Expect(sourceClient.Update(ctx, source)).To(Succeed())
+
+		Expect(sourceActuator.Reconcile(ctx, source, cluster)).To(Succeed())
+		Expect(sourceActuator.Delete(ctx, source, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+		Expect(sourceClient.Get(ctx, kutil.Key(namespace, source.Name), source)).To(Succeed())
+
+		migrated := &corev1.ConfigMap{}
+		Expect(sourceClient.Get(ctx, kutil.Key("garden", "metal-migrated-clusters"), migrated)).To(Succeed())
+		Expect(migrated.Data).To(HaveKey(clusterID))
+
+		state := decodeStatus(source.Status.State)
+		Expect(state.Firewalls).To(HaveLen(1))
+		Expect(state.Firewalls[0].MachineID).To(Equal("metal:///" + partition + "/" + firewallID))
+		Expect(state.NodeNetworkPrefixes).To(ConsistOf(nodeCIDR))
+
+		By("restoring the infrastructure on the destination seed from its state only")
+		destination := newInfrastructure()
+		destination.Status.State = &runtime.RawExtension{Raw: source.Status.State.Raw}
+		destinationClient, destinationActuator := newActuator(destination)
+
+		Expect(destinationActuator.Reconcile(ctx, destination, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+
+		Expect(destinationClient.Get(ctx, kutil.Key(namespace, destination.Name), destination)).To(Succeed())
+		Expect(destination.Status.NodesCIDR).To(PointTo(Equal(nodeCIDR)))
+
+		status := decodeStatus(destination.Status.ProviderStatus)
+		Expect(status.Firewalls).To(HaveLen(1))
+		Expect(status.Firewalls[0].MachineID).To(Equal("metal:///" + partition + "/" + firewallID))
+		Expect(status.Firewalls[0].Phase).To(Equal(metalv1alpha1.FirewallPhaseRunning))
+	})
+	It("should record the deletion plan if it is requested", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		infrastructure.Annotations = map[string]string{DeletionPlanAnnotation: "true"}
+		c, a := newActuator(infrastructure)
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		Expect(infrastructure.Annotations).NotTo(HaveKey(DeletionPlanAnnotation))
+
+		status := decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.DeletionPlan).NotTo(BeNil())
+		Expect(status.DeletionPlan.Firewalls).To(ConsistOf(firewallID))
+		Expect(status.DeletionPlan.IPsToFree).To(ConsistOf("212.1.2.3", "212.1.2.4"))
+		Expect(status.DeletionPlan.IPsToUpdate).To(BeEmpty())
+		Expect(status.DeletionPlan.Networks).To(ConsistOf("private-network"))
+	})
+	It("should plan the deletion with the metal client of the given factory", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		c, _ := newActuator(infrastructure)
+
+		plan, err := PlanDeletion(ctx, c, metalClient, infrastructure, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Firewalls).To(ConsistOf(firewallID))
+		Expect(plan.Networks).To(ConsistOf("private-network"))
+		Expect(writes()).To(BeEmpty())
+	})
+
+	It("should reuse the static egress ip allocated for the cluster", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"networks": ["internet"]`, `"networks": ["internet"], "egressIPs": [{"networkID": "internet"}]`, 1))
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		c, a := newActuator(infrastructure)
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		status := decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.EgressIPs).To(Equal([]metalv1alpha1.FirewallEgressIPs{
+			{NetworkID: "internet", IPs: []string{"212.1.2.4"}},
+		}))
+		Expect(status.Firewalls).To(HaveLen(1))
+	})
+	It("should not release static egress ips given by the user", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"networks": ["internet"]`, `"networks": ["internet"], "egressIPs": [{"networkID": "internet", "ips": ["212.1.2.5"]}]`, 1))
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+
+		userIP, static, project, internet := "212.1.2.5", "static", "project-1", "internet"
+		metalClient.AddIP(&models.V1IPResponse{
+			Ipaddress: &userIP,
+			Type:      &static,
+			Projectid: &project,
+			Networkid: &internet,
+			Tags:      []string{tag.ClusterID + "=" + clusterID, "firewall.metal-stack.io/egress-ip=allocated"},
+		})
+
+		Expect(a.Delete(ctx, infrastructure, cluster)).To(Succeed())
+
+		var remaining []string
+		for _, ip := range metalClient.IPs() {
+			remaining = append(remaining, *ip.Ipaddress)
+		}
+		Expect(remaining).To(ConsistOf(userIP))
+	})
+
+	It("should mark the recorded egress ip allocated before ips were marked", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"networks": ["internet"]`, `"networks": ["internet"], "egressIPs": [{"networkID": "internet"}]`, 1))
+		infrastructure.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{
+  "apiVersion": "metal.provider.extensions.gardener.cloud/v1alpha1",
+  "kind": "InfrastructureStatus",
+  "egressIPs": [{"networkID": "internet", "ips": ["212.1.2.4"]}]
+}`)}
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+
+		egressIP := metalClient.IPs()[1]
+		Expect(*egressIP.Ipaddress).To(Equal("212.1.2.4"))
+		egressIP.Tags = []string{tag.ClusterID + "=" + clusterID}
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("IPUpdate"))
+		Expect(metalClient.IPs()[1].Tags).To(ContainElement("firewall.metal-stack.io/egress-ip=allocated"))
+	})
+
+	It("should mark the ephemeral ips of the cluster as managed by the seed", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+		cluster.Seed = &gardencorev1beta1.Seed{ObjectMeta: metav1.ObjectMeta{Name: "seed-a"}}
+
+		ephemeralIP := metalClient.IPs()[0]
+		Expect(*ephemeralIP.Ipaddress).To(Equal("212.1.2.3"))
+		ephemeralIP.Tags = append(ephemeralIP.Tags, "cluster.metal-stack.io/seed=seed-b")
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("IPUpdate"))
+		Expect(metalClient.IPs()[0].Tags).To(ConsistOf(tag.ClusterServiceFQN+"="+clusterID+"/default/ingress", "cluster.metal-stack.io/seed=seed-a"))
+		Expect(events()).To(ContainElement(ContainSubstring("IPSeedMarked")))
+
+		By("reconciling again")
+		metalClient.Calls = nil
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+	})
+
+	It("should adopt the firewalls marked for the seed the cluster was migrated from", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+		cluster.Seed = &gardencorev1beta1.Seed{ObjectMeta: metav1.ObjectMeta{Name: "seed-b"}}
+
+		fw := metalClient.Firewalls()[0]
+		fw.Tags = append(fw.Tags, "cluster.metal-stack.io/seed=seed-a")
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("IPUpdate"))
+		Expect(metalClient.Firewalls()).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"ID": PointTo(Equal(firewallID))}))))
+	})
+
+	It("should wait for provisioning firewalls and reset the provisioning status once they are running", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		infrastructure.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{
+  "apiVersion": "metal.provider.extensions.gardener.cloud/v1alpha1",
+  "kind": "InfrastructureStatus",
+  "firewallProvisioning": {"attempts": 2, "lastError": "allocation failed"}
+}`)}
+		c, a := newActuator(infrastructure)
+
+		fw := metalClient.Firewalls()[0]
+		provisioning := false
+		fw.Allocation.Succeeded = &provisioning
+		created := strfmt.DateTime(time.Now())
+		fw.Allocation.Created = &created
+
+		err := a.Reconcile(ctx, infrastructure, cluster)
+		Expect(err).To(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+		Expect(err.(*controllererrors.RequeueAfterError).Cause).To(MatchError(ContainSubstring("waiting for firewall \"" + firewallID + "\" to be provisioned")))
+		Expect(writes()).To(BeEmpty())
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		status := decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.Firewalls).To(HaveLen(1))
+		Expect(status.Firewalls[0].Phase).To(Equal(metalv1alpha1.FirewallPhaseProvisioning))
+		Expect(status.FirewallProvisioning).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Attempts":  BeEquivalentTo(2),
+			"LastError": Equal("allocation failed"),
+		})))
+
+		By("reconciling again after the firewall was provisioned")
+		succeeded := true
+		fw.Allocation.Succeeded = &succeeded
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		status = decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.FirewallProvisioning).To(BeNil())
+		Expect(status.Firewalls[0].Phase).To(Equal(metalv1alpha1.FirewallPhaseRunning))
+	})
+	It("should spread the cluster over the additional partitions", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"partitionID": "partition-a",`, `"partitionID": "partition-a", "additionalPartitionIDs": ["partition-b"],`, 1))
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		c, a := newActuator(infrastructure)
+
+		fw := *metalClient.Firewalls()[0]
+		id, partitionB := "firewall-2", "partition-b"
+		fw.ID = &id
+		fw.Partition = &models.V1PartitionResponse{ID: &partitionB}
+		metalClient.AddFirewall(&fw)
+
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("NetworkAllocate"))
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		Expect(infrastructure.Status.NodesCIDR).To(PointTo(Equal(nodeCIDR)))
+
+		status := decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.AdditionalNodeNetworks).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"PartitionID": Equal(partitionB),
+			"Prefixes":    HaveLen(1),
+		})))
+		Expect(status.Firewalls).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{"MachineID": Equal("metal:///" + partition + "/" + firewallID)}),
+			MatchFields(IgnoreExtras, Fields{"MachineID": Equal("metal:///" + partitionB + "/" + id)}),
+		))
+
+		By("reconciling again the node network of the additional partition is reused")
+		Expect(a.Reconcile(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("NetworkAllocate"))
+
+		By("deleting the infrastructure the node networks of all partitions are released")
+		Expect(a.Delete(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("NetworkAllocate", "MachineDelete", "MachineDelete", "IPFree", "IPFree", "NetworkFree", "NetworkFree"))
+		Expect(metalClient.Networks()).To(HaveLen(1))
+	})
+	It("should reject node network prefix lengths the partition does not offer without allocating a network", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"partitionID": "partition-a",`, `"partitionID": "partition-a", "additionalPartitionIDs": ["partition-b"], "nodeNetwork": {"prefixLength": 24},`, 1))
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+
+		partitionB := "partition-b"
+		metalClient.AddPartition(&models.V1PartitionResponse{ID: &partitionB, Privatenetworkprefixlength: 22})
+
+		err := a.Reconcile(ctx, infrastructure, cluster)
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+		Expect(metalclient.IsPermanent(err)).To(BeTrue())
+		Expect(writes()).To(BeEmpty())
+		Expect(events()).To(ContainElement(ContainSubstring("NodeNetworkRejected")))
+	})
+
+	It("should reject dual-stack node networks in partitions without IPv6 prefix without allocating a network", func() {
+		infrastructure := newInfrastructure()
+		infrastructure.Spec.ProviderConfig.Raw = []byte(strings.Replace(string(infrastructure.Spec.ProviderConfig.Raw),
+			`"partitionID": "partition-a",`, `"partitionID": "partition-a", "additionalPartitionIDs": ["partition-b"], "nodeNetwork": {"dualStack": true},`, 1))
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+
+		superID, privateSuper := "tenant-super-network-b", true
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:           &superID,
+			Partitionid:  "partition-b",
+			Prefixes:     []string{"10.128.0.0/14"},
+			Privatesuper: &privateSuper,
+		})
+
+		err := a.Reconcile(ctx, infrastructure, cluster)
+		Expect(err).To(HaveOccurred())
+		Expect(metalclient.IsPermanent(err)).To(BeTrue())
+		Expect(writes()).To(BeEmpty())
+		Expect(events()).To(ContainElement(ContainSubstring("NodeNetworkRejected")))
+	})
+
+	It("should release all metal resources of the cluster on deletion", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		_, a := newActuator(infrastructure)
+
+		Expect(a.Delete(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("MachineDelete", "IPFree", "IPFree", "NetworkFree"))
+
+		Expect(metalClient.Firewalls()).To(BeEmpty())
+		Expect(metalClient.IPs()).To(BeEmpty())
+		Expect(metalClient.Networks()).To(HaveLen(1))
+		Expect(metalClient.Networks()[0].ID).To(PointTo(Equal("internet")))
+
+		Expect(events()).To(ConsistOf(
+			"Normal FirewallDeleted Deleted firewall \""+firewallID+"\" because the infrastructure is deleted",
+			"Normal IPReleased Released ip 212.1.2.3",
+			"Normal IPReleased Released ip 212.1.2.4",
+			"Normal NetworkReleased Released network \"private-network\"",
+		))
+
+		By("deleting the infrastructure again after its resources are gone")
+		Expect(a.Delete(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(HaveLen(4))
+	})
+	It("should resume the release of the metal resources which failed on deletion", func() {
+		infrastructure := newInfrastructure()
+		cidr := nodeCIDR
+		infrastructure.Status.NodesCIDR = &cidr
+		c, a := newActuator(infrastructure)
+
+		metalClient.FailOn("IPFree", "212.1.2.3", errors.New("metal-api unavailable"))
+		metalClient.FailOn("NetworkFree", "private-network", errors.New("metal-api unavailable"))
+
+		By("releasing the remaining resources if some of them fail")
+		err := a.Delete(ctx, infrastructure, cluster)
+		Expect(err).To(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+		cause := err.(*controllererrors.RequeueAfterError).Cause
+		Expect(cause).To(MatchError(ContainSubstring("could not release ip \"212.1.2.3\"")))
+		Expect(cause).To(MatchError(ContainSubstring("could not release network \"private-network\"")))
+		Expect(cause).NotTo(MatchError(ContainSubstring("212.1.2.4")))
+		Expect(metalClient.IPs()).To(HaveLen(1))
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		status := decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.Deletion).NotTo(BeNil())
+		Expect(status.Deletion.ReleasedIPs).To(ConsistOf("212.1.2.4"))
+		Expect(status.Deletion.ReleasedNetworks).To(BeEmpty())
+		Expect(status.Deletion.Failures).To(ConsistOf(
+			metalv1alpha1.DeletionFailure{Kind: "ip", ID: "212.1.2.3", Error: "metal-api unavailable"},
+			metalv1alpha1.DeletionFailure{Kind: "network", ID: "private-network", Error: "metal-api unavailable"},
+		))
+
+		By("releasing only the failed resources on the next attempt")
+		metalClient.FailOn("IPFree", "212.1.2.3", nil)
+		metalClient.FailOn("NetworkFree", "private-network", nil)
+		metalClient.Calls = nil
+
+		Expect(a.Delete(ctx, infrastructure, cluster)).To(Succeed())
+		Expect(writes()).To(ConsistOf("IPFree", "NetworkFree"))
+		Expect(metalClient.IPs()).To(BeEmpty())
+		Expect(metalClient.Networks()).To(HaveLen(1))
+
+		Expect(c.Get(ctx, kutil.Key(namespace, infrastructure.Name), infrastructure)).To(Succeed())
+		status = decodeStatus(infrastructure.Status.ProviderStatus)
+		Expect(status.Deletion.ReleasedIPs).To(ConsistOf("212.1.2.3", "212.1.2.4"))
+		Expect(status.Deletion.ReleasedNetworks).To(ConsistOf("private-network"))
+		Expect(status.Deletion.Failures).To(BeEmpty())
+	})
+})
@@ -1,9 +1,12 @@
 package infrastructure
 
 import (
+	"time"
+
 	"github.com/gardener/gardener-extensions/pkg/controller/infrastructure"
 	"github.com/go-logr/logr"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -21,14 +24,27 @@ type AddOptions struct {
 	Controller controller.Options
 	// IgnoreOperationAnnotation specifies whether to ignore the operation annotation or not.
 	IgnoreOperationAnnotation bool
+	// MetalClientFactory creates the metal clients of the controller, metalclient.DefaultClientFactory is used if it
+	// is nil.
+	MetalClientFactory metalclient.ClientFactory
+	// FirewallProvisioningTimeout is the duration after which a firewall that has not finished provisioning is
+	// allocated again, DefaultFirewallProvisioningTimeout is used if it is zero.
+	FirewallProvisioningTimeout time.Duration
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 	logr.InfoLogger.Info(log.Log.WithName("infrastructure-actuator"), "Adding infrastructure controller")
+	actuator := NewActuator(mgr.GetEventRecorderFor(infrastructure.ControllerName), opts.FirewallProvisioningTimeout)
+	if opts.MetalClientFactory != nil {
+		if _, err := metalclient.ClientFactoryInto(opts.MetalClientFactory, actuator); err != nil {
+			return err
+		}
+	}
+
 	return infrastructure.Add(mgr, infrastructure.AddArgs{
-		Actuator:          NewActuator(),
+		Actuator:          actuator,
 		ControllerOptions: opts.Controller,
 		Predicates:        infrastructure.DefaultPredicates(opts.IgnoreOperationAnnotation),
 		Type:              metal.Type,
@@ -0,0 +1,164 @@
+package infrastructure
+
+import (
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/models"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// allocatedEgressIPTag marks the static egress ips which were allocated by the extension. Only ips with this tag are
+// released when the shoot is deleted, static ips given by the user are kept even if they carry the cluster tag.
+var allocatedEgressIPTag = metaltags.New("firewall.metal-stack.io/egress-ip", "allocated").String()
+
+// ensureEgressIPs returns the static egress ips of the firewall networks. A static ip is allocated once for every
+// egress network without configured ips. Allocated ips are tagged with the cluster such that they are found again
+// when the firewall is replaced and released when the shoot is deleted.
+func (a *actuator) ensureEgressIPs(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, infrastructureStatus *metalapi.InfrastructureStatus, cluster *extensionscontroller.Cluster, clusterTag string) ([]metalapi.FirewallEgressIPs, error) {
+	var egressIPs []metalapi.FirewallEgressIPs
+	for _, egress := range infrastructureConfig.Firewall.EgressIPs {
+		if len(egress.IPs) > 0 {
+			egressIPs = append(egressIPs, *egress.DeepCopy())
+			continue
+		}
+
+		ip, err := a.ensureAllocatedEgressIP(mclient, infrastructure, infrastructureConfig, infrastructureStatus, cluster, egress.NetworkID, clusterTag)
+		if err != nil {
+			return nil, err
+		}
+		egressIPs = append(egressIPs, metalapi.FirewallEgressIPs{
+			NetworkID: egress.NetworkID,
+			IPs:       []string{ip},
+		})
+	}
+	return egressIPs, nil
+}
+
+func (a *actuator) ensureAllocatedEgressIP(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, infrastructureStatus *metalapi.InfrastructureStatus, cluster *extensionscontroller.Cluster, networkID, clusterTag string) (string, error) {
+	ipType := metalgo.IPTypeStatic
+	resp, err := mclient.IPFind(&metalgo.IPFindRequest{
+		ProjectID: &infrastructureConfig.ProjectID,
+		NetworkID: &networkID,
+		Type:      &ipType,
+		Tags:      []string{clusterTag},
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, ip := range resp.IPs {
+		if hasTag(ip.Tags, allocatedEgressIPTag) {
+			return *ip.Ipaddress, nil
+		}
+	}
+	for _, ip := range resp.IPs {
+		// ips allocated before they were marked are only known by being the recorded egress ip of the network
+		if recordedEgressIP(infrastructureStatus, networkID, *ip.Ipaddress) {
+			if _, err := mclient.IPUpdate(&metalgo.IPUpdateRequest{
+				IPAddress:   *ip.Ipaddress,
+				Type:        metalgo.IPTypeStatic,
+				Name:        ip.Name,
+				Description: ip.Description,
+				Tags:        append(append([]string{}, ip.Tags...), allocatedEgressIPTag),
+			}); err != nil {
+				return "", err
+			}
+			return *ip.Ipaddress, nil
+		}
+	}
+
+	name := cluster.Shoot.Status.TechnicalID + "-egress"
+	allocated, err := mclient.IPAllocate(&metalgo.IPAllocateRequest{
+		Name:        name,
+		Description: name + " created by Gardener",
+		Networkid:   networkID,
+		Projectid:   infrastructureConfig.ProjectID,
+		Type:        metalgo.IPTypeStatic,
+		Tags:        []string{clusterTag, allocatedEgressIPTag},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	a.logger.Info("allocated static egress ip", "network", networkID, "ip", *allocated.IP.Ipaddress)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonEgressIPAllocated, "Allocated static egress ip %s in network %q", *allocated.IP.Ipaddress, networkID)
+	return *allocated.IP.Ipaddress, nil
+}
+
+// egressIPsAttached returns true if the given firewall holds all of the given egress ips.
+func egressIPsAttached(fw *models.V1FirewallResponse, egressIPs []metalapi.FirewallEgressIPs) bool {
+	if len(egressIPs) == 0 {
+		return true
+	}
+	if fw.Allocation == nil {
+		return false
+	}
+
+	attached := map[string]sets.String{}
+	for _, n := range fw.Allocation.Networks {
+		if n == nil || n.Networkid == nil {
+			continue
+		}
+		attached[*n.Networkid] = sets.NewString(n.Ips...)
+	}
+	for _, egress := range egressIPs {
+		ips, ok := attached[egress.NetworkID]
+		if !ok || !ips.HasAll(egress.IPs...) {
+			return false
+		}
+	}
+	return true
+}
+
+// staticEgressIPsOfCluster returns the static ips which were allocated by the extension as egress ips of the cluster.
+// Static ips given in the infrastructure config are never returned, even if they carry the tags of allocated ips.
+func staticEgressIPsOfCluster(mclient metalclient.Client, infrastructureConfig *metalapi.InfrastructureConfig, clusterTag string) ([]*models.V1IPResponse, error) {
+	ipType := metalgo.IPTypeStatic
+	resp, err := mclient.IPFind(&metalgo.IPFindRequest{
+		ProjectID: &infrastructureConfig.ProjectID,
+		Type:      &ipType,
+		Tags:      []string{clusterTag, allocatedEgressIPTag},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configured := sets.NewString()
+	for _, egress := range infrastructureConfig.Firewall.EgressIPs {
+		configured.Insert(egress.IPs...)
+	}
+
+	var ips []*models.V1IPResponse
+	for _, ip := range resp.IPs {
+		if ip.Ipaddress != nil && !configured.Has(*ip.Ipaddress) {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
+
+// recordedEgressIP returns true if the given ip is recorded as egress ip of the given network in the status.
+func recordedEgressIP(infrastructureStatus *metalapi.InfrastructureStatus, networkID, ip string) bool {
+	for _, egress := range infrastructureStatus.EgressIPs {
+		if egress.NetworkID == networkID && sets.NewString(egress.IPs...).Has(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(tags []string, t string) bool {
+	for _, s := range tags {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
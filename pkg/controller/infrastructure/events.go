@@ -0,0 +1,33 @@
+package infrastructure
+
+// Reasons of the events recorded on the Infrastructure resource. Every mutating call to the metal-api is reported
+// together with the id of the affected machine, network or ip such that shoot owners can follow what happened.
+const (
+	eventReasonFirewallCreated             = "FirewallCreated"
+	eventReasonFirewallAllocationFailed    = "FirewallAllocationFailed"
+	eventReasonFirewallDeleted             = "FirewallDeleted"
+	eventReasonFirewallDeletionFailed      = "FirewallDeletionFailed"
+	eventReasonFirewallAdopted             = "FirewallAdopted"
+	eventReasonFirewallDisappeared         = "FirewallDisappeared"
+	eventReasonFirewallProvisioningTimeout = "FirewallProvisioningTimeout"
+	eventReasonFirewallRolloutStarted      = "FirewallRolloutStarted"
+	eventReasonFirewallRolloutRestarted    = "FirewallRolloutRestarted"
+	eventReasonFirewallCredentialsRenewed  = "FirewallCredentialsRenewed"
+	eventReasonFirewallCredentialsFailed   = "FirewallCredentialsSyncFailed"
+
+	eventReasonNodeNetworkAllocated   = "NodeNetworkAllocated"
+	eventReasonNodeNetworkRejected    = "NodeNetworkRejected"
+	eventReasonNodeNetworkPrefixAdded = "NodeNetworkPrefixAdded"
+	eventReasonNodeNetworkKept        = "NodeNetworkKept"
+	eventReasonNetworkReleased        = "NetworkReleased"
+	eventReasonNetworkReleaseFailed   = "NetworkReleaseFailed"
+
+	eventReasonEgressIPAllocated = "EgressIPAllocated"
+	eventReasonIPReleased        = "IPReleased"
+	eventReasonIPReleaseFailed   = "IPReleaseFailed"
+	eventReasonIPUntagged        = "IPUntagged"
+	eventReasonIPUntagFailed     = "IPUntagFailed"
+	eventReasonIPSeedMarked      = "IPSeedMarked"
+
+	eventReasonDeletionPlanned = "DeletionPlanned"
+)
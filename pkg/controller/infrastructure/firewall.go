@@ -0,0 +1,281 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	"github.com/metal-stack/metal-go/api/models"
+
+	"github.com/coreos/container-linux-config-transpiler/config/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// firewallLivelinessAlive is the liveliness the metal-api reports for machines that send heartbeats.
+const firewallLivelinessAlive = "Alive"
+
+// firewallRulesTag is the tag of a firewall which contains the hash of the rate limit ruleset it was created with. The
+// egress rules are applied in place and are not part of the hash.
+const firewallRulesTag = "firewall.metal-stack.io/rules-hash"
+
+// firewallRulesHash returns the hash of the rendered rate limit ruleset, an empty string if there is no ruleset.
+func firewallRulesHash(rules string) string {
+	if rules == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(rules)))[:16]
+}
+
+// firewallRulesHashFromTags returns the hash of the firewall rules from the given firewall tags.
+func firewallRulesHashFromTags(tags []string) string {
+	return tagValue(tags, firewallRulesTag)
+}
+
+// tagValue returns the value of the given tag key, an empty string if the tag is not part of the given tags.
+func tagValue(tags []string, key string) string {
+	value, _ := metaltags.Value(tags, key)
+	return value
+}
+
+// firewallSnippetsTag is the tag of a firewall which contains the hash of the ignition snippets it was created with.
+const firewallSnippetsTag = "firewall.metal-stack.io/snippets-hash"
+
+// firewallSnippetsHash returns the hash of the given ignition snippets, an empty string if there are no snippets.
+func firewallSnippetsHash(snippets []types.Config) (string, error) {
+	if len(snippets) == 0 {
+		return "", nil
+	}
+	raw, err := json.Marshal(snippets)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw))[:16], nil
+}
+
+// firewallHashes contains the hashes of the parts of the user data a firewall was created with and the seed managing
+// it. They are tagged on the firewall, a firewall is replaced if one of the hashes changes. The seed is not considered,
+// firewalls of a migrated cluster are adopted by the new seed and keep the mark of the seed which created them, as the
+// tags of a machine can not be updated.
+type firewallHashes struct {
+	rules string
+	seed  string
+	// snippets contains the hash of the ignition snippets per partition, the snippets of the cloud profile are selected
+	// by partition.
+	snippets map[string]string
+}
+
+// tags returns the tags of the hashes for a firewall in the given partition.
+func (h firewallHashes) tags(partitionID string) []string {
+	var tags []string
+	if h.rules != "" {
+		tags = append(tags, metaltags.New(firewallRulesTag, h.rules).String())
+	}
+	if h.snippets[partitionID] != "" {
+		tags = append(tags, metaltags.New(firewallSnippetsTag, h.snippets[partitionID]).String())
+	}
+	if h.seed != "" {
+		tags = append(tags, metaltags.SeedName(h.seed))
+	}
+	return tags
+}
+
+// firewallReplicas returns the desired amount of firewalls for the cluster.
+func firewallReplicas(infrastructureConfig *metalapi.InfrastructureConfig) int {
+	if infrastructureConfig.Firewall.Replicas == nil {
+		return 1
+	}
+	return int(*infrastructureConfig.Firewall.Replicas)
+}
+
+// firewallPartitions returns the partitions the firewalls of the cluster are reconciled in together with the desired
+// amount of firewalls per partition. These are the partitions of the cluster followed by the partitions of the given
+// firewalls which do not belong to the cluster, their firewalls are removed.
+func firewallPartitions(infrastructureConfig *metalapi.InfrastructureConfig, firewalls []*models.V1FirewallResponse) ([]string, map[string]int) {
+	var (
+		partitionIDs = helper.PartitionIDs(infrastructureConfig)
+		replicas     = map[string]int{}
+	)
+	for _, partitionID := range partitionIDs {
+		replicas[partitionID] = firewallReplicas(infrastructureConfig)
+	}
+
+	var foreign []string
+	for _, fw := range firewalls {
+		partitionID := firewallPartition(fw)
+		if _, ok := replicas[partitionID]; !ok {
+			replicas[partitionID] = 0
+			foreign = append(foreign, partitionID)
+		}
+	}
+	sort.Strings(foreign)
+
+	return append(partitionIDs, foreign...), replicas
+}
+
+// firewallsInPartition returns the firewalls of the given partition.
+func firewallsInPartition(firewalls []*models.V1FirewallResponse, partitionID string) []*models.V1FirewallResponse {
+	var result []*models.V1FirewallResponse
+	for _, fw := range firewalls {
+		if firewallPartition(fw) == partitionID {
+			result = append(result, fw)
+		}
+	}
+	return result
+}
+
+func firewallPartition(fw *models.V1FirewallResponse) string {
+	if fw.Partition == nil || fw.Partition.ID == nil {
+		return ""
+	}
+	return *fw.Partition.ID
+}
+
+func firewallSucceeded(fw *models.V1FirewallResponse) bool {
+	return fw.Allocation != nil && fw.Allocation.Succeeded != nil && *fw.Allocation.Succeeded
+}
+
+func containsFirewall(firewalls []metalapi.FirewallStatus, machineID string) bool {
+	for _, fw := range firewalls {
+		if decodeMachineID(fw.MachineID) == machineID {
+			return true
+		}
+	}
+	return false
+}
+
+// firewallUpToDate returns true if the given firewall matches the firewall spec of the infrastructure config,
+// was created with the user data of the given hashes and holds the given egress ips.
+func firewallUpToDate(fw *models.V1FirewallResponse, infrastructureConfig *metalapi.InfrastructureConfig, hashes firewallHashes, egressIPs []metalapi.FirewallEgressIPs) bool {
+	if fw.Size == nil || fw.Size.ID == nil || fw.Allocation == nil || fw.Allocation.Image == nil || fw.Allocation.Image.ID == nil {
+		return false
+	}
+	return *fw.Size.ID == infrastructureConfig.Firewall.Size &&
+		*fw.Allocation.Image.ID == infrastructureConfig.Firewall.Image &&
+		firewallRulesHashFromTags(fw.Tags) == hashes.rules &&
+		tagValue(fw.Tags, firewallSnippetsTag) == hashes.snippets[firewallPartition(fw)] &&
+		egressIPsAttached(fw, egressIPs)
+}
+
+func findFirewall(firewalls []*models.V1FirewallResponse, machineID string) *models.V1FirewallResponse {
+	for _, fw := range firewalls {
+		if *fw.ID == machineID {
+			return fw
+		}
+	}
+	return nil
+}
+
+// getFirewalls returns the given firewalls as they are reported by the metal-api for every single firewall. Firewalls
+// without id and firewalls which were deleted in the meantime are left out.
+func getFirewalls(mclient metalclient.Client, firewalls []*models.V1FirewallResponse) ([]*models.V1FirewallResponse, error) {
+	var result []*models.V1FirewallResponse
+	for _, fw := range firewalls {
+		if fw == nil || fw.ID == nil {
+			continue
+		}
+		resp, err := mclient.FirewallGet(*fw.ID)
+		if err != nil {
+			if metalclient.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if resp.Firewall == nil || resp.Firewall.ID == nil {
+			continue
+		}
+		result = append(result, resp.Firewall)
+	}
+	return result, nil
+}
+
+func firewallStatuses(firewalls []*models.V1FirewallResponse) []metalapi.FirewallStatus {
+	var statuses []metalapi.FirewallStatus
+	for _, fw := range firewalls {
+		if fw.ID == nil {
+			continue
+		}
+		statuses = append(statuses, firewallStatus(fw))
+	}
+	return statuses
+}
+
+// firewallStatus returns the status of the given firewall as reported by the metal-api, the firewall must have an id.
+func firewallStatus(fw *models.V1FirewallResponse) metalapi.FirewallStatus {
+	partitionID := firewallPartition(fw)
+	status := metalapi.FirewallStatus{
+		MachineID: encodeMachineID(partitionID, *fw.ID),
+		Succeeded: firewallSucceeded(fw),
+		Partition: partitionID,
+		Phase:     metalapi.FirewallPhaseProvisioning,
+	}
+
+	if fw.Liveliness != nil {
+		status.Liveliness = *fw.Liveliness
+	}
+	if fw.Size != nil && fw.Size.ID != nil {
+		status.Size = *fw.Size.ID
+	}
+
+	if status.Succeeded {
+		status.Phase = metalapi.FirewallPhaseRunning
+		if status.Liveliness != firewallLivelinessAlive {
+			status.Phase = metalapi.FirewallPhaseUnhealthy
+		}
+	}
+
+	if fw.Allocation == nil {
+		return status
+	}
+
+	if fw.Allocation.Hostname != nil {
+		status.Hostname = *fw.Allocation.Hostname
+	}
+	if fw.Allocation.Image != nil && fw.Allocation.Image.ID != nil {
+		status.Image = *fw.Allocation.Image.ID
+	}
+	if fw.Allocation.Created != nil {
+		created := metav1.NewTime(time.Time(*fw.Allocation.Created))
+		status.AllocationTimestamp = &created
+	}
+	for _, n := range fw.Allocation.Networks {
+		if n == nil || n.Networkid == nil {
+			continue
+		}
+		status.Networks = append(status.Networks, metalapi.FirewallNetworkStatus{
+			NetworkID: *n.Networkid,
+			IPs:       n.Ips,
+		})
+	}
+
+	return status
+}
+
+// findUnrecordedFirewallRollout returns a rollout for an outdated firewall and an up-to-date firewall that has not yet
+// been allocated successfully, nil if there is no such pair.
+func findUnrecordedFirewallRollout(firewalls []*models.V1FirewallResponse, infrastructureConfig *metalapi.InfrastructureConfig, hashes firewallHashes, egressIPs []metalapi.FirewallEgressIPs) *metalapi.FirewallRollout {
+	var outdated, replacement *models.V1FirewallResponse
+	for _, fw := range firewalls {
+		if !firewallUpToDate(fw, infrastructureConfig, hashes, egressIPs) {
+			outdated = fw
+			continue
+		}
+		if !firewallSucceeded(fw) {
+			replacement = fw
+		}
+	}
+	if outdated == nil || replacement == nil {
+		return nil
+	}
+	return &metalapi.FirewallRollout{
+		Phase:        metalapi.FirewallRolloutPhaseProvisioning,
+		OldMachineID: encodeMachineID(firewallPartition(outdated), *outdated.ID),
+		NewMachineID: encodeMachineID(firewallPartition(replacement), *replacement.ID),
+	}
+}
@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/gardener/gardener-extensions/pkg/util"
+
+	v1alpha1constants "github.com/gardener/gardener/pkg/apis/core/v1alpha1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	"github.com/gardener/gardener/pkg/utils/secrets"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// firewallCredentialsRenewalThreshold is the remaining validity of the client certificate of the
+	// firewall-policy-controller below which the certificate is renewed.
+	firewallCredentialsRenewalThreshold = 30 * 24 * time.Hour
+	// firewallPolicyControllerKubeconfigSecretName is the name of the secret in the kube-system namespace of the shoot
+	// which contains the current kubeconfig of the firewall-policy-controller. The firewall-policy-controller reads it
+	// with its current credentials, this way renewed credentials reach the firewall without recreating it.
+	firewallPolicyControllerKubeconfigSecretName = "firewall-policy-controller-kubeconfig"
+)
+
+// ensureFirewallCredentials renews the kubeconfig of the firewall-policy-controller if its client certificate expires
+// soon or was not signed by the current cluster ca, and writes the kubeconfig into the shoot. The expiration of the
+// certificate is recorded in the given status. Nothing is done before the credentials were generated for the first
+// firewall.
+func (a *actuator) ensureFirewallCredentials(ctx context.Context, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster, infrastructureStatus *metalapi.InfrastructureStatus) error {
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, kutil.Key(infrastructure.Namespace, firewallPolicyControllerName), secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	ca := &corev1.Secret{}
+	if err := a.client.Get(ctx, kutil.Key(infrastructure.Namespace, v1alpha1constants.SecretNameCACluster), ca); err != nil {
+		return err
+	}
+
+	cert, err := firewallPolicyControllerCertificate(secret)
+	if err != nil {
+		return err
+	}
+
+	if reason := firewallCredentialsRenewalReason(secret, ca, cert, time.Now()); reason != "" {
+		a.logger.Info("renewing firewall-policy-controller credentials", "infrastructure", infrastructure.Name, "reason", reason)
+
+		if err := a.client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := a.createFirewallPolicyControllerKubeconfig(ctx, infrastructure, cluster); err != nil {
+			return err
+		}
+		if err := a.client.Get(ctx, kutil.Key(infrastructure.Namespace, firewallPolicyControllerName), secret); err != nil {
+			return err
+		}
+		cert, err = firewallPolicyControllerCertificate(secret)
+		if err != nil {
+			return err
+		}
+
+		a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonFirewallCredentialsRenewed, "Renewed the credentials of the firewall-policy-controller because %s", reason)
+	}
+
+	status := &metalapi.FirewallCredentialsStatus{
+		ExpirationTime: metav1.NewTime(cert.NotAfter),
+	}
+	if old := infrastructureStatus.FirewallCredentials; old != nil && old.ExpirationTime.Equal(&status.ExpirationTime) {
+		status.LastSyncTime = old.LastSyncTime
+	}
+	infrastructureStatus.FirewallCredentials = status
+
+	result, err := a.syncFirewallKubeconfig(ctx, infrastructure.Namespace, cluster, secret.Data[secrets.DataKeyKubeconfig])
+	if err != nil {
+		// the api server of the shoot is not reachable before the control plane is deployed or while the shoot wakes up
+		// from hibernation, the firewalls keep their current credentials until the next reconciliation
+		a.logger.Error(err, "could not write firewall-policy-controller kubeconfig into the shoot", "infrastructure", infrastructure.Name)
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallCredentialsFailed, "Could not write the kubeconfig of the firewall-policy-controller into the shoot: %v", err)
+		return nil
+	}
+	if result == controllerutil.OperationResultCreated || result == controllerutil.OperationResultUpdated ||
+		(result == controllerutil.OperationResultNone && status.LastSyncTime == nil) {
+		now := metav1.Now()
+		status.LastSyncTime = &now
+	}
+
+	return nil
+}
+
+// syncFirewallKubeconfig writes the given kubeconfig into the shoot and returns how the secret in the shoot was
+// changed. Hibernated shoots and shoots without a control plane are skipped, an empty result is returned for them.
+func (a *actuator) syncFirewallKubeconfig(ctx context.Context, namespace string, cluster *extensionscontroller.Cluster, kubeconfig []byte) (controllerutil.OperationResult, error) {
+	if extensionscontroller.IsHibernated(cluster) {
+		return "", nil
+	}
+
+	_, shootClient, err := util.NewClientForShoot(ctx, a.client, namespace, client.Options{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      firewallPolicyControllerKubeconfigSecretName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			secrets.DataKeyKubeconfig: kubeconfig,
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// firewallPolicyControllerCertificate returns the client certificate of the firewall-policy-controller contained in
+// the given secret.
+func firewallPolicyControllerCertificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	data, ok := secret.Data[fmt.Sprintf("%s.crt", firewallPolicyControllerName)]
+	if !ok {
+		return nil, fmt.Errorf("certificate not part of firewall policy controller secret")
+	}
+	return utils.DecodeCertificate(data)
+}
+
+// firewallCredentialsRenewalReason returns why the credentials of the firewall-policy-controller have to be renewed,
+// an empty string is returned if they are still valid.
+func firewallCredentialsRenewalReason(secret, ca *corev1.Secret, cert *x509.Certificate, now time.Time) string {
+	if !bytes.Equal(secret.Data[secrets.DataKeyCertificateCA], ca.Data[secrets.DataKeyCertificateCA]) {
+		return "the cluster ca changed"
+	}
+	if cert.NotAfter.Sub(now) < firewallCredentialsRenewalThreshold {
+		return fmt.Sprintf("the certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/gardener/gardener/pkg/utils/secrets"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Firewall credentials", func() {
+	Describe("#firewallCredentialsRenewalReason", func() {
+		var (
+			ca     *secrets.Certificate
+			cert   *secrets.Certificate
+			secret *corev1.Secret
+		)
+
+		BeforeEach(func() {
+			var err error
+			ca, err = (&secrets.CertificateSecretConfig{
+				Name:       "ca",
+				CommonName: "kubernetes",
+				CertType:   secrets.CACert,
+			}).GenerateCertificate()
+			Expect(err).NotTo(HaveOccurred())
+
+			cert, err = (&secrets.CertificateSecretConfig{
+				Name:       firewallPolicyControllerName,
+				CommonName: "system:" + firewallPolicyControllerName,
+				CertType:   secrets.ClientCert,
+				SigningCA:  ca,
+			}).GenerateCertificate()
+			Expect(err).NotTo(HaveOccurred())
+
+			secret = &corev1.Secret{Data: cert.SecretData()}
+		})
+
+		It("should keep valid credentials", func() {
+			Expect(firewallCredentialsRenewalReason(secret, &corev1.Secret{Data: ca.SecretData()}, cert.Certificate, time.Now())).To(BeEmpty())
+		})
+
+		It("should renew credentials which expire soon", func() {
+			now := cert.Certificate.NotAfter.Add(-24 * time.Hour)
+
+			Expect(firewallCredentialsRenewalReason(secret, &corev1.Secret{Data: ca.SecretData()}, cert.Certificate, now)).To(ContainSubstring("expires"))
+		})
+
+		It("should renew credentials if the cluster ca changed", func() {
+			newCA, err := (&secrets.CertificateSecretConfig{
+				Name:       "ca",
+				CommonName: "kubernetes",
+				CertType:   secrets.CACert,
+			}).GenerateCertificate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(firewallCredentialsRenewalReason(secret, &corev1.Secret{Data: newCA.SecretData()}, cert.Certificate, time.Now())).To(Equal("the cluster ca changed"))
+		})
+	})
+})
@@ -0,0 +1,183 @@
+package infrastructure
+
+import (
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/ignition"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/metal-go/api/models"
+
+	"github.com/coreos/container-linux-config-transpiler/config/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Firewall partitions", func() {
+	newFirewall := func(id, partition string) *models.V1FirewallResponse {
+		return &models.V1FirewallResponse{
+			ID:        &id,
+			Partition: &models.V1PartitionResponse{ID: &partition},
+		}
+	}
+
+	It("should desire the firewall replicas in every partition of the cluster", func() {
+		replicas := int32(2)
+		infrastructureConfig := &metalapi.InfrastructureConfig{
+			PartitionID:            "partition-a",
+			AdditionalPartitionIDs: []string{"partition-b"},
+			Firewall:               metalapi.Firewall{Replicas: &replicas},
+		}
+
+		partitionIDs, desired := firewallPartitions(infrastructureConfig, nil)
+		Expect(partitionIDs).To(Equal([]string{"partition-a", "partition-b"}))
+		Expect(desired).To(Equal(map[string]int{"partition-a": 2, "partition-b": 2}))
+	})
+
+	It("should remove the firewalls of partitions which do not belong to the cluster", func() {
+		infrastructureConfig := &metalapi.InfrastructureConfig{PartitionID: "partition-a"}
+		firewalls := []*models.V1FirewallResponse{
+			newFirewall("firewall-1", "partition-a"),
+			newFirewall("firewall-2", "partition-c"),
+			newFirewall("firewall-3", "partition-b"),
+		}
+
+		partitionIDs, desired := firewallPartitions(infrastructureConfig, firewalls)
+		Expect(partitionIDs).To(Equal([]string{"partition-a", "partition-b", "partition-c"}))
+		Expect(desired).To(Equal(map[string]int{"partition-a": 1, "partition-b": 0, "partition-c": 0}))
+
+		Expect(firewallsInPartition(firewalls, "partition-c")).To(ConsistOf(firewalls[1]))
+	})
+})
+
+var _ = Describe("Firewall status", func() {
+	It("should report firewalls without partition and skip firewalls without id", func() {
+		id := "firewall-1"
+		statuses := firewallStatuses([]*models.V1FirewallResponse{
+			{ID: &id},
+			{},
+		})
+
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].MachineID).To(Equal(encodeMachineID("", id)))
+		Expect(statuses[0].Partition).To(BeEmpty())
+		Expect(statuses[0].Phase).To(Equal(metalapi.FirewallPhaseProvisioning))
+	})
+})
+
+var _ = Describe("Firewall ignition snippets", func() {
+	var (
+		size                 = "c1-xlarge-x86"
+		image                = "firewall-1"
+		infrastructureConfig = &metalapi.InfrastructureConfig{
+			PartitionID: "partition-a",
+			Firewall:    metalapi.Firewall{Size: size, Image: image},
+		}
+	)
+
+	newFirewall := func(tags ...string) *models.V1FirewallResponse {
+		return &models.V1FirewallResponse{
+			ID:         &[]string{"firewall-1"}[0],
+			Partition:  &models.V1PartitionResponse{ID: &infrastructureConfig.PartitionID},
+			Size:       &models.V1SizeResponse{ID: &size},
+			Tags:       tags,
+			Allocation: &models.V1MachineAllocation{Image: &models.V1ImageResponse{ID: &image}},
+		}
+	}
+
+	snippetsHash := func(data ...string) string {
+		var snippets []types.Config
+		for _, d := range data {
+			snippet, err := ignition.ParseSnippet(d)
+			Expect(err).NotTo(HaveOccurred())
+			snippets = append(snippets, snippet)
+		}
+		hash, err := firewallSnippetsHash(snippets)
+		Expect(err).NotTo(HaveOccurred())
+		return hash
+	}
+
+	It("should replace firewalls whose snippets changed", func() {
+		created := firewallHashes{snippets: map[string]string{"partition-a": snippetsHash("systemd:\n  units:\n  - name: a.service\n    enabled: true\n")}}
+		changed := firewallHashes{snippets: map[string]string{"partition-a": snippetsHash("systemd:\n  units:\n  - name: b.service\n    enabled: true\n")}}
+		fw := newFirewall(created.tags("partition-a")...)
+
+		Expect(firewallUpToDate(fw, infrastructureConfig, created, nil)).To(BeTrue())
+		Expect(firewallUpToDate(fw, infrastructureConfig, changed, nil)).To(BeFalse())
+		Expect(firewallUpToDate(fw, infrastructureConfig, firewallHashes{}, nil)).To(BeFalse())
+	})
+
+	It("should not tag firewalls without snippets", func() {
+		Expect(snippetsHash()).To(BeEmpty())
+		Expect(firewallUpToDate(newFirewall(), infrastructureConfig, firewallHashes{snippets: map[string]string{"partition-b": snippetsHash("systemd: {}\n")}}, nil)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Firewall rules", func() {
+	var (
+		internet = "internet"
+		mpls     = "mpls"
+		mclient  *metalfake.Client
+	)
+
+	BeforeEach(func() {
+		mclient = metalfake.NewClient()
+		mclient.AddNetwork(&models.V1NetworkResponse{ID: &internet, Vrf: 104009})
+		mclient.AddNetwork(&models.V1NetworkResponse{ID: &mpls, Vrf: 104010})
+	})
+
+	It("should limit the traffic entering from the vrf of the network", func() {
+		ruleset, err := renderFirewallRules(mclient, &metalapi.FirewallRules{
+			RateLimits: []metalapi.RateLimit{{NetworkID: internet, RateLimit: 100}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleset).To(Equal(`table inet firewall-rules {
+	chain ingress {
+		type filter hook prerouting priority 0; policy accept;
+		meta iifname "vrf104009" limit rate over 12500 kbytes/second drop
+	}
+}
+`))
+	})
+
+	It("should only allow the ingress traffic of the rules from the networks with ingress rules", func() {
+		ruleset, err := renderFirewallRules(mclient, &metalapi.FirewallRules{
+			Ingress: []metalapi.IngressRule{
+				{NetworkID: mpls, Protocol: metalapi.FirewallProtocolUDP},
+				{NetworkID: internet, Protocol: metalapi.FirewallProtocolTCP, CIDRs: []string{"212.34.0.0/16", "2001:db8::/32", "10.0.0.0/8"}, Ports: []int32{22, 443}},
+				{NetworkID: internet, Protocol: metalapi.FirewallProtocolUDP, Ports: []int32{53}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleset).To(Equal(`table inet firewall-rules {
+	chain forward {
+		type filter hook forward priority 0; policy accept;
+		ct state established,related accept
+		meta iifname "vrf104010" meta l4proto udp accept
+		meta iifname "vrf104009" ip saddr { 212.34.0.0/16, 10.0.0.0/8 } tcp dport { 22, 443 } accept
+		meta iifname "vrf104009" ip6 saddr { 2001:db8::/32 } tcp dport { 22, 443 } accept
+		meta iifname "vrf104009" udp dport { 53 } accept
+		meta iifname "vrf104009" drop
+		meta iifname "vrf104010" drop
+	}
+}
+`))
+	})
+
+	It("should fail for networks without vrf", func() {
+		external := "external"
+		mclient.AddNetwork(&models.V1NetworkResponse{ID: &external})
+
+		_, err := renderFirewallRules(mclient, &metalapi.FirewallRules{
+			Ingress: []metalapi.IngressRule{{NetworkID: external, Protocol: metalapi.FirewallProtocolTCP}},
+		})
+		Expect(err).To(MatchError(`network "external" of firewall rule has no vrf`))
+	})
+
+	It("should not render a ruleset without ingress rules and rate limits", func() {
+		ruleset, err := renderFirewallRules(mclient, &metalapi.FirewallRules{
+			Egress: []metalapi.EgressRule{{Protocol: metalapi.FirewallProtocolTCP, CIDRs: []string{"0.0.0.0/0"}}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleset).To(BeEmpty())
+	})
+})
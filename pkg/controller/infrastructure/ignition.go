@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/ignition"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	"github.com/coreos/container-linux-config-transpiler/config/types"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// firewallIgnitionSnippets returns the ignition snippets which are merged into the user data of the firewall. These are
+// the snippets of the cloud profile matching the given partition and the image of the firewall in the order they are
+// referenced, followed by the snippet of the infrastructure config.
+func (a *actuator) firewallIgnitionSnippets(ctx context.Context, infrastructureConfig *metalapi.InfrastructureConfig, partitionID string, cluster *extensionscontroller.Cluster) ([]types.Config, error) {
+	cloudProfileConfig, err := helper.CloudProfileConfigFromCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var snippets []types.Config
+	if cloudProfileConfig != nil {
+		for _, ref := range cloudProfileConfig.FirewallIgnitionSnippets {
+			if len(ref.Partitions) > 0 && !sets.NewString(ref.Partitions...).Has(partitionID) {
+				continue
+			}
+			if len(ref.Images) > 0 && !sets.NewString(ref.Images...).Has(infrastructureConfig.Firewall.Image) {
+				continue
+			}
+
+			cm := &corev1.ConfigMap{}
+			if err := a.client.Get(ctx, client.ObjectKey{Namespace: ref.ConfigMapRef.Namespace, Name: ref.ConfigMapRef.Name}, cm); err != nil {
+				return nil, fmt.Errorf("could not get config map %s/%s with ignition snippets: %w", ref.ConfigMapRef.Namespace, ref.ConfigMapRef.Name, err)
+			}
+
+			keys := make([]string, 0, len(cm.Data))
+			for key := range cm.Data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				snippet, err := ignition.ParseSnippet(cm.Data[key])
+				if err != nil {
+					return nil, fmt.Errorf("invalid ignition snippet %q in config map %s/%s: %w", key, ref.ConfigMapRef.Namespace, ref.ConfigMapRef.Name, err)
+				}
+				snippets = append(snippets, snippet)
+			}
+		}
+	}
+
+	if infrastructureConfig.Firewall.IgnitionSnippet != nil {
+		snippet, err := ignition.ParseSnippet(*infrastructureConfig.Firewall.IgnitionSnippet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignition snippet in infrastructure config: %w", err)
+		}
+		snippets = append(snippets, snippet)
+	}
+
+	return snippets, nil
+}
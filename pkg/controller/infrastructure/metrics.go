@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	operationReconcile = "reconcile"
+	operationDelete    = "delete"
+	operationMigrate   = "migrate"
+
+	resultSucceeded = "succeeded"
+	resultRequeued  = "requeued"
+	resultFailed    = "failed"
+)
+
+var (
+	provisioningFirewalls = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "metal_firewalls_provisioning",
+			Help: "Number of firewalls of an infrastructure whose allocation has not yet succeeded.",
+		},
+		[]string{"shoot_namespace"},
+	)
+
+	infrastructureOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_infrastructure_operations_total",
+			Help: "Total number of infrastructure reconciliations, deletions and migrations by their result.",
+		},
+		[]string{"operation", "result"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(provisioningFirewalls, infrastructureOperations)
+}
+
+// recordOperation counts an operation of the infrastructure actuator by its result and returns the given error.
+func recordOperation(operation string, err error) error {
+	result := resultSucceeded
+	if err != nil {
+		result = resultFailed
+		if _, ok := err.(*controllererrors.RequeueAfterError); ok {
+			result = resultRequeued
+		}
+	}
+	infrastructureOperations.WithLabelValues(operation, result).Inc()
+	return err
+}
+
+// recordProvisioningFirewalls sets the number of firewalls of the infrastructure in the given namespace whose
+// allocation has not yet succeeded.
+func recordProvisioningFirewalls(namespace string, firewalls []*models.V1FirewallResponse) {
+	var count int
+	for _, fw := range firewalls {
+		if !firewallSucceeded(fw) {
+			count++
+		}
+	}
+	provisioningFirewalls.WithLabelValues(namespace).Set(float64(count))
+}
+
+// forgetProvisioningFirewalls removes the number of provisioning firewalls of the infrastructure in the given
+// namespace, e.g. after it was deleted.
+func forgetProvisioningFirewalls(namespace string) {
+	provisioningFirewalls.DeleteLabelValues(namespace)
+}
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+)
+
+// defaultPrivateNetworkPrefixLength is the length of the private networks of partitions which were created without a
+// length, the metal-api defaults to it.
+const defaultPrivateNetworkPrefixLength = 22
+
+// nodeCIDRFromPrefixes returns the prefix of the node network which is used as nodes cidr of the cluster, this is the
+// first IPv4 prefix as the nodes cidr of the shoot can only hold a single prefix.
+func nodeCIDRFromPrefixes(prefixes []string) (string, error) {
+	if len(prefixes) == 0 {
+		return "", fmt.Errorf("node network has no prefixes")
+	}
+	for _, prefix := range prefixes {
+		if !isIPv6Prefix(prefix) {
+			return prefix, nil
+		}
+	}
+	return prefixes[0], nil
+}
+
+// containsIPv6Prefix returns true if one of the given prefixes is an IPv6 prefix.
+func containsIPv6Prefix(prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if isIPv6Prefix(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIPv6Prefix(prefix string) bool {
+	ip, _, err := net.ParseCIDR(prefix)
+	return err == nil && ip.To4() == nil
+}
+
+func dualStack(infrastructureConfig *metalapi.InfrastructureConfig) bool {
+	return infrastructureConfig.NodeNetwork != nil && infrastructureConfig.NodeNetwork.DualStack
+}
@@ -0,0 +1,163 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeletionPlanAnnotation is the annotation of an infrastructure which requests the deletion plan to be computed on the
+// next reconciliation. The plan is written into the provider status and reported as an event.
+const DeletionPlanAnnotation = "metal.provider.extensions.gardener.cloud/plan-deletion"
+
+// deletionPlan contains the metal resources that are released when an infrastructure is deleted.
+type deletionPlan struct {
+	firewalls   []*models.V1FirewallResponse
+	ipsToFree   []*models.V1IPResponse
+	ipsToUpdate []*models.V1IPResponse
+	networks    []*models.V1NetworkResponse
+}
+
+// planDeletion looks up the metal resources of the cluster that are released when the infrastructure is deleted.
+// Node networks which were provided by the user are not part of the plan.
+func planDeletion(mclient metalclient.Client, infrastructureConfig *metalapi.InfrastructureConfig, clusterID string, nodesCIDR *string) (*deletionPlan, error) {
+	clusterTag := metaltags.ClusterID(clusterID)
+
+	resp, err := mclient.FirewallFind(&metalgo.FirewallFindRequest{
+		MachineFindRequest: metalgo.MachineFindRequest{
+			AllocationProject: &infrastructureConfig.ProjectID,
+			Tags:              []string{clusterTag},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query firewalls: %w", err)
+	}
+
+	ipsToFree, ipsToUpdate, err := metalclient.GetEphemeralIPsFromCluster(mclient, infrastructureConfig.ProjectID, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ephemeral cluster ips: %w", err)
+	}
+
+	// static egress ips allocated for the cluster are kept for replacements of the firewall until the shoot is deleted
+	egressIPs, err := staticEgressIPsOfCluster(mclient, infrastructureConfig, clusterTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query static egress ips: %w", err)
+	}
+
+	plan := &deletionPlan{
+		firewalls:   resp.Firewalls,
+		ipsToFree:   append(ipsToFree, egressIPs...),
+		ipsToUpdate: ipsToUpdate,
+	}
+
+	// the node networks of the additional partitions are always allocated for the cluster
+	for _, partitionID := range infrastructureConfig.AdditionalPartitionIDs {
+		partitionID := partitionID
+		resp, err := mclient.NetworkFind(&metalgo.NetworkFindRequest{
+			ProjectID:   &infrastructureConfig.ProjectID,
+			PartitionID: &partitionID,
+			Labels:      map[string]string{tag.ClusterID: clusterID},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query node networks of partition %q: %w", partitionID, err)
+		}
+		plan.networks = append(plan.networks, resp.Networks...)
+	}
+
+	if nodesCIDR == nil {
+		return plan, nil
+	}
+
+	privateNetworks, err := metalclient.GetPrivateNetworksFromNodeNetwork(mclient, infrastructureConfig.ProjectID, *nodesCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query private network: %w", err)
+	}
+	for _, pn := range privateNetworks {
+		if infrastructureConfig.NodeNetworkID != nil && *pn.ID == *infrastructureConfig.NodeNetworkID {
+			continue
+		}
+		plan.networks = append(plan.networks, pn)
+	}
+
+	return plan, nil
+}
+
+// status returns the deletion plan as it is reported in the infrastructure status.
+func (p *deletionPlan) status(now time.Time) *metalapi.DeletionPlan {
+	status := &metalapi.DeletionPlan{
+		Timestamp: metav1.NewTime(now),
+	}
+	for _, fw := range p.firewalls {
+		status.Firewalls = append(status.Firewalls, *fw.ID)
+	}
+	for _, ip := range p.ipsToFree {
+		status.IPsToFree = append(status.IPsToFree, *ip.Ipaddress)
+	}
+	for _, ip := range p.ipsToUpdate {
+		status.IPsToUpdate = append(status.IPsToUpdate, *ip.Ipaddress)
+	}
+	for _, nw := range p.networks {
+		status.Networks = append(status.Networks, *nw.ID)
+	}
+	return status
+}
+
+// PlanDeletion returns the metal resources that are released when the given infrastructure is deleted without
+// changing any of them. The metal client is created by the given client factory.
+func PlanDeletion(ctx context.Context, k8sClient client.Client, metalClientFactory metalclient.ClientFactory, infrastructure *extensionsv1alpha1.Infrastructure, cluster *extensionscontroller.Cluster) (*metalapi.DeletionPlan, error) {
+	infrastructureConfig, err := helper.InfrastructureConfigFromInfrastructure(infrastructure)
+	if err != nil {
+		return nil, err
+	}
+
+	mclient, err := metalClientFactory.NewClient(ctx, k8sClient, &infrastructure.Spec.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planDeletion(mclient, infrastructureConfig, string(cluster.Shoot.GetUID()), infrastructure.Status.NodesCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.status(time.Now()), nil
+}
+
+// recordDeletionPlan computes the deletion plan of the infrastructure, writes it into the provider status, reports it
+// as an event and removes the annotation which requested it.
+func (a *actuator) recordDeletionPlan(ctx context.Context, mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, infrastructureStatus *metalapi.InfrastructureStatus, clusterID string) error {
+	plan, err := planDeletion(mclient, infrastructureConfig, clusterID, infrastructure.Status.NodesCIDR)
+	if err != nil {
+		return err
+	}
+
+	infrastructureStatus.DeletionPlan = plan.status(time.Now())
+	if err := a.updateProviderStatus(ctx, infrastructure, infrastructureStatus, infrastructure.Status.NodesCIDR); err != nil {
+		return err
+	}
+
+	a.logger.Info("computed deletion plan", "infrastructure", infrastructure.Name, "firewalls", infrastructureStatus.DeletionPlan.Firewalls,
+		"ipsToFree", infrastructureStatus.DeletionPlan.IPsToFree, "ipsToUpdate", infrastructureStatus.DeletionPlan.IPsToUpdate, "networks", infrastructureStatus.DeletionPlan.Networks)
+	a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonDeletionPlanned, "Deletion deletes firewalls %v, frees ips %v, untags ips %v and releases networks %v",
+		infrastructureStatus.DeletionPlan.Firewalls, infrastructureStatus.DeletionPlan.IPsToFree, infrastructureStatus.DeletionPlan.IPsToUpdate, infrastructureStatus.DeletionPlan.Networks)
+
+	patch := client.MergeFrom(infrastructure.DeepCopy())
+	delete(infrastructure.Annotations, DeletionPlanAnnotation)
+	return a.client.Patch(ctx, infrastructure, patch)
+}
@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"fmt"
+	"time"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	"github.com/metal-stack/metal-go/api/models"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultFirewallProvisioningTimeout is the default duration after which a firewall that has not finished
+// provisioning is deleted and allocated again.
+const DefaultFirewallProvisioningTimeout = 30 * time.Minute
+
+// firewallProvisioningTimedOut returns true if the allocation of the given firewall has not succeeded within the
+// given timeout. Firewalls without allocation timestamp are never considered as timed out.
+func firewallProvisioningTimedOut(fw *models.V1FirewallResponse, timeout time.Duration, now time.Time) bool {
+	if firewallSucceeded(fw) || fw.Allocation == nil || fw.Allocation.Created == nil {
+		return false
+	}
+	return now.Sub(time.Time(*fw.Allocation.Created)) > timeout
+}
+
+// deleteStuckFirewalls deletes the firewalls whose provisioning timed out such that they are allocated again, the
+// timeout is recorded in the given status. It returns the remaining firewalls.
+func (a *actuator) deleteStuckFirewalls(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureStatus *metalapi.InfrastructureStatus, firewalls []*models.V1FirewallResponse) ([]*models.V1FirewallResponse, error) {
+	var (
+		now       = time.Now()
+		remaining []*models.V1FirewallResponse
+	)
+	for _, fw := range firewalls {
+		if !firewallProvisioningTimedOut(fw, a.firewallProvisioningTimeout, now) {
+			remaining = append(remaining, fw)
+			continue
+		}
+
+		reason := fmt.Sprintf("firewall %q was not provisioned within %s", *fw.ID, a.firewallProvisioningTimeout)
+		a.logger.Info("firewall provisioning timed out, deleting the firewall to allocate a new one", "infrastructure", infrastructure.Name, "machineid", *fw.ID)
+
+		_, err := mclient.MachineDelete(*fw.ID)
+		if metalclient.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+
+		recordFirewallProvisioningError(infrastructureStatus, reason, now)
+		a.recorder.Eventf(infrastructure, corev1.EventTypeWarning, eventReasonFirewallProvisioningTimeout, "Deleted firewall %q because it was not provisioned within %s, allocating a new one", *fw.ID, a.firewallProvisioningTimeout)
+	}
+	return remaining, nil
+}
+
+// recordFirewallAllocation counts a firewall allocation in the provisioning status.
+func recordFirewallAllocation(infrastructureStatus *metalapi.InfrastructureStatus) {
+	if infrastructureStatus.FirewallProvisioning == nil {
+		infrastructureStatus.FirewallProvisioning = &metalapi.FirewallProvisioningStatus{}
+	}
+	infrastructureStatus.FirewallProvisioning.Attempts++
+}
+
+// recordFirewallProvisioningError records why the last firewall allocation failed in the provisioning status.
+func recordFirewallProvisioningError(infrastructureStatus *metalapi.InfrastructureStatus, reason string, now time.Time) {
+	if infrastructureStatus.FirewallProvisioning == nil {
+		infrastructureStatus.FirewallProvisioning = &metalapi.FirewallProvisioningStatus{}
+	}
+	errorTime := metav1.NewTime(now)
+	infrastructureStatus.FirewallProvisioning.LastError = reason
+	infrastructureStatus.FirewallProvisioning.LastErrorTime = &errorTime
+}
+
+// provisioningFirewall returns a firewall whose allocation has not yet succeeded, nil if all firewalls are provisioned.
+func provisioningFirewall(firewalls []*models.V1FirewallResponse) *models.V1FirewallResponse {
+	for _, fw := range firewalls {
+		if !firewallSucceeded(fw) {
+			return fw
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"time"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/metal-go/api/models"
+
+	"github.com/go-openapi/strfmt"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Firewall provisioning", func() {
+	newFirewall := func(id string, succeeded bool, created time.Time) *models.V1FirewallResponse {
+		partition := "partition-a"
+		allocated := strfmt.DateTime(created)
+		return &models.V1FirewallResponse{
+			ID:        &id,
+			Partition: &models.V1PartitionResponse{ID: &partition},
+			Allocation: &models.V1MachineAllocation{
+				Created:   &allocated,
+				Succeeded: &succeeded,
+			},
+		}
+	}
+
+	Describe("#firewallProvisioningTimedOut", func() {
+		now := time.Now()
+
+		It("should not consider provisioned firewalls", func() {
+			Expect(firewallProvisioningTimedOut(newFirewall("fw", true, now.Add(-time.Hour)), time.Minute, now)).To(BeFalse())
+		})
+
+		It("should not consider firewalls within the timeout", func() {
+			Expect(firewallProvisioningTimedOut(newFirewall("fw", false, now.Add(-30*time.Second)), time.Minute, now)).To(BeFalse())
+		})
+
+		It("should detect firewalls exceeding the timeout", func() {
+			Expect(firewallProvisioningTimedOut(newFirewall("fw", false, now.Add(-2*time.Minute)), time.Minute, now)).To(BeTrue())
+		})
+
+		It("should not consider firewalls without allocation timestamp", func() {
+			fw := newFirewall("fw", false, now)
+			fw.Allocation.Created = nil
+			Expect(firewallProvisioningTimedOut(fw, time.Minute, now)).To(BeFalse())
+		})
+	})
+
+	Describe("#deleteStuckFirewalls", func() {
+		It("should delete stuck firewalls and record the timeout", func() {
+			var (
+				now            = time.Now()
+				recorder       = record.NewFakeRecorder(10)
+				metalClient    = metalfake.NewClient()
+				stuck          = newFirewall("stuck", false, now.Add(-time.Hour))
+				provisioning   = newFirewall("provisioning", false, now)
+				running        = newFirewall("running", true, now.Add(-time.Hour))
+				infrastructure = &extensionsv1alpha1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "infrastructure"}}
+				status         = &metalapi.InfrastructureStatus{
+					FirewallProvisioning: &metalapi.FirewallProvisioningStatus{Attempts: 1},
+				}
+				a = &actuator{
+					logger:                      log.Log.WithName("test"),
+					recorder:                    recorder,
+					firewallProvisioningTimeout: 10 * time.Minute,
+				}
+			)
+			for _, fw := range []*models.V1FirewallResponse{stuck, provisioning, running} {
+				metalClient.AddFirewall(fw)
+			}
+
+			remaining, err := a.deleteStuckFirewalls(metalClient, infrastructure, status, []*models.V1FirewallResponse{stuck, provisioning, running})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(ConsistOf(provisioning, running))
+			Expect(metalClient.Firewalls()).To(ConsistOf(provisioning, running))
+
+			Expect(status.FirewallProvisioning.Attempts).To(BeEquivalentTo(1))
+			Expect(status.FirewallProvisioning.LastError).To(Equal(`firewall "stuck" was not provisioned within 10m0s`))
+			Expect(status.FirewallProvisioning.LastErrorTime).NotTo(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("FirewallProvisioningTimeout")))
+		})
+
+		It("should ignore firewalls which are gone already", func() {
+			a := &actuator{
+				logger:                      log.Log.WithName("test"),
+				recorder:                    record.NewFakeRecorder(10),
+				firewallProvisioningTimeout: time.Minute,
+			}
+			status := &metalapi.InfrastructureStatus{}
+
+			remaining, err := a.deleteStuckFirewalls(metalfake.NewClient(), &extensionsv1alpha1.Infrastructure{}, status, []*models.V1FirewallResponse{
+				newFirewall("stuck", false, time.Now().Add(-time.Hour)),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(BeEmpty())
+			Expect(status.FirewallProvisioning).NotTo(BeNil())
+		})
+	})
+})
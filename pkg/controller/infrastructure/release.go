@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sync"
+
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+)
+
+const (
+	// releaseWorkers is the maximum number of metal resources which are released concurrently on deletion.
+	releaseWorkers = 10
+
+	releaseKindIP      = "ip"
+	releaseKindNetwork = "network"
+)
+
+// releaseTask releases a single metal resource of the cluster.
+type releaseTask struct {
+	kind    string
+	id      string
+	release func() error
+}
+
+// releaseAll runs the given tasks with at most the given number of workers. The error of every task is returned at the
+// index of the task, nil if the resource was released.
+func releaseAll(tasks []releaseTask, workers int) []error {
+	errs := make([]error, len(tasks))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(tasks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = tasks[i].release()
+			}
+		}()
+	}
+
+	for i := range tasks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// recordRelease records the results of the given tasks in the deletion status and returns an error for every resource
+// which could not be released.
+func recordRelease(status *metalapi.DeletionStatus, tasks []releaseTask, errs []error) []error {
+	var failed []error
+	for i, task := range tasks {
+		if errs[i] != nil {
+			status.Failures = append(status.Failures, metalapi.DeletionFailure{
+				Kind:  task.kind,
+				ID:    task.id,
+				Error: errs[i].Error(),
+			})
+			failed = append(failed, fmt.Errorf("could not release %s %q: %v", task.kind, task.id, errs[i]))
+			continue
+		}
+		switch task.kind {
+		case releaseKindIP:
+			status.ReleasedIPs = append(status.ReleasedIPs, task.id)
+		case releaseKindNetwork:
+			status.ReleasedNetworks = append(status.ReleasedNetworks, task.id)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	metalapi "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// seedName returns the name of the seed the cluster is hosted on, an empty string if it is not known. Firewalls and
+// networks are marked with it when they are created, such that only the orphan collector of this seed considers them.
+func seedName(cluster *extensionscontroller.Cluster) string {
+	if cluster.Seed == nil {
+		return ""
+	}
+	return cluster.Seed.Name
+}
+
+// ensureEphemeralIPsOfSeed marks the ephemeral ips which are only used by the cluster as managed by the given seed. The
+// ips are allocated by the cloud-controller-manager which does not know the seed, the mark is also moved to the seed
+// a cluster was migrated to this way.
+func (a *actuator) ensureEphemeralIPsOfSeed(mclient metalclient.Client, infrastructure *extensionsv1alpha1.Infrastructure, infrastructureConfig *metalapi.InfrastructureConfig, clusterID, seed string) error {
+	if seed == "" {
+		return nil
+	}
+
+	ips, _, err := metalclient.GetEphemeralIPsFromCluster(mclient, infrastructureConfig.ProjectID, clusterID)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if metaltags.IsManagedBySeed(ip.Tags, seed) {
+			continue
+		}
+		if err := metalclient.SetIPSeed(mclient, ip, seed); err != nil {
+			return err
+		}
+		a.logger.Info("marked ephemeral ip as managed by this seed", "clusterid", clusterID, "ip", *ip.Ipaddress, "seed", seed)
+		a.recorder.Eventf(infrastructure, corev1.EventTypeNormal, eventReasonIPSeedMarked, "Marked ephemeral ip %s as managed by seed %q", *ip.Ipaddress, seed)
+	}
+	return nil
+}
+
+// nodeNetworkLabels returns the labels of the node network of the cluster with the given id hosted on the given seed.
+func nodeNetworkLabels(clusterID, seed string) map[string]string {
+	labels := map[string]string{tag.ClusterID: clusterID}
+	if seed != "" {
+		labels[metaltags.Seed] = seed
+	}
+	return labels
+}
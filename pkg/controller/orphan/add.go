@@ -0,0 +1,56 @@
+package orphan
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// ControllerName is the name of the controller which collects orphaned metal resources.
+const ControllerName = "orphan_collector"
+
+var (
+	// DefaultAddOptions are the default AddOptions for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
+
+// AddOptions are options to apply when adding the orphan collector to the manager.
+type AddOptions struct {
+	// OrphanCollection is the configuration of the orphan collector, the collector is not added if it is nil.
+	OrphanCollection *config.OrphanCollection
+	// MetalClientFactory creates the metal clients of the controller, metalclient.DefaultClientFactory is used if it
+	// is nil.
+	MetalClientFactory metalclient.ClientFactory
+}
+
+// AddToManagerWithOptions adds the orphan collector with the given Options to the given manager.
+func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
+	logger := log.Log.WithName("metal-orphan-collector")
+	if opts.OrphanCollection == nil {
+		logr.InfoLogger.Info(logger, "Orphan collection is not configured, not adding orphan collector")
+		return nil
+	}
+
+	if opts.OrphanCollection.SeedName == "" {
+		return fmt.Errorf("the seed name must be set to collect orphaned metal resources")
+	}
+
+	logr.InfoLogger.Info(logger, "Adding orphan collector")
+	collector := newCollector(logger, mgr.GetClient(), mgr.GetEventRecorderFor(ControllerName), *opts.OrphanCollection)
+	if opts.MetalClientFactory != nil {
+		if _, err := metalclient.ClientFactoryInto(opts.MetalClientFactory, collector); err != nil {
+			return err
+		}
+	}
+	return mgr.Add(collector)
+}
+
+// AddToManager adds the orphan collector with the default Options.
+func AddToManager(mgr manager.Manager) error {
+	return AddToManagerWithOptions(mgr, DefaultAddOptions)
+}
@@ -0,0 +1,384 @@
+package orphan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// defaultInterval is the interval in which orphaned resources are looked up if none is configured.
+	defaultInterval = 10 * time.Minute
+	// defaultGracePeriod is the duration a resource has to be orphaned before it is released if none is configured.
+	defaultGracePeriod = 24 * time.Hour
+
+	// stateConfigMapName is the name of the config map in the namespace of the credentials secret which contains the
+	// time every orphaned resource was detected, such that the grace period survives restarts of the collector.
+	stateConfigMapName = "metal-orphan-collector-state"
+	// stateDataKey is the key of the detection times in the state config map.
+	stateDataKey = "orphanedSince"
+)
+
+const (
+	kindFirewall = "firewall"
+	kindIP       = "ip"
+	kindNetwork  = "network"
+)
+
+const (
+	eventReasonOrphanDetected      = "OrphanedResourceDetected"
+	eventReasonOrphanReleased      = "OrphanedResourceReleased"
+	eventReasonOrphanReleaseFailed = "OrphanedResourceReleaseFailed"
+)
+
+// kinds is the order in which orphaned resources are released, networks can only be released once there are no
+// machines and ips left in them.
+var kinds = []string{kindFirewall, kindIP, kindNetwork}
+
+// orphan is a metal resource which carries the id of a cluster that is not present on the seed.
+type orphan struct {
+	kind      string
+	id        string
+	clusterID string
+}
+
+func (o orphan) key() string {
+	return o.kind + "/" + o.id
+}
+
+type collector struct {
+	logger             logr.Logger
+	client             client.Client
+	recorder           record.EventRecorder
+	metalClientFactory metalclient.ClientFactory
+
+	seedName    string
+	secretRef   corev1.SecretReference
+	interval    time.Duration
+	release     bool
+	gracePeriod time.Duration
+
+	// orphanedSince contains the time an orphaned resource was detected for the first time. It is persisted in the
+	// state config map and loaded on the first collection.
+	orphanedSince map[string]time.Time
+	now           func() time.Time
+}
+
+func newCollector(logger logr.Logger, c client.Client, recorder record.EventRecorder, cfg config.OrphanCollection) *collector {
+	col := &collector{
+		logger:             logger,
+		client:             c,
+		recorder:           recorder,
+		seedName:           cfg.SeedName,
+		secretRef:          cfg.SecretRef,
+		interval:           defaultInterval,
+		release:            cfg.Release,
+		gracePeriod:        defaultGracePeriod,
+		metalClientFactory: metalclient.DefaultClientFactory,
+		now:                time.Now,
+	}
+	if cfg.Interval != nil {
+		col.interval = cfg.Interval.Duration
+	}
+	if cfg.GracePeriod != nil {
+		col.gracePeriod = cfg.GracePeriod.Duration
+	}
+	return col
+}
+
+func (c *collector) InjectMetalClientFactory(factory metalclient.ClientFactory) error {
+	c.metalClientFactory = factory
+	return nil
+}
+
+// Start implements manager.Runnable and looks up orphaned resources periodically until the stop channel is closed.
+func (c *collector) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	wait.Until(func() {
+		if err := c.collect(ctx); err != nil {
+			c.logger.Error(err, "error collecting orphaned metal resources")
+		}
+	}, c.interval, stop)
+	return nil
+}
+
+// collect looks up the orphaned resources, reports them and releases those that exceeded the grace period if
+// releasing is enabled.
+func (c *collector) collect(ctx context.Context) error {
+	clusterIDs, err := c.clusterIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret, err := extensionscontroller.GetSecretByReference(ctx, c.client, &c.secretRef)
+	if err != nil {
+		return err
+	}
+	credentials, err := metal.ReadCredentialsSecret(secret)
+	if err != nil {
+		return err
+	}
+	mclient, err := c.metalClientFactory.NewClientFromCredentials(credentials)
+	if err != nil {
+		return err
+	}
+
+	orphans, err := findOrphans(mclient, c.seedName, clusterIDs)
+	if err != nil {
+		return err
+	}
+	orphans, err = c.skipMigrated(ctx, orphans, clusterIDs)
+	if err != nil {
+		return err
+	}
+
+	if c.orphanedSince == nil {
+		orphanedSince, err := c.loadOrphanedSince(ctx)
+		if err != nil {
+			return err
+		}
+		c.orphanedSince = orphanedSince
+	}
+
+	c.report(secret, orphans)
+
+	var errs []error
+	if c.release {
+		if err := c.releaseOrphans(mclient, secret, orphans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := c.saveOrphanedSince(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("could not persist the detection times of orphaned resources: %+v", err))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// loadOrphanedSince returns the detection times of orphaned resources persisted in the state config map.
+func (c *collector) loadOrphanedSince(ctx context.Context) (map[string]time.Time, error) {
+	orphanedSince := map[string]time.Time{}
+
+	cm := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.secretRef.Namespace, Name: stateConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return orphanedSince, nil
+		}
+		return nil, err
+	}
+
+	if data, ok := cm.Data[stateDataKey]; ok {
+		if err := json.Unmarshal([]byte(data), &orphanedSince); err != nil {
+			return nil, fmt.Errorf("could not decode state config map %s/%s: %+v", cm.Namespace, cm.Name, err)
+		}
+	}
+	return orphanedSince, nil
+}
+
+// saveOrphanedSince persists the detection times of orphaned resources in the state config map.
+func (c *collector) saveOrphanedSince(ctx context.Context) error {
+	data, err := json.Marshal(c.orphanedSince)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      stateConfigMapName,
+			Namespace: c.secretRef.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, c.client, cm, func() error {
+		cm.Data = map[string]string{stateDataKey: string(data)}
+		return nil
+	})
+	return err
+}
+
+// clusterIDs returns the ids of all clusters present on the seed.
+func (c *collector) clusterIDs(ctx context.Context) (sets.String, error) {
+	clusters := &extensionsv1alpha1.ClusterList{}
+	if err := c.client.List(ctx, clusters); err != nil {
+		return nil, err
+	}
+
+	decoder, err := extensionscontroller.NewGardenDecoder()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := sets.NewString()
+	for i := range clusters.Items {
+		shoot, err := extensionscontroller.ShootFromCluster(decoder, &clusters.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not decode shoot of cluster %s: %+v", clusters.Items[i].Name, err)
+		}
+		if shoot == nil {
+			return nil, fmt.Errorf("cluster %s does not contain a shoot", clusters.Items[i].Name)
+		}
+		ids.Insert(string(shoot.UID))
+	}
+	return ids, nil
+}
+
+// report updates the metrics of orphaned resources and emits an event for every newly detected orphan.
+func (c *collector) report(secret *corev1.Secret, orphans []orphan) {
+	now := c.now()
+	current := map[string]time.Time{}
+	counts := map[string]int{}
+	for _, o := range orphans {
+		counts[o.kind]++
+
+		since, ok := c.orphanedSince[o.key()]
+		if !ok {
+			since = now
+			c.logger.Info("detected orphaned metal resource", "kind", o.kind, "id", o.id, "clusterID", o.clusterID)
+			c.recorder.Eventf(secret, corev1.EventTypeWarning, eventReasonOrphanDetected, "Detected orphaned %s %s of cluster %s", o.kind, o.id, o.clusterID)
+		}
+		current[o.key()] = since
+	}
+	c.orphanedSince = current
+
+	for _, kind := range kinds {
+		orphanedResources.WithLabelValues(kind).Set(float64(counts[kind]))
+	}
+}
+
+// releaseOrphans releases all orphans that exceeded the grace period.
+func (c *collector) releaseOrphans(mclient metalclient.Client, secret *corev1.Secret, orphans []orphan) error {
+	now := c.now()
+	var errs []error
+	for _, kind := range kinds {
+		for _, o := range orphans {
+			if o.kind != kind || now.Sub(c.orphanedSince[o.key()]) < c.gracePeriod {
+				continue
+			}
+
+			if err := releaseOrphan(mclient, o); err != nil {
+				orphanReleaseErrors.WithLabelValues(o.kind).Inc()
+				c.recorder.Eventf(secret, corev1.EventTypeWarning, eventReasonOrphanReleaseFailed, "Failed to release orphaned %s %s of cluster %s: %v", o.kind, o.id, o.clusterID, err)
+				errs = append(errs, fmt.Errorf("could not release orphaned %s %s: %+v", o.kind, o.id, err))
+				continue
+			}
+
+			releasedOrphanedResources.WithLabelValues(o.kind).Inc()
+			delete(c.orphanedSince, o.key())
+			c.logger.Info("released orphaned metal resource", "kind", o.kind, "id", o.id, "clusterID", o.clusterID)
+			c.recorder.Eventf(secret, corev1.EventTypeNormal, eventReasonOrphanReleased, "Released orphaned %s %s of cluster %s", o.kind, o.id, o.clusterID)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func releaseOrphan(mclient metalclient.Client, o orphan) error {
+	var err error
+	switch o.kind {
+	case kindFirewall:
+		_, err = mclient.MachineDelete(o.id)
+	case kindIP:
+		_, err = mclient.IPFree(o.id)
+	case kindNetwork:
+		_, err = mclient.NetworkFree(o.id)
+	default:
+		return fmt.Errorf("unknown kind %q", o.kind)
+	}
+	// the resource is gone already if it was released in the meantime
+	return metalclient.IgnoreNotFound(err)
+}
+
+// findOrphans returns all firewalls, ephemeral ips and networks managed by the given seed that carry the id of a
+// cluster which is not contained in the given cluster ids. Ips which are still used by another cluster are not
+// considered orphaned. Networks to which firewalls of another seed are attached belong to a cluster which was migrated
+// to that seed.
+func findOrphans(mclient metalclient.Client, seedName string, clusterIDs sets.String) ([]orphan, error) {
+	var orphans []orphan
+
+	firewalls, err := mclient.FirewallList()
+	if err != nil {
+		return nil, err
+	}
+	otherSeedNetworks := sets.NewString()
+	for _, fw := range firewalls.Firewalls {
+		if fw.ID == nil {
+			continue
+		}
+		if !metaltags.IsManagedBySeed(fw.Tags, seedName) {
+			if _, ok := metaltags.Value(fw.Tags, metaltags.Seed); ok && fw.Allocation != nil {
+				for _, nw := range fw.Allocation.Networks {
+					if nw != nil && nw.Networkid != nil {
+						otherSeedNetworks.Insert(*nw.Networkid)
+					}
+				}
+			}
+			continue
+		}
+		ids := metaltags.ClusterIDs(fw.Tags)
+		if ids.Len() == 0 || ids.HasAny(clusterIDs.UnsortedList()...) {
+			continue
+		}
+		orphans = append(orphans, orphan{kind: kindFirewall, id: *fw.ID, clusterID: strings.Join(ids.List(), ",")})
+	}
+
+	ephemeral := metalgo.IPTypeEphemeral
+	ips, err := mclient.IPFind(&metalgo.IPFindRequest{Type: &ephemeral})
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips.IPs {
+		if ip.Ipaddress == nil || !metaltags.IsManagedBySeed(ip.Tags, seedName) {
+			continue
+		}
+		ids := metaltags.ClusterIDs(ip.Tags)
+		if ids.Len() == 0 || ids.HasAny(clusterIDs.UnsortedList()...) {
+			continue
+		}
+		orphans = append(orphans, orphan{kind: kindIP, id: *ip.Ipaddress, clusterID: strings.Join(ids.List(), ",")})
+	}
+
+	networks, err := mclient.NetworkList()
+	if err != nil {
+		return nil, err
+	}
+	for _, nw := range networks.Networks {
+		if nw.ID == nil || nw.Labels[metaltags.Seed] != seedName || otherSeedNetworks.Has(*nw.ID) {
+			continue
+		}
+		id, ok := nw.Labels[tag.ClusterID]
+		if !ok || id == "" || clusterIDs.Has(id) {
+			continue
+		}
+		orphans = append(orphans, orphan{kind: kindNetwork, id: *nw.ID, clusterID: id})
+	}
+
+	return orphans, nil
+}
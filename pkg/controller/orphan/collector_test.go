@@ -0,0 +1,236 @@
+package orphan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// fakeMetalAPI serves the metal resources the orphan collector looks up and records all release calls.
+type fakeMetalAPI struct {
+	lock sync.Mutex
+
+	firewalls []*models.V1FirewallResponse
+	ips       []*models.V1IPResponse
+	networks  []*models.V1NetworkResponse
+
+	releases []string
+}
+
+func (f *fakeMetalAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var payload interface{}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/firewall":
+		payload = f.firewalls
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/ip/find":
+		payload = f.ips
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/network":
+		payload = f.networks
+	default:
+		f.releases = append(f.releases, r.Method+" "+r.URL.Path)
+		payload = map[string]interface{}{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	Expect(json.NewEncoder(w).Encode(payload)).To(Succeed())
+}
+
+func (f *fakeMetalAPI) Releases() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.releases
+}
+
+var _ = Describe("Orphan collector", func() {
+	const (
+		liveClusterID   = "live-cluster"
+		deadClusterID   = "dead-cluster"
+		secretName      = "metal-orphan-collector"
+		secretNamespace = "garden"
+		seedName        = "seed-a"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		metalAPI *fakeMetalAPI
+		server   *httptest.Server
+		recorder *record.FakeRecorder
+		now      time.Time
+	)
+
+	strPtr := func(s string) *string { return &s }
+	seedTag := metaltags.SeedName(seedName)
+	seedLabels := func(clusterID string) map[string]string {
+		return map[string]string{tag.ClusterID: clusterID, metaltags.Seed: seedName}
+	}
+
+	BeforeEach(func() {
+		metalAPI = &fakeMetalAPI{
+			firewalls: []*models.V1FirewallResponse{
+				{ID: strPtr("live-firewall"), Tags: []string{tag.ClusterID + "=" + liveClusterID, seedTag}},
+				{ID: strPtr("dead-firewall"), Tags: []string{tag.ClusterID + "=" + deadClusterID, seedTag}},
+				{ID: strPtr("other-firewall")},
+				{ID: strPtr("unmarked-firewall"), Tags: []string{tag.ClusterID + "=" + deadClusterID}},
+				{ID: strPtr("other-seed-firewall"), Tags: []string{tag.ClusterID + "=" + "migrated-cluster", metaltags.SeedName("seed-b")},
+					Allocation: &models.V1MachineAllocation{Networks: []*models.V1MachineNetwork{{Networkid: strPtr("migrated-network")}}}},
+			},
+			ips: []*models.V1IPResponse{
+				{Ipaddress: strPtr("1.1.1.1"), Tags: []string{tag.ClusterServiceFQN + "=" + deadClusterID + "/default/svc", seedTag}},
+				{Ipaddress: strPtr("2.2.2.2"), Tags: []string{
+					tag.ClusterServiceFQN + "=" + deadClusterID + "/default/svc",
+					tag.ClusterServiceFQN + "=" + liveClusterID + "/default/svc",
+					seedTag,
+				}},
+				{Ipaddress: strPtr("3.3.3.3")},
+				{Ipaddress: strPtr("4.4.4.4"), Tags: []string{tag.ClusterServiceFQN + "=" + deadClusterID + "/default/svc", metaltags.SeedName("seed-b")}},
+			},
+			networks: []*models.V1NetworkResponse{
+				{ID: strPtr("live-network"), Labels: seedLabels(liveClusterID)},
+				{ID: strPtr("dead-network"), Labels: seedLabels(deadClusterID)},
+				{ID: strPtr("internet")},
+				{ID: strPtr("unmarked-network"), Labels: map[string]string{tag.ClusterID: deadClusterID}},
+				{ID: strPtr("migrated-network"), Labels: seedLabels("migrated-cluster")},
+			},
+		}
+		server = httptest.NewServer(metalAPI)
+		recorder = record.NewFakeRecorder(100)
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	var c client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewFakeClientWithScheme(scheme,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: secretNamespace},
+				Data: map[string][]byte{
+					metal.APIURL:  []byte(server.URL),
+					metal.APIHMac: []byte("hmac"),
+				},
+			},
+			&extensionsv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"},
+				Spec: extensionsv1alpha1.ClusterSpec{
+					CloudProfile: runtime.RawExtension{},
+					Seed:         runtime.RawExtension{},
+					Shoot: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"core.gardener.cloud/v1beta1","kind":"Shoot","metadata":{"name":"bar","uid":"` + liveClusterID + `"}}`),
+					},
+				},
+			},
+		)
+	})
+
+	newCollectorWithRelease := func(release bool) *collector {
+		col := newCollector(log.Log, c, recorder, config.OrphanCollection{
+			SeedName:    seedName,
+			SecretRef:   corev1.SecretReference{Name: secretName, Namespace: secretNamespace},
+			Release:     release,
+			GracePeriod: &metav1.Duration{Duration: time.Hour},
+		})
+		col.now = func() time.Time { return now }
+		return col
+	}
+
+	It("should only report orphaned resources if releasing is disabled", func() {
+		col := newCollectorWithRelease(false)
+
+		Expect(col.collect(ctx)).To(Succeed())
+		now = now.Add(2 * time.Hour)
+		Expect(col.collect(ctx)).To(Succeed())
+
+		Expect(col.orphanedSince).To(HaveLen(3))
+		Expect(col.orphanedSince).To(HaveKey("firewall/dead-firewall"))
+		Expect(col.orphanedSince).To(HaveKey("ip/1.1.1.1"))
+		Expect(col.orphanedSince).To(HaveKey("network/dead-network"))
+		Expect(recorder.Events).To(HaveLen(3))
+		Expect(metalAPI.Releases()).To(BeEmpty())
+	})
+
+	It("should release orphaned resources after the grace period", func() {
+		col := newCollectorWithRelease(true)
+
+		Expect(col.collect(ctx)).To(Succeed())
+		Expect(metalAPI.Releases()).To(BeEmpty())
+
+		now = now.Add(2 * time.Hour)
+		Expect(col.collect(ctx)).To(Succeed())
+
+		Expect(metalAPI.Releases()).To(Equal([]string{
+			"DELETE /v1/machine/dead-firewall/free",
+			"POST /v1/ip/free/1.1.1.1",
+			"POST /v1/network/free/dead-network",
+		}))
+		Expect(col.orphanedSince).To(BeEmpty())
+		Expect(recorder.Events).To(HaveLen(6))
+	})
+
+	It("should keep the detection times when the collector is restarted", func() {
+		Expect(newCollectorWithRelease(true).collect(ctx)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: stateConfigMapName}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKey(stateDataKey))
+
+		now = now.Add(2 * time.Hour)
+		restarted := newCollectorWithRelease(true)
+		Expect(restarted.collect(ctx)).To(Succeed())
+
+		Expect(metalAPI.Releases()).To(HaveLen(3))
+		Expect(restarted.orphanedSince).To(BeEmpty())
+	})
+
+	It("should not consider the resources of clusters which were migrated to another seed", func() {
+		Expect(RecordMigratedCluster(ctx, c, deadClusterID)).To(Succeed())
+		Expect(RecordMigratedCluster(ctx, c, "gone-cluster")).To(Succeed())
+		Expect(RecordMigratedCluster(ctx, c, liveClusterID)).To(Succeed())
+
+		col := newCollectorWithRelease(true)
+		Expect(col.collect(ctx)).To(Succeed())
+		now = now.Add(2 * time.Hour)
+		Expect(col.collect(ctx)).To(Succeed())
+
+		Expect(col.orphanedSince).To(BeEmpty())
+		Expect(metalAPI.Releases()).To(BeEmpty())
+
+		By("forgetting the clusters which are present again or have no resources marked for this seed")
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "garden", Name: migratedClustersConfigMapName}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveLen(1))
+		Expect(cm.Data).To(HaveKey(deadClusterID))
+	})
+})
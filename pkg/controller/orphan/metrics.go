@@ -0,0 +1,37 @@
+package orphan
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	orphanedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "metal_orphaned_resources",
+			Help: "Number of metal resources that carry the id of a cluster which is not present on the seed.",
+		},
+		[]string{"kind"},
+	)
+
+	releasedOrphanedResources = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_orphaned_resources_released_total",
+			Help: "Total number of orphaned metal resources released by the orphan collector.",
+		},
+		[]string{"kind"},
+	)
+
+	orphanReleaseErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_orphaned_resources_release_errors_total",
+			Help: "Total number of errors while releasing orphaned metal resources.",
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedResources, releasedOrphanedResources, orphanReleaseErrors)
+}
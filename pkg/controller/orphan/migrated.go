@@ -0,0 +1,90 @@
+package orphan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// migratedClustersConfigMapName is the name of the config map in the garden namespace of the seed which contains the
+// ids of the clusters whose control plane was migrated away from this seed together with the time of the migration.
+// The firewalls and networks of these clusters keep the mark of this seed as their tags and labels can not be updated,
+// hence the orphan collector of this seed must not consider them.
+const migratedClustersConfigMapName = "metal-migrated-clusters"
+
+// RecordMigratedCluster records that the control plane of the cluster with the given id was migrated away from this
+// seed, such that its metal resources are not collected as orphans.
+func RecordMigratedCluster(ctx context.Context, c client.Client, clusterID string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      migratedClustersConfigMapName,
+			Namespace: v1beta1constants.GardenNamespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		if _, ok := cm.Data[clusterID]; !ok {
+			cm.Data[clusterID] = time.Now().UTC().Format(time.RFC3339)
+		}
+		return nil
+	})
+	return err
+}
+
+// skipMigrated removes the orphans of migrated clusters from the given orphans. Clusters which are present on the seed
+// again or have no resources marked for this seed anymore are removed from the migrated clusters.
+func (c *collector) skipMigrated(ctx context.Context, orphans []orphan, clusterIDs sets.String) ([]orphan, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: v1beta1constants.GardenNamespace, Name: migratedClustersConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return orphans, nil
+		}
+		return nil, err
+	}
+
+	var (
+		result []orphan
+		inUse  = sets.NewString()
+	)
+	for _, o := range orphans {
+		migrated := false
+		for _, id := range strings.Split(o.clusterID, ",") {
+			if _, ok := cm.Data[id]; ok {
+				migrated = true
+				inUse.Insert(id)
+			}
+		}
+		if migrated {
+			continue
+		}
+		result = append(result, o)
+	}
+
+	var prune []string
+	for id := range cm.Data {
+		if clusterIDs.Has(id) || !inUse.Has(id) {
+			prune = append(prune, id)
+		}
+	}
+	if len(prune) > 0 {
+		for _, id := range prune {
+			delete(cm.Data, id)
+		}
+		if err := c.client.Update(ctx, cm); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
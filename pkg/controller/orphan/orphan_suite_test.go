@@ -0,0 +1,13 @@
+package orphan
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOrphan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Orphan Collector Suite")
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/gardener/gardener-extensions/pkg/util"
 
@@ -12,23 +13,40 @@ import (
 	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/imagevector"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	gardener "github.com/gardener/gardener/pkg/client/kubernetes"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
 
 	"github.com/go-logr/logr"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
+const (
+	eventReasonNodeNetworkNotFound                = "NodeNetworkNotFound"
+	eventReasonMachinesKept                       = "MachinesKept"
+	eventReasonMachinesRestored                   = "MachinesRestored"
+	eventReasonMachineControllerManagerScaledDown = "MachineControllerManagerScaledDown"
+)
+
 type delegateFactory struct {
-	logger logr.Logger
+	logger   logr.Logger
+	recorder record.EventRecorder
 
 	restConfig *rest.Config
 
@@ -37,23 +55,127 @@ type delegateFactory struct {
 	decoder runtime.Decoder
 
 	machineImageMapping []config.MachineImage
+	metalClientFactory  metalclient.ClientFactory
+}
+
+// actuator wraps the generic worker actuator and adds the handling of control plane migrations.
+type actuator struct {
+	worker.Actuator
+
+	logger          logr.Logger
+	recorder        record.EventRecorder
+	client          client.Client
+	delegateFactory *delegateFactory
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled WorkerPoolConfigs.
-func NewActuator(machineImages []config.MachineImage) worker.Actuator {
+func NewActuator(recorder record.EventRecorder, machineImages []config.MachineImage) worker.Actuator {
 	delegateFactory := &delegateFactory{
 		logger:              log.Log.WithName("worker-actuator"),
+		recorder:            recorder,
 		machineImageMapping: machineImages,
+		metalClientFactory:  metalclient.DefaultClientFactory,
+	}
+	return &actuator{
+		Actuator: genericactuator.NewActuator(
+			log.Log.WithName("metal-worker-actuator"),
+			delegateFactory,
+			metal.MachineControllerManagerName,
+			mcmChart,
+			mcmShootChart,
+			imagevector.ImageVector(),
+			extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
+		),
+		logger:          log.Log.WithName("metal-worker-actuator"),
+		recorder:        recorder,
+		delegateFactory: delegateFactory,
+	}
+}
+
+func (a *actuator) InjectFunc(f inject.Func) error {
+	return f(a.Actuator)
+}
+
+func (a *actuator) InjectClient(client client.Client) error {
+	a.client = client
+	return nil
+}
+
+func (a *actuator) InjectMetalClientFactory(factory metalclient.ClientFactory) error {
+	return a.delegateFactory.InjectMetalClientFactory(factory)
+}
+
+func (a *actuator) Reconcile(ctx context.Context, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	if w.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.GardenerOperationMigrate {
+		return a.Migrate(ctx, w)
+	}
+	if err := a.Restore(ctx, w); err != nil {
+		return err
 	}
-	return genericactuator.NewActuator(
-		log.Log.WithName("metal-worker-actuator"),
-		delegateFactory,
-		metal.MachineControllerManagerName,
-		mcmChart,
-		mcmShootChart,
-		imagevector.ImageVector(),
-		extensionscontroller.ChartRendererFactoryFunc(util.NewChartRendererForShoot),
-	)
+	return a.Actuator.Reconcile(ctx, w, cluster)
+}
+
+func (a *actuator) Delete(ctx context.Context, w *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) error {
+	if w.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.GardenerOperationMigrate {
+		// the machine-controller-manager is scaled down and does not remove its finalizers anymore, they would block the
+		// deletion of the namespace
+		if err := removeMachineControllerManagerFinalizers(ctx, a.client, w.Namespace); err != nil {
+			return err
+		}
+		a.logger.Info("worker was migrated to another seed, keeping machines", "worker", w.Name)
+		a.recorder.Event(w, corev1.EventTypeNormal, eventReasonMachinesKept, "Keeping the machines because the worker was migrated to another seed")
+		return nil
+	}
+	return a.Actuator.Delete(ctx, w, cluster)
+}
+
+// Migrate scales down the machine-controller-manager such that the machines are not managed by this seed anymore and
+// persists the state of the worker for the seed the control plane is migrated to.
+func (a *actuator) Migrate(ctx context.Context, w *extensionsv1alpha1.Worker) error {
+	deployment := &appsv1.Deployment{}
+	if err := a.client.Get(ctx, kutil.Key(w.Namespace, metal.MachineControllerManagerName), deployment); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if err := util.ScaleDeployment(ctx, a.client, deployment, 0); err != nil {
+		return err
+	} else {
+		a.recorder.Event(w, corev1.EventTypeNormal, eventReasonMachineControllerManagerScaledDown, "Scaled down the machine-controller-manager, the machines are managed by the seed the control plane is migrated to")
+	}
+
+	state, err := computeWorkerState(ctx, a.client, w)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return extensionscontroller.TryUpdateStatus(ctx, retry.DefaultBackoff, a.client, w, func() error {
+		w.Status.State = &runtime.RawExtension{Raw: raw}
+		return nil
+	})
+}
+
+// Restore creates the machine sets and machines of a worker which was restored after a control plane migration before
+// the machine-controller-manager is deployed, such that the existing machines are adopted instead of created again.
+func (a *actuator) Restore(ctx context.Context, w *extensionsv1alpha1.Worker) error {
+	if w.Status.ProviderStatus != nil {
+		// the worker was already reconciled on this seed
+		return nil
+	}
+
+	state, err := decodeWorkerState(w)
+	if err != nil || state == nil {
+		return err
+	}
+
+	if err := restoreMachines(ctx, a.client, state); err != nil {
+		return err
+	}
+	a.recorder.Eventf(w, corev1.EventTypeNormal, eventReasonMachinesRestored, "Restored %d machine sets and %d machines of the migrated worker", len(state.MachineSets), len(state.Machines))
+	return nil
 }
 
 func (d *delegateFactory) InjectScheme(scheme *runtime.Scheme) error {
@@ -72,6 +194,11 @@ func (d *delegateFactory) InjectClient(client client.Client) error {
 	return nil
 }
 
+func (d *delegateFactory) InjectMetalClientFactory(factory metalclient.ClientFactory) error {
+	d.metalClientFactory = factory
+	return nil
+}
+
 func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensionsv1alpha1.Worker, cluster *extensionscontroller.Cluster) (genericactuator.WorkerDelegate, error) {
 	clientset, err := kubernetes.NewForConfig(d.restConfig)
 	if err != nil {
@@ -90,6 +217,8 @@ func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensions
 
 	return NewWorkerDelegate(
 		d.client,
+		d.recorder,
+		d.metalClientFactory,
 		d.scheme,
 		d.decoder,
 
@@ -103,9 +232,11 @@ func (d *delegateFactory) WorkerDelegate(ctx context.Context, worker *extensions
 }
 
 type workerDelegate struct {
-	client  client.Client
-	scheme  *runtime.Scheme
-	decoder runtime.Decoder
+	client             client.Client
+	recorder           record.EventRecorder
+	metalClientFactory metalclient.ClientFactory
+	scheme             *runtime.Scheme
+	decoder            runtime.Decoder
 
 	machineImageMapping []config.MachineImage
 	seedChartApplier    gardener.ChartApplier
@@ -122,6 +253,8 @@ type workerDelegate struct {
 // NewWorkerDelegate creates a new context for a worker reconciliation.
 func NewWorkerDelegate(
 	client client.Client,
+	recorder record.EventRecorder,
+	metalClientFactory metalclient.ClientFactory,
 	scheme *runtime.Scheme,
 	decoder runtime.Decoder,
 
@@ -133,9 +266,11 @@ func NewWorkerDelegate(
 	cluster *extensionscontroller.Cluster,
 ) genericactuator.WorkerDelegate {
 	return &workerDelegate{
-		client:  client,
-		scheme:  scheme,
-		decoder: decoder,
+		client:             client,
+		recorder:           recorder,
+		metalClientFactory: metalClientFactory,
+		scheme:             scheme,
+		decoder:            decoder,
 
 		machineImageMapping: machineImageMapping,
 		seedChartApplier:    seedChartApplier,
@@ -4,6 +4,7 @@ import (
 	"github.com/gardener/gardener-extensions/pkg/controller/worker"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/config"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
 
 	machinescheme "github.com/gardener/machine-controller-manager/pkg/client/clientset/versioned/scheme"
 	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
@@ -24,6 +25,9 @@ type AddOptions struct {
 	MachineImages []config.MachineImage
 	// IgnoreOperationAnnotation specifies whether to ignore the operation annotation or not.
 	IgnoreOperationAnnotation bool
+	// MetalClientFactory creates the metal clients of the controller, metalclient.DefaultClientFactory is used if it
+	// is nil.
+	MetalClientFactory metalclient.ClientFactory
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
@@ -37,8 +41,15 @@ func AddToManagerWithOptions(mgr manager.Manager, opts AddOptions) error {
 		return err
 	}
 
+	actuator := NewActuator(mgr.GetEventRecorderFor(worker.ControllerName), opts.MachineImages)
+	if opts.MetalClientFactory != nil {
+		if _, err := metalclient.ClientFactoryInto(opts.MetalClientFactory, actuator); err != nil {
+			return err
+		}
+	}
+
 	return worker.Add(mgr, worker.AddArgs{
-		Actuator:          NewActuator(opts.MachineImages),
+		Actuator:          actuator,
 		ControllerOptions: opts.Controller,
 		Predicates:        worker.DefaultPredicates(opts.IgnoreOperationAnnotation),
 		Type:              metal.Type,
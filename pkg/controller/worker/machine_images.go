@@ -58,8 +58,19 @@ func (w *workerDelegate) findMachineImage(name, version string) (string, error)
 		return machineImage, nil
 	}
 
-	// Try to look up machine image in worker provider status as it was not found in componentconfig.
-	if providerStatus := w.worker.Status.ProviderStatus; providerStatus != nil {
+	// Try to look up machine image in worker provider status as it was not found in componentconfig. The state is used
+	// if the worker was restored after a control plane migration.
+	providerStatus := w.worker.Status.ProviderStatus
+	if providerStatus == nil {
+		state, err := decodeWorkerState(w.worker)
+		if err != nil {
+			return "", err
+		}
+		if state != nil {
+			providerStatus = state.ProviderStatus
+		}
+	}
+	if providerStatus != nil {
 		workerStatus := &apismetal.WorkerStatus{}
 		if _, _, err := w.decoder.Decode(providerStatus.Raw, nil, workerStatus); err != nil {
 			return "", errors.Wrapf(err, "could not decode worker status of worker '%s'", util.ObjectName(w.worker))
@@ -4,19 +4,21 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-
-	metaltag "github.com/metal-stack/metal-lib/pkg/tag"
+	"strings"
 
 	"github.com/gardener/gardener-extensions/pkg/controller/worker"
 	apismetal "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal"
+	apismetalhelper "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/helper"
 
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
 	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
 
 	genericworkeractuator "github.com/gardener/gardener-extensions/pkg/controller/worker/genericactuator"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -67,7 +69,7 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 		return err
 	}
 
-	mclient, err := metalclient.NewClientFromCredentials(credentials)
+	mclient, err := w.metalClientFactory.NewClientFromCredentials(credentials)
 	if err != nil {
 		return err
 	}
@@ -80,6 +82,12 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 	}
 
 	privateNetwork, err := metalclient.GetPrivateNetworkFromNodeNetwork(mclient, projectID, *nodeCIDR)
+	if err != nil {
+		w.recorder.Eventf(w.worker, corev1.EventTypeWarning, eventReasonNodeNetworkNotFound, "Could not find the node network %s in project %q: %v", *nodeCIDR, projectID, err)
+		return metalclient.ReconcileError(err)
+	}
+
+	nodeNetworks, err := w.nodeNetworksByPartition(infrastructureConfig, *privateNetwork.ID)
 	if err != nil {
 		return err
 	}
@@ -90,79 +98,105 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 			return err
 		}
 
-		machineImage, err := w.findMachineImage(pool.MachineImage.Name, pool.MachineImage.Version)
-		if err != nil {
-			return err
+		workerConfig := &apismetal.WorkerConfig{}
+		if pool.ProviderConfig != nil && pool.ProviderConfig.Raw != nil {
+			if _, _, err := w.decoder.Decode(pool.ProviderConfig.Raw, nil, workerConfig); err != nil {
+				return fmt.Errorf("could not decode provider config of worker pool %q: %w", pool.Name, err)
+			}
 		}
-		machineImages = appendMachineImage(machineImages, apismetal.MachineImage{
-			Name:    pool.MachineImage.Name,
-			Version: pool.MachineImage.Version,
-			Image:   machineImage,
-		})
-
-		var (
-			metalClusterIDTag      = fmt.Sprintf("%s=%s", metaltag.ClusterID, w.cluster.Shoot.GetUID())
-			metalClusterNameTag    = fmt.Sprintf("%s=%s", metaltag.ClusterName, w.worker.Namespace)
-			metalClusterProjectTag = fmt.Sprintf("%s=%s", metaltag.ClusterProject, infrastructureConfig.ProjectID)
-
-			kubernetesClusterTag        = fmt.Sprintf("kubernetes.io/cluster=%s", w.worker.Namespace)
-			kubernetesRoleTag           = fmt.Sprintf("kubernetes.io/role=node")
-			kubernetesInstanceTypeTag   = fmt.Sprintf("node.kubernetes.io/instance-type=%s", pool.MachineType)
-			kubernetesTopologyRegionTag = fmt.Sprintf("topology.kubernetes.io/region=%s", w.worker.Spec.Region)
-			kubernetesTopologyZoneTag   = fmt.Sprintf("topology.kubernetes.io/zone=%s", infrastructureConfig.PartitionID)
-		)
-
-		machineClassSpec := map[string]interface{}{
-			"partition": infrastructureConfig.PartitionID,
-			"size":      pool.MachineType,
-			"project":   projectID,
-			"network":   privateNetwork.ID,
-			"image":     machineImage,
-			"tags": []string{
-				kubernetesClusterTag,
-				kubernetesRoleTag,
-				kubernetesInstanceTypeTag,
-				kubernetesTopologyRegionTag,
-				kubernetesTopologyZoneTag,
-
-				metalClusterIDTag,
-				metalClusterNameTag,
-				metalClusterProjectTag,
-			},
-			"sshkeys": []string{string(w.worker.Spec.SSHPublicKey)},
-			"secret": map[string]interface{}{
-				"cloudConfig": string(pool.UserData),
-			},
+
+		// overridden images are not recorded in the worker status as they are always available from the pool config
+		machineImage, ok := apismetalhelper.FindImageOverride(workerConfig.ImageOverrides, pool.MachineImage.Name, pool.MachineImage.Version)
+		if !ok {
+			machineImage, err = w.findMachineImage(pool.MachineImage.Name, pool.MachineImage.Version)
+			if err != nil {
+				return err
+			}
+			machineImages = appendMachineImage(machineImages, apismetal.MachineImage{
+				Name:    pool.MachineImage.Name,
+				Version: pool.MachineImage.Version,
+				Image:   machineImage,
+			})
 		}
 
-		var (
-			deploymentName = fmt.Sprintf("%s-%s", w.worker.Namespace, pool.Name)
-			className      = fmt.Sprintf("%s-%s", deploymentName, workerPoolHash)
-		)
-
-		machineDeployments = append(machineDeployments, worker.MachineDeployment{
-			Name:           deploymentName,
-			ClassName:      className,
-			SecretName:     className,
-			Minimum:        pool.Minimum,
-			Maximum:        pool.Maximum,
-			MaxSurge:       pool.MaxSurge,
-			MaxUnavailable: pool.MaxUnavailable,
-			Labels:         pool.Labels,
-			Annotations:    pool.Annotations,
-			Taints:         pool.Taints,
-		})
-
-		machineClassSpec["name"] = className
-		machineClassSpec["labels"] = map[string]string{
-			v1beta1constants.GardenPurpose: genericworkeractuator.GardenPurposeMachineClass,
+		// pools without zones are placed into the partition of the infrastructure config or the one of the worker config,
+		// the machine deployment keeps its name without zone suffix in this case
+		zones := pool.Zones
+		if len(zones) == 0 {
+			partitionID := infrastructureConfig.PartitionID
+			if workerConfig.PartitionID != nil {
+				partitionID = *workerConfig.PartitionID
+			}
+			zones = []string{partitionID}
 		}
 
-		machineClassSpec["secret"].(map[string]interface{})[metal.APIURL] = credentials.MetalAPIURL
-		machineClassSpec["secret"].(map[string]interface{})[metal.APIKey] = credentials.MetalAPIKey
-		machineClassSpec["secret"].(map[string]interface{})[metal.APIHMac] = credentials.MetalAPIHMac
+		userData := string(pool.UserData)
+		if len(workerConfig.UserDataSnippets) > 0 {
+			userData = strings.Join(append([]string{strings.TrimSuffix(userData, "\n")}, workerConfig.UserDataSnippets...), "\n")
+		}
 
-		machineClasses = append(machineClasses, machineClassSpec)
+		for zoneIndex, partitionID := range zones {
+			networkID, ok := nodeNetworks[partitionID]
+			if !ok {
+				return fmt.Errorf("no node network found for zone %q of worker pool %q", partitionID, pool.Name)
+			}
+
+			machineTags := metaltags.KubernetesTopology{
+				Cluster:      w.worker.Namespace,
+				InstanceType: pool.MachineType,
+				Region:       w.worker.Spec.Region,
+				Zone:         partitionID,
+			}.Tags()
+			machineTags = metaltags.AddClusterMembership(machineTags, string(w.cluster.Shoot.GetUID()))
+			machineTags = append(machineTags,
+				metaltags.ClusterName(w.worker.Namespace),
+				metaltags.ClusterProject(infrastructureConfig.ProjectID),
+			)
+			machineTags = append(machineTags, workerConfig.ExtraTags...)
+
+			machineClassSpec := map[string]interface{}{
+				"partition": partitionID,
+				"size":      pool.MachineType,
+				"project":   projectID,
+				"network":   networkID,
+				"image":     machineImage,
+				"tags":      machineTags,
+				"sshkeys":   []string{string(w.worker.Spec.SSHPublicKey)},
+				"secret": map[string]interface{}{
+					"cloudConfig": userData,
+				},
+			}
+
+			deploymentName := fmt.Sprintf("%s-%s", w.worker.Namespace, pool.Name)
+			if len(pool.Zones) > 0 {
+				deploymentName = fmt.Sprintf("%s-z%d", deploymentName, zoneIndex+1)
+			}
+			className := fmt.Sprintf("%s-%s", deploymentName, workerPoolHash)
+
+			machineDeployments = append(machineDeployments, worker.MachineDeployment{
+				Name:           deploymentName,
+				ClassName:      className,
+				SecretName:     className,
+				Minimum:        worker.DistributeOverZones(zoneIndex, pool.Minimum, len(zones)),
+				Maximum:        worker.DistributeOverZones(zoneIndex, pool.Maximum, len(zones)),
+				MaxSurge:       worker.DistributePositiveIntOrPercent(zoneIndex, pool.MaxSurge, len(zones), pool.Maximum),
+				MaxUnavailable: worker.DistributePositiveIntOrPercent(zoneIndex, pool.MaxUnavailable, len(zones), pool.Minimum),
+				Labels:         pool.Labels,
+				Annotations:    pool.Annotations,
+				Taints:         pool.Taints,
+			})
+
+			machineClassSpec["name"] = className
+			machineClassSpec["labels"] = map[string]string{
+				v1beta1constants.GardenPurpose: genericworkeractuator.GardenPurposeMachineClass,
+			}
+
+			machineClassSpec["secret"].(map[string]interface{})[metal.APIURL] = credentials.MetalAPIURL
+			machineClassSpec["secret"].(map[string]interface{})[metal.APIKey] = credentials.MetalAPIKey
+			machineClassSpec["secret"].(map[string]interface{})[metal.APIHMac] = credentials.MetalAPIHMac
+
+			machineClasses = append(machineClasses, machineClassSpec)
+		}
 	}
 
 	w.machineDeployments = machineDeployments
@@ -170,3 +204,27 @@ func (w *workerDelegate) generateMachineConfig(ctx context.Context) error {
 
 	return nil
 }
+
+// nodeNetworksByPartition returns the ids of the node networks of the cluster by their partition. The node networks of
+// the additional partitions are taken from the infrastructure status.
+func (w *workerDelegate) nodeNetworksByPartition(infrastructureConfig *apismetal.InfrastructureConfig, nodeNetworkID string) (map[string]string, error) {
+	nodeNetworks := map[string]string{
+		infrastructureConfig.PartitionID: nodeNetworkID,
+	}
+	if len(infrastructureConfig.AdditionalPartitionIDs) == 0 {
+		return nodeNetworks, nil
+	}
+
+	if w.worker.Spec.InfrastructureProviderStatus == nil {
+		return nil, fmt.Errorf("infrastructure provider status must be not empty for worker %s/%s", w.worker.Namespace, w.worker.Name)
+	}
+	infrastructureStatus := &apismetal.InfrastructureStatus{}
+	if _, _, err := w.decoder.Decode(w.worker.Spec.InfrastructureProviderStatus.Raw, nil, infrastructureStatus); err != nil {
+		return nil, err
+	}
+
+	for _, nw := range infrastructureStatus.AdditionalNodeNetworks {
+		nodeNetworks[nw.PartitionID] = nw.NetworkID
+	}
+	return nodeNetworks, nil
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// machineControllerManagerFinalizer is the finalizer the machine-controller-manager sets on the machine objects and the
+// secrets of the machine classes.
+const machineControllerManagerFinalizer = "machine.sapcloud.io/machine-controller-manager"
+
+// workerState is the state of a worker which is carried over on control plane migrations. Besides the provider status
+// it contains the machine sets and machines of the machine-controller-manager, they are restored on the seed the
+// control plane is migrated to such that the existing machines are adopted instead of created again.
+type workerState struct {
+	// ProviderStatus is the provider status of the worker.
+	ProviderStatus *runtime.RawExtension `json:"providerStatus,omitempty"`
+	// MachineSets are the machine sets of the worker.
+	MachineSets []machinev1alpha1.MachineSet `json:"machineSets,omitempty"`
+	// Machines are the machines of the worker.
+	Machines []machinev1alpha1.Machine `json:"machines,omitempty"`
+}
+
+// decodeWorkerState decodes the state of a worker, nil is returned if the worker has no state.
+func decodeWorkerState(w *extensionsv1alpha1.Worker) (*workerState, error) {
+	if w.Status.State == nil || len(w.Status.State.Raw) == 0 {
+		return nil, nil
+	}
+
+	state := &workerState{}
+	if err := json.Unmarshal(w.Status.State.Raw, state); err != nil {
+		return nil, errors.Wrapf(err, "could not decode state of worker '%s/%s'", w.Namespace, w.Name)
+	}
+	return state, nil
+}
+
+// computeWorkerState collects the provider status, the machine sets and the machines of the given worker.
+func computeWorkerState(ctx context.Context, c client.Client, w *extensionsv1alpha1.Worker) (*workerState, error) {
+	state := &workerState{}
+
+	if providerStatus := w.Status.ProviderStatus; providerStatus != nil {
+		raw := providerStatus.Raw
+		if raw == nil && providerStatus.Object != nil {
+			var err error
+			raw, err = json.Marshal(providerStatus.Object)
+			if err != nil {
+				return nil, err
+			}
+		}
+		state.ProviderStatus = &runtime.RawExtension{Raw: raw}
+	}
+
+	machineSets := &machinev1alpha1.MachineSetList{}
+	if err := c.List(ctx, machineSets, client.InNamespace(w.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, machineSet := range machineSets.Items {
+		state.MachineSets = append(state.MachineSets, machinev1alpha1.MachineSet{
+			ObjectMeta: shallowObjectMeta(machineSet.ObjectMeta),
+			Spec:       machineSet.Spec,
+		})
+	}
+
+	machines := &machinev1alpha1.MachineList{}
+	if err := c.List(ctx, machines, client.InNamespace(w.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, machine := range machines.Items {
+		state.Machines = append(state.Machines, machinev1alpha1.Machine{
+			ObjectMeta: shallowObjectMeta(machine.ObjectMeta),
+			Spec:       machine.Spec,
+			Status:     machine.Status,
+		})
+	}
+
+	return state, nil
+}
+
+// restoreMachines creates the machine sets and machines of the given state which do not exist yet. They are created
+// without owner references, the machine-controller-manager adopts them by their labels.
+func restoreMachines(ctx context.Context, c client.Client, state *workerState) error {
+	for _, machineSet := range state.MachineSets {
+		machineSet := machineSet.DeepCopy()
+		if err := c.Create(ctx, machineSet); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "could not restore machine set '%s'", machineSet.Name)
+		}
+	}
+
+	for _, machine := range state.Machines {
+		newMachine := machine.DeepCopy()
+		newMachine.Status = machinev1alpha1.MachineStatus{}
+		if err := c.Create(ctx, newMachine); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return errors.Wrapf(err, "could not restore machine '%s'", machine.Name)
+			}
+			// the machine was created by a previous restore whose status update may have failed
+			if err := c.Get(ctx, kutil.Key(machine.Namespace, machine.Name), newMachine); err != nil {
+				return errors.Wrapf(err, "could not get machine '%s'", machine.Name)
+			}
+			if !apiequality.Semantic.DeepEqual(newMachine.Status, machinev1alpha1.MachineStatus{}) {
+				continue
+			}
+		}
+
+		// the status carries the node of the machine, it is required by the machine-controller-manager to not
+		// consider the machine as pending
+		newMachine.Status = machine.Status
+		if err := c.Status().Update(ctx, newMachine); err != nil {
+			return errors.Wrapf(err, "could not restore status of machine '%s'", machine.Name)
+		}
+	}
+
+	return nil
+}
+
+// removeMachineControllerManagerFinalizers removes the finalizer of the machine-controller-manager from the machine
+// objects and the secrets of the machine classes in the given namespace.
+func removeMachineControllerManagerFinalizers(ctx context.Context, c client.Client, namespace string) error {
+	lists := []struct {
+		list runtime.Object
+		opts []client.ListOption
+	}{
+		{list: &machinev1alpha1.MachineDeploymentList{}},
+		{list: &machinev1alpha1.MachineSetList{}},
+		{list: &machinev1alpha1.MachineList{}},
+		{list: &machinev1alpha1.MetalMachineClassList{}},
+		{list: &corev1.SecretList{}, opts: []client.ListOption{client.MatchingLabels{"garden.sapcloud.io/purpose": "machineclass"}}},
+	}
+
+	for _, l := range lists {
+		if err := c.List(ctx, l.list, append(l.opts, client.InNamespace(namespace))...); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(l.list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := extensionscontroller.DeleteFinalizer(ctx, c, machineControllerManagerFinalizer, item); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func shallowObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
@@ -0,0 +1,134 @@
+package ignition
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/container-linux-config-transpiler/config"
+	"github.com/coreos/container-linux-config-transpiler/config/types"
+)
+
+// ParseSnippet parses a container linux config snippet. Snippets may only contain files, directories, links and
+// systemd units, other sections of the config are owned by the generated user data.
+func ParseSnippet(data string) (types.Config, error) {
+	cfg, _, report := config.Parse([]byte(data))
+	if report.IsFatal() {
+		return types.Config{}, fmt.Errorf("could not parse container linux config: %s", report.String())
+	}
+
+	rest := cfg
+	rest.Storage.Files = nil
+	rest.Storage.Directories = nil
+	rest.Storage.Links = nil
+	rest.Systemd.Units = nil
+	if !reflect.DeepEqual(rest, types.Config{}) {
+		return types.Config{}, fmt.Errorf("snippet may only contain storage files, directories, links and systemd units")
+	}
+
+	return cfg, nil
+}
+
+// Merge merges the files, directories, links and units of the given snippets into the base config. Entries of later
+// snippets replace entries of earlier snippets with the same path or unit name. Snippets must not replace entries of
+// the base config.
+func Merge(base types.Config, snippets ...types.Config) (types.Config, error) {
+	merged := base
+	merged.Storage.Files = append([]types.File{}, base.Storage.Files...)
+	merged.Storage.Directories = append([]types.Directory{}, base.Storage.Directories...)
+	merged.Storage.Links = append([]types.Link{}, base.Storage.Links...)
+	merged.Systemd.Units = append([]types.SystemdUnit{}, base.Systemd.Units...)
+
+	owned := map[string]bool{}
+	for _, f := range base.Storage.Files {
+		owned["file "+f.Path] = true
+	}
+	for _, d := range base.Storage.Directories {
+		owned["directory "+d.Path] = true
+	}
+	for _, l := range base.Storage.Links {
+		owned["link "+l.Path] = true
+	}
+	for _, u := range base.Systemd.Units {
+		owned["unit "+u.Name] = true
+	}
+
+	index := map[string]int{}
+	for _, s := range snippets {
+		for _, f := range s.Storage.Files {
+			i, err := position(owned, index, "file "+f.Path, len(merged.Storage.Files))
+			if err != nil {
+				return types.Config{}, err
+			}
+			if i < len(merged.Storage.Files) {
+				merged.Storage.Files[i] = f
+			} else {
+				merged.Storage.Files = append(merged.Storage.Files, f)
+			}
+		}
+		for _, d := range s.Storage.Directories {
+			i, err := position(owned, index, "directory "+d.Path, len(merged.Storage.Directories))
+			if err != nil {
+				return types.Config{}, err
+			}
+			if i < len(merged.Storage.Directories) {
+				merged.Storage.Directories[i] = d
+			} else {
+				merged.Storage.Directories = append(merged.Storage.Directories, d)
+			}
+		}
+		for _, l := range s.Storage.Links {
+			i, err := position(owned, index, "link "+l.Path, len(merged.Storage.Links))
+			if err != nil {
+				return types.Config{}, err
+			}
+			if i < len(merged.Storage.Links) {
+				merged.Storage.Links[i] = l
+			} else {
+				merged.Storage.Links = append(merged.Storage.Links, l)
+			}
+		}
+		for _, u := range s.Systemd.Units {
+			i, err := position(owned, index, "unit "+u.Name, len(merged.Systemd.Units))
+			if err != nil {
+				return types.Config{}, err
+			}
+			if i < len(merged.Systemd.Units) {
+				merged.Systemd.Units[i] = u
+			} else {
+				merged.Systemd.Units = append(merged.Systemd.Units, u)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// position returns the index an entry with the given key is written to. Keys that were not seen before are appended
+// at the given length of their list.
+func position(owned map[string]bool, index map[string]int, key string, length int) (int, error) {
+	if owned[key] {
+		return 0, fmt.Errorf("snippet must not replace generated %s", key)
+	}
+	if i, ok := index[key]; ok {
+		return i, nil
+	}
+	index[key] = length
+	return length, nil
+}
+
+// Transpile validates the config with the container linux config transpiler and returns the resulting ignition
+// config.
+func Transpile(cfg types.Config) (string, error) {
+	out, report := types.Convert(cfg, "", nil)
+	if report.IsFatal() {
+		return "", fmt.Errorf("could not transpile ignition config: %s", report.String())
+	}
+
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,128 @@
+package ignition_test
+
+import (
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/ignition"
+
+	"github.com/coreos/container-linux-config-transpiler/config/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ignition", func() {
+	base := types.Config{
+		Storage: types.Storage{
+			Files: []types.File{{Path: "/etc/firewall-policy-controller/.kubeconfig", Filesystem: "root"}},
+		},
+		Systemd: types.Systemd{
+			Units: []types.SystemdUnit{{Name: "firewall-policy-controller.service", Enable: true}},
+		},
+	}
+
+	Describe("#ParseSnippet", func() {
+		It("should parse files, directories, links and units", func() {
+			cfg, err := ParseSnippet(`
+storage:
+  files:
+  - path: /etc/motd
+    filesystem: root
+    contents:
+      inline: hello
+  directories:
+  - path: /etc/foo
+    filesystem: root
+  links:
+  - path: /etc/bar
+    filesystem: root
+    target: /etc/motd
+systemd:
+  units:
+  - name: foo.service
+    enable: true
+`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Storage.Files).To(HaveLen(1))
+			Expect(cfg.Storage.Files[0].Contents.Inline).To(Equal("hello"))
+			Expect(cfg.Storage.Directories).To(HaveLen(1))
+			Expect(cfg.Storage.Links).To(HaveLen(1))
+			Expect(cfg.Systemd.Units).To(HaveLen(1))
+		})
+
+		It("should forbid other sections", func() {
+			_, err := ParseSnippet(`
+passwd:
+  users:
+  - name: core
+`)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail for invalid yaml", func() {
+			_, err := ParseSnippet("storage: [")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#Merge", func() {
+		It("should append the entries of the snippets and let later snippets win", func() {
+			first := types.Config{
+				Storage: types.Storage{Files: []types.File{{Path: "/etc/motd", Contents: types.FileContents{Inline: "first"}}}},
+				Systemd: types.Systemd{Units: []types.SystemdUnit{{Name: "foo.service"}}},
+			}
+			second := types.Config{
+				Storage: types.Storage{Files: []types.File{{Path: "/etc/motd", Contents: types.FileContents{Inline: "second"}}}},
+			}
+
+			merged, err := Merge(base, first, second)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.Storage.Files).To(HaveLen(2))
+			Expect(merged.Storage.Files[1].Contents.Inline).To(Equal("second"))
+			Expect(merged.Systemd.Units).To(HaveLen(2))
+			Expect(merged.Systemd.Units[1].Name).To(Equal("foo.service"))
+			Expect(base.Storage.Files).To(HaveLen(1))
+		})
+
+		It("should forbid replacing generated files", func() {
+			snippet := types.Config{
+				Storage: types.Storage{Files: []types.File{{Path: "/etc/firewall-policy-controller/.kubeconfig"}}},
+			}
+
+			_, err := Merge(base, snippet)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should forbid replacing generated units", func() {
+			snippet := types.Config{
+				Systemd: types.Systemd{Units: []types.SystemdUnit{{Name: "firewall-policy-controller.service"}}},
+			}
+
+			_, err := Merge(base, snippet)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("#Transpile", func() {
+		It("should transpile a valid config", func() {
+			userData, err := Transpile(base)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userData).To(ContainSubstring("firewall-policy-controller.service"))
+		})
+
+		It("should fail for an invalid config", func() {
+			cfg := types.Config{
+				Storage: types.Storage{Files: []types.File{{Path: "relative/path", Filesystem: "root"}}},
+			}
+
+			_, err := Transpile(cfg)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
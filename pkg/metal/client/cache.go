@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	metalgo "github.com/metal-stack/metal-go"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	cacheResourceNetwork = "network"
+	cacheResourceProject = "project"
+)
+
+// rateLimitedClient waits for the rate limiter before every request to the metal-api. It stops waiting with an error
+// when its context is done.
+type rateLimitedClient struct {
+	ctx     context.Context
+	client  Client
+	limiter flowcontrol.RateLimiter
+}
+
+func (r *rateLimitedClient) FirewallCreate(fcr *metalgo.FirewallCreateRequest) (*metalgo.FirewallCreateResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.FirewallCreate(fcr)
+}
+
+func (r *rateLimitedClient) FirewallFind(ffr *metalgo.FirewallFindRequest) (*metalgo.FirewallListResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.FirewallFind(ffr)
+}
+
+func (r *rateLimitedClient) FirewallGet(machineID string) (*metalgo.FirewallGetResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.FirewallGet(machineID)
+}
+
+func (r *rateLimitedClient) FirewallList() (*metalgo.FirewallListResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.FirewallList()
+}
+
+func (r *rateLimitedClient) MachineDelete(machineID string) (*metalgo.MachineDeleteResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.MachineDelete(machineID)
+}
+
+func (r *rateLimitedClient) NetworkAllocate(ncr *metalgo.NetworkAllocateRequest) (*metalgo.NetworkDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkAllocate(ncr)
+}
+
+func (r *rateLimitedClient) NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkAddPrefix(nur)
+}
+
+func (r *rateLimitedClient) NetworkFind(nfr *metalgo.NetworkFindRequest) (*metalgo.NetworkListResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkFind(nfr)
+}
+
+func (r *rateLimitedClient) NetworkFree(id string) (*metalgo.NetworkDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkFree(id)
+}
+
+func (r *rateLimitedClient) NetworkGet(id string) (*metalgo.NetworkGetResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkGet(id)
+}
+
+func (r *rateLimitedClient) NetworkList() (*metalgo.NetworkListResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.NetworkList()
+}
+
+func (r *rateLimitedClient) IPAllocate(iar *metalgo.IPAllocateRequest) (*metalgo.IPDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.IPAllocate(iar)
+}
+
+func (r *rateLimitedClient) IPFind(ifr *metalgo.IPFindRequest) (*metalgo.IPListResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.IPFind(ifr)
+}
+
+func (r *rateLimitedClient) IPFree(id string) (*metalgo.IPDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.IPFree(id)
+}
+
+func (r *rateLimitedClient) IPUpdate(iur *metalgo.IPUpdateRequest) (*metalgo.IPDetailResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.IPUpdate(iur)
+}
+
+func (r *rateLimitedClient) PartitionGet(partitionID string) (*metalgo.PartitionGetResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.PartitionGet(partitionID)
+}
+
+func (r *rateLimitedClient) ProjectGet(projectID string) (*metalgo.ProjectGetResponse, error) {
+	if err := r.limiter.Wait(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.client.ProjectGet(projectID)
+}
+
+// cache holds the networks and projects read with the same credentials for a short time. It is shared by all caching
+// clients of the credentials, such that a change through one of them drops the cached networks of all of them.
+type cache struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// newCache returns a new cache with the given ttl, nil if the ttl is not positive.
+func newCache(ttl time.Duration, now func() time.Time) *cache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &cache{
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+		now:     now,
+	}
+}
+
+func (c *cache) get(resource, key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[resource+"/"+key]
+	if !ok || !c.now().Before(entry.expires) {
+		cacheMisses.WithLabelValues(resource).Inc()
+		return nil, false
+	}
+	cacheHits.WithLabelValues(resource).Inc()
+	return entry.value, true
+}
+
+func (c *cache) set(resource, key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[resource+"/"+key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+func (c *cache) invalidate(resource string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for k := range c.entries {
+		if strings.HasPrefix(k, resource+"/") {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// cachingClient answers reads of networks and projects from the cache of its credentials. Cached responses are shared
+// between callers and must not be modified. The cached networks are dropped whenever a network is changed with the
+// same credentials, changes by other parties become visible after the ttl.
+type cachingClient struct {
+	Client
+
+	cache *cache
+}
+
+func newCachingClient(c Client, cache *cache) Client {
+	if cache == nil {
+		return c
+	}
+	return &cachingClient{
+		Client: c,
+		cache:  cache,
+	}
+}
+
+func (c *cachingClient) NetworkGet(id string) (*metalgo.NetworkGetResponse, error) {
+	if value, ok := c.cache.get(cacheResourceNetwork, "get/"+id); ok {
+		return value.(*metalgo.NetworkGetResponse), nil
+	}
+	resp, err := c.Client.NetworkGet(id)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(cacheResourceNetwork, "get/"+id, resp)
+	return resp, nil
+}
+
+func (c *cachingClient) NetworkFind(nfr *metalgo.NetworkFindRequest) (*metalgo.NetworkListResponse, error) {
+	raw, err := json.Marshal(nfr)
+	if err != nil {
+		return c.Client.NetworkFind(nfr)
+	}
+	key := "find/" + string(raw)
+
+	if value, ok := c.cache.get(cacheResourceNetwork, key); ok {
+		return value.(*metalgo.NetworkListResponse), nil
+	}
+	resp, err := c.Client.NetworkFind(nfr)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(cacheResourceNetwork, key, resp)
+	return resp, nil
+}
+
+func (c *cachingClient) NetworkList() (*metalgo.NetworkListResponse, error) {
+	if value, ok := c.cache.get(cacheResourceNetwork, "list"); ok {
+		return value.(*metalgo.NetworkListResponse), nil
+	}
+	resp, err := c.Client.NetworkList()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(cacheResourceNetwork, "list", resp)
+	return resp, nil
+}
+
+func (c *cachingClient) NetworkAllocate(ncr *metalgo.NetworkAllocateRequest) (*metalgo.NetworkDetailResponse, error) {
+	defer c.cache.invalidate(cacheResourceNetwork)
+	return c.Client.NetworkAllocate(ncr)
+}
+
+func (c *cachingClient) NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error) {
+	defer c.cache.invalidate(cacheResourceNetwork)
+	return c.Client.NetworkAddPrefix(nur)
+}
+
+func (c *cachingClient) NetworkFree(id string) (*metalgo.NetworkDetailResponse, error) {
+	defer c.cache.invalidate(cacheResourceNetwork)
+	return c.Client.NetworkFree(id)
+}
+
+func (c *cachingClient) ProjectGet(projectID string) (*metalgo.ProjectGetResponse, error) {
+	if value, ok := c.cache.get(cacheResourceProject, projectID); ok {
+		return value.(*metalgo.ProjectGetResponse), nil
+	}
+	resp, err := c.Client.ProjectGet(projectID)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(cacheResourceProject, projectID, resp)
+	return resp, nil
+}
@@ -17,20 +17,80 @@ package client
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
 	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
 	metalgo "github.com/metal-stack/metal-go"
 	"github.com/metal-stack/metal-go/api/models"
-	"github.com/metal-stack/metal-lib/pkg/tag"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Client contains the operations of the metal-api used by the extension. It is implemented by the metal-go driver.
+type Client interface {
+	FirewallCreate(fcr *metalgo.FirewallCreateRequest) (*metalgo.FirewallCreateResponse, error)
+	FirewallFind(ffr *metalgo.FirewallFindRequest) (*metalgo.FirewallListResponse, error)
+	FirewallGet(machineID string) (*metalgo.FirewallGetResponse, error)
+	FirewallList() (*metalgo.FirewallListResponse, error)
+
+	MachineDelete(machineID string) (*metalgo.MachineDeleteResponse, error)
+
+	NetworkAllocate(ncr *metalgo.NetworkAllocateRequest) (*metalgo.NetworkDetailResponse, error)
+	NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error)
+	NetworkFind(nfr *metalgo.NetworkFindRequest) (*metalgo.NetworkListResponse, error)
+	NetworkFree(id string) (*metalgo.NetworkDetailResponse, error)
+	NetworkGet(id string) (*metalgo.NetworkGetResponse, error)
+	NetworkList() (*metalgo.NetworkListResponse, error)
+
+	IPAllocate(iar *metalgo.IPAllocateRequest) (*metalgo.IPDetailResponse, error)
+	IPFind(ifr *metalgo.IPFindRequest) (*metalgo.IPListResponse, error)
+	IPFree(id string) (*metalgo.IPDetailResponse, error)
+	IPUpdate(iur *metalgo.IPUpdateRequest) (*metalgo.IPDetailResponse, error)
+
+	PartitionGet(partitionID string) (*metalgo.PartitionGetResponse, error)
+
+	ProjectGet(projectID string) (*metalgo.ProjectGetResponse, error)
+}
+
+// ClientFactory creates metal clients.
+type ClientFactory interface {
+	// NewClient returns a new metal client with the provider credentials from a given secret reference.
+	NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (Client, error)
+	// NewClientFromCredentials returns a new metal client with the client constructed from the given credentials.
+	NewClientFromCredentials(credentials *metal.Credentials) (Client, error)
+}
+
+// ClientFactoryInjector is implemented by components which create metal clients and allow replacing the factory,
+// e.g. by a fake in tests.
+type ClientFactoryInjector interface {
+	InjectMetalClientFactory(factory ClientFactory) error
+}
+
+// ClientFactoryInto injects the given client factory into the given object if it implements ClientFactoryInjector.
+func ClientFactoryInto(factory ClientFactory, i interface{}) (bool, error) {
+	if injector, ok := i.(ClientFactoryInjector); ok {
+		return true, injector.InjectMetalClientFactory(factory)
+	}
+	return false, nil
+}
+
+type clientFactory struct{}
+
+// DefaultClientFactory is the client factory creating metal-go drivers.
+var DefaultClientFactory ClientFactory = clientFactory{}
+
+func (clientFactory) NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (Client, error) {
+	return NewClient(ctx, k8sClient, secretRef)
+}
+
+func (clientFactory) NewClientFromCredentials(credentials *metal.Credentials) (Client, error) {
+	return NewClientFromCredentials(credentials)
+}
+
 // NewClient returns a new metal client with the provider credentials from a given secret reference.
-func NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (*metalgo.Driver, error) {
+func NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (Client, error) {
 	credentials, err := ReadCredentialsFromSecretRef(ctx, k8sClient, secretRef)
 	if err != nil {
 		return nil, err
@@ -39,13 +99,28 @@ func NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.S
 	return NewClientFromCredentials(credentials)
 }
 
-// NewClientFromCredentials returns a new metal client with the client constructed from the given credentials.
-func NewClientFromCredentials(credentials *metal.Credentials) (*metalgo.Driver, error) {
+// NewClientFromCredentials returns a new metal client with the client constructed from the given credentials. The
+// latency and the errors of its requests are exported as metrics.
+func NewClientFromCredentials(credentials *metal.Credentials) (Client, error) {
 	client, err := metalgo.NewDriver(credentials.MetalAPIURL, credentials.MetalAPIKey, credentials.MetalAPIHMac)
 	if err != nil {
 		return nil, err
 	}
-	return client, nil
+	return newInstrumentedClient(&driver{Driver: client}), nil
+}
+
+// driver adapts the requests of the metal-go driver which do not return the errors of the metal-api as they are.
+type driver struct {
+	*metalgo.Driver
+}
+
+// NetworkAddPrefix adds a prefix to a network. The metal-go driver wraps the error of looking up the network, such that
+// it cannot be classified anymore, so the network is looked up first.
+func (d *driver) NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error) {
+	if _, err := d.Driver.NetworkGet(nur.Networkid); err != nil {
+		return nil, err
+	}
+	return d.Driver.NetworkAddPrefix(nur)
 }
 
 // ReadCredentialsFromSecretRef returns metal credentials from the provider credentials from a given secret reference.
@@ -64,7 +139,7 @@ func ReadCredentialsFromSecretRef(ctx context.Context, k8sClient client.Client,
 }
 
 // GetPrivateNetworksFromNodeNetwork returns the private network that belongs to the given node network cidr and project.
-func GetPrivateNetworksFromNodeNetwork(client *metalgo.Driver, projectID string, nodeNetworkCIDR string) ([]*models.V1NetworkResponse, error) {
+func GetPrivateNetworksFromNodeNetwork(client Client, projectID string, nodeNetworkCIDR string) ([]*models.V1NetworkResponse, error) {
 	if nodeNetworkCIDR == "" {
 		return nil, fmt.Errorf("node network cidr is empty")
 	}
@@ -81,7 +156,7 @@ func GetPrivateNetworksFromNodeNetwork(client *metalgo.Driver, projectID string,
 }
 
 // GetPrivateNetworkFromNodeNetwork returns the private network that belongs to the given node network cidr and project.
-func GetPrivateNetworkFromNodeNetwork(client *metalgo.Driver, projectID string, nodeNetworkCIDR string) (*models.V1NetworkResponse, error) {
+func GetPrivateNetworkFromNodeNetwork(client Client, projectID string, nodeNetworkCIDR string) (*models.V1NetworkResponse, error) {
 	privateNetworks, err := GetPrivateNetworksFromNodeNetwork(client, projectID, nodeNetworkCIDR)
 	if err != nil {
 		return nil, err
@@ -92,8 +167,9 @@ func GetPrivateNetworkFromNodeNetwork(client *metalgo.Driver, projectID string,
 	return privateNetworks[0], nil
 }
 
-// GetEphemeralIPsFromCluster return all ephemeral IPs for given project and cluster
-func GetEphemeralIPsFromCluster(client *metalgo.Driver, projectID, clusterID string) ([]*models.V1IPResponse, []*models.V1IPResponse, error) {
+// GetEphemeralIPsFromCluster returns the ephemeral ips of the given project which are members of the given cluster. The
+// ips which are only members of this cluster are returned first, the ips which are still used by other clusters second.
+func GetEphemeralIPsFromCluster(client Client, projectID, clusterID string) ([]*models.V1IPResponse, []*models.V1IPResponse, error) {
 	ephemeral := metalgo.IPTypeEphemeral
 	ipFindRequest := metalgo.IPFindRequest{
 		ProjectID: &projectID,
@@ -108,52 +184,40 @@ func GetEphemeralIPsFromCluster(client *metalgo.Driver, projectID, clusterID str
 	// those who are member of more clusters must be updated and the tags which references this cluster must be removed.
 	ipsToUpdate := []*models.V1IPResponse{}
 	for _, ip := range ipFindResponse.IPs {
-		clusterCount := 0
-		for _, t := range ip.Tags {
-
-			if isMemberOfCluster(t, clusterID) {
-				clusterCount++
-			}
+		clusterIDs := tags.ClusterIDs(ip.Tags)
+		if !clusterIDs.Has(clusterID) {
+			continue
 		}
-		if clusterCount == 1 {
+		if clusterIDs.Len() == 1 {
 			ipsToFree = append(ipsToFree, ip)
 			continue
 		}
-		// IPs which are used in more than one cluster must be updated to get the tags with this clusterid removed
 		ipsToUpdate = append(ipsToUpdate, ip)
 	}
 	return ipsToFree, ipsToUpdate, nil
 }
 
-// UpdateIPInCluster update the IP in the cluster to have only these tags left which are not from this cluster
-func UpdateIPInCluster(client *metalgo.Driver, ip *models.V1IPResponse, clusterID string) error {
-	var newTags []string
-	for _, t := range ip.Tags {
-		if strings.HasPrefix(t, tag.ClusterServiceFQN+"="+clusterID) {
-			continue
-		}
-		newTags = append(newTags, t)
-	}
+// AddIPToCluster tags the given ip as a member of the given cluster.
+func AddIPToCluster(client Client, ip *models.V1IPResponse, clusterID string) error {
+	return updateIPTags(client, ip, tags.AddClusterMembership(ip.Tags, clusterID))
+}
+
+// RemoveIPFromCluster removes the cluster id tag and the service tags of the given cluster from the given ip, the tags
+// of other clusters are kept.
+func RemoveIPFromCluster(client Client, ip *models.V1IPResponse, clusterID string) error {
+	return updateIPTags(client, ip, tags.RemoveClusterMembership(ip.Tags, clusterID))
+}
+
+// SetIPSeed marks the given ip as managed by the seed with the given name, the mark of another seed is replaced.
+func SetIPSeed(client Client, ip *models.V1IPResponse, seedName string) error {
+	return updateIPTags(client, ip, tags.SetSeed(ip.Tags, seedName))
+}
+
+func updateIPTags(client Client, ip *models.V1IPResponse, newTags []string) error {
 	iur := &metalgo.IPUpdateRequest{
 		IPAddress: *ip.Ipaddress,
 		Tags:      newTags,
 	}
 	_, err := client.IPUpdate(iur)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func isMemberOfCluster(t, clusterID string) bool {
-	if strings.HasPrefix(t, tag.ClusterID) {
-		parts := strings.Split(t, "=")
-		if len(parts) != 2 {
-			return false
-		}
-		if strings.HasPrefix(parts[1], clusterID) {
-			return true
-		}
-	}
-	return false
+	return err
 }
@@ -0,0 +1,67 @@
+package client_test
+
+import (
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	const project = "project-1"
+
+	var metalClient *metalfake.Client
+
+	addIP := func(address string, ipTags ...string) {
+		ephemeral := metalgo.IPTypeEphemeral
+		p := project
+		metalClient.AddIP(&models.V1IPResponse{Ipaddress: &address, Type: &ephemeral, Projectid: &p, Tags: ipTags})
+	}
+
+	addresses := func(ips []*models.V1IPResponse) []string {
+		var result []string
+		for _, ip := range ips {
+			result = append(result, *ip.Ipaddress)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		metalClient = metalfake.NewClient()
+		addIP("10.0.0.1", tags.ClusterID("cluster"))
+		addIP("10.0.0.2", tags.ClusterService(tags.Service{ClusterID: "cluster", Namespace: "default", Name: "ingress"}))
+		addIP("10.0.0.3", tags.ClusterID("cluster"), tags.ClusterService(tags.Service{ClusterID: "cluster-2", Namespace: "default", Name: "ingress"}))
+		addIP("10.0.0.4", tags.ClusterID("cluster-2"))
+	})
+
+	It("should match the ips of the cluster exactly by its id", func() {
+		ipsToFree, ipsToUpdate, err := GetEphemeralIPsFromCluster(metalClient, project, "cluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(addresses(ipsToFree)).To(ConsistOf("10.0.0.1", "10.0.0.2"))
+		Expect(addresses(ipsToUpdate)).To(ConsistOf("10.0.0.3"))
+
+		ipsToFree, ipsToUpdate, err = GetEphemeralIPsFromCluster(metalClient, project, "cluster-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(addresses(ipsToFree)).To(ConsistOf("10.0.0.4"))
+		Expect(addresses(ipsToUpdate)).To(ConsistOf("10.0.0.3"))
+	})
+
+	It("should add and remove the cluster membership of an ip", func() {
+		ipsToFree, ipsToUpdate, err := GetEphemeralIPsFromCluster(metalClient, project, "cluster")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(RemoveIPFromCluster(metalClient, ipsToUpdate[0], "cluster")).To(Succeed())
+		Expect(AddIPToCluster(metalClient, ipsToFree[0], "cluster-2")).To(Succeed())
+
+		ips := map[string][]string{}
+		for _, ip := range metalClient.IPs() {
+			ips[*ip.Ipaddress] = ip.Tags
+		}
+		Expect(ips["10.0.0.3"]).To(Equal([]string{tags.ClusterService(tags.Service{ClusterID: "cluster-2", Namespace: "default", Name: "ingress"})}))
+		Expect(tags.ClusterIDs(ips[*ipsToFree[0].Ipaddress]).List()).To(Equal([]string{"cluster", "cluster-2"}))
+	})
+})
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+
+	"github.com/go-openapi/runtime"
+)
+
+// ErrorClass is the category of an error returned by the metal-api, it determines how a controller reacts to it.
+type ErrorClass string
+
+const (
+	// ErrorClassNotFound is the class of errors for resources which do not exist.
+	ErrorClassNotFound ErrorClass = "NotFound"
+	// ErrorClassConflict is the class of errors for requests which conflict with the current state of a resource.
+	ErrorClassConflict ErrorClass = "Conflict"
+	// ErrorClassUnauthorized is the class of errors for requests with invalid or insufficient credentials.
+	ErrorClassUnauthorized ErrorClass = "Unauthorized"
+	// ErrorClassRetryable is the class of errors which may disappear when the request is repeated, e.g. server errors
+	// or failed connections.
+	ErrorClassRetryable ErrorClass = "Retryable"
+	// ErrorClassRateLimited is the class of errors for requests which were rejected because of too many requests.
+	ErrorClassRateLimited ErrorClass = "RateLimited"
+	// ErrorClassPermanent is the class of errors for requests which are rejected by the metal-api, e.g. because they
+	// are invalid, and which will be rejected again when they are repeated.
+	ErrorClassPermanent ErrorClass = "Permanent"
+)
+
+const (
+	// retryableRequeueAfter is the duration after which a request failed with a retryable error is repeated.
+	retryableRequeueAfter = 30 * time.Second
+	// rateLimitedRequeueAfter is the duration after which a request rejected because of too many requests is repeated.
+	rateLimitedRequeueAfter = 60 * time.Second
+)
+
+// Error is an error returned by the metal-api together with its class.
+type Error struct {
+	// Class is the class of the error.
+	Class ErrorClass
+	// Code is the http status code of the response, it is zero if no response was received.
+	Code int
+	// Err is the original error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("metal-api request failed (%s): %v", e.Class, e.Err)
+	}
+	return fmt.Sprintf("metal-api request failed (%s, status %d): %v", e.Class, e.Code, e.Err)
+}
+
+// Unwrap returns the original error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// coder is implemented by the default responses of the generated metal-api client.
+type coder interface {
+	Code() int
+}
+
+// Classify returns the given error as metal-api error with its class. Errors which were already classified are
+// returned as they are. Errors without a response are only considered retryable if the request failed on the transport,
+// all other errors without a response are local errors of the extension, e.g. a network which does not fit the
+// cluster, and are permanent. Nil is returned for nil.
+func Classify(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified
+	}
+
+	code := 0
+	var c coder
+	var apiErr *runtime.APIError
+	switch {
+	case errors.As(err, &c):
+		code = c.Code()
+	case errors.As(err, &apiErr):
+		code = apiErr.Code
+	}
+
+	class := classFromCode(code)
+	if code == 0 && isTransportError(err) {
+		class = ErrorClassRetryable
+	}
+
+	return &Error{
+		Class: class,
+		Code:  code,
+		Err:   err,
+	}
+}
+
+func classFromCode(code int) ErrorClass {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrorClassNotFound
+	case code == http.StatusConflict:
+		return ErrorClassConflict
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ErrorClassUnauthorized
+	case code == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case code >= 500:
+		// server errors may disappear when the request is repeated
+		return ErrorClassRetryable
+	}
+	return ErrorClassPermanent
+}
+
+// isTransportError returns true if the given error was returned because no response was received, e.g. because the
+// connection failed or timed out.
+func isTransportError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func hasClass(err error, class ErrorClass) bool {
+	if err == nil {
+		return false
+	}
+	return Classify(err).Class == class
+}
+
+// IsNotFound returns true if the metal-api reported that the requested resource does not exist.
+func IsNotFound(err error) bool {
+	return hasClass(err, ErrorClassNotFound)
+}
+
+// IsConflict returns true if the request conflicts with the current state of a resource.
+func IsConflict(err error) bool {
+	return hasClass(err, ErrorClassConflict)
+}
+
+// IsUnauthorized returns true if the metal-api rejected the credentials.
+func IsUnauthorized(err error) bool {
+	return hasClass(err, ErrorClassUnauthorized)
+}
+
+// IsRetryable returns true if the request may succeed when it is repeated.
+func IsRetryable(err error) bool {
+	return hasClass(err, ErrorClassRetryable)
+}
+
+// IsRateLimited returns true if the metal-api rejected the request because of too many requests.
+func IsRateLimited(err error) bool {
+	return hasClass(err, ErrorClassRateLimited)
+}
+
+// IsPermanent returns true if the metal-api rejected the request and repeating it will not help.
+func IsPermanent(err error) bool {
+	return hasClass(err, ErrorClassPermanent)
+}
+
+// IgnoreNotFound returns nil if the metal-api reported that the resource does not exist, the error otherwise. It is
+// used when deleting resources, which are gone already in this case.
+func IgnoreNotFound(err error) error {
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ReconcileError returns the error a controller returns for the given error of a metal-api request. Retryable and rate
+// limited requests are requeued after a fixed duration, all other errors are returned with their class such that the
+// reason is visible in the last error of the resource.
+func ReconcileError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	classified := Classify(err)
+	switch classified.Class {
+	case ErrorClassRetryable:
+		return &controllererrors.RequeueAfterError{
+			Cause:        err,
+			RequeueAfter: retryableRequeueAfter,
+		}
+	case ErrorClassRateLimited:
+		return &controllererrors.RequeueAfterError{
+			Cause:        err,
+			RequeueAfter: rateLimitedRequeueAfter,
+		}
+	default:
+		var wrapped *Error
+		if errors.As(err, &wrapped) {
+			return err
+		}
+		return classified
+	}
+}
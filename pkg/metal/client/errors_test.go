@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	"github.com/metal-stack/metal-go/api/client/ip"
+
+	controllererrors "github.com/gardener/gardener-extensions/pkg/controller/error"
+
+	"github.com/go-openapi/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	DescribeTable("#Classify",
+		func(err error, class ErrorClass) {
+			Expect(Classify(err).Class).To(Equal(class))
+		},
+		Entry("not found", ip.NewFreeIPDefault(http.StatusNotFound), ErrorClassNotFound),
+		Entry("conflict", ip.NewFreeIPDefault(http.StatusConflict), ErrorClassConflict),
+		Entry("unauthorized", ip.NewFreeIPDefault(http.StatusUnauthorized), ErrorClassUnauthorized),
+		Entry("forbidden", ip.NewFreeIPDefault(http.StatusForbidden), ErrorClassUnauthorized),
+		Entry("too many requests", ip.NewFreeIPDefault(http.StatusTooManyRequests), ErrorClassRateLimited),
+		Entry("bad request", ip.NewFreeIPDefault(http.StatusBadRequest), ErrorClassPermanent),
+		Entry("unprocessable entity", ip.NewFreeIPDefault(http.StatusUnprocessableEntity), ErrorClassPermanent),
+		Entry("internal server error", ip.NewFreeIPDefault(http.StatusInternalServerError), ErrorClassRetryable),
+		Entry("undocumented response", runtime.NewAPIError("freeIP", nil, http.StatusNotFound), ErrorClassNotFound),
+		Entry("wrapped response", fmt.Errorf("failed to free ip: %w", ip.NewFreeIPDefault(http.StatusConflict)), ErrorClassConflict),
+		Entry("service unavailable", ip.NewFreeIPDefault(http.StatusServiceUnavailable), ErrorClassRetryable),
+		Entry("failed connection", &url.Error{Op: "Get", URL: "http://metal-api", Err: errors.New("connection refused")}, ErrorClassRetryable),
+		Entry("wrapped failed connection", fmt.Errorf("unable to fetch network: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), ErrorClassRetryable),
+		Entry("timeout", context.DeadlineExceeded, ErrorClassRetryable),
+		Entry("local error", errors.New("node network does not belong to project"), ErrorClassPermanent),
+	)
+
+	It("should return nil for nil", func() {
+		Expect(Classify(nil)).To(BeNil())
+		Expect(ReconcileError(nil)).To(BeNil())
+		Expect(IsNotFound(nil)).To(BeFalse())
+	})
+
+	It("should ignore not found errors", func() {
+		Expect(IgnoreNotFound(ip.NewFreeIPDefault(http.StatusNotFound))).To(Succeed())
+		Expect(IgnoreNotFound(ip.NewFreeIPDefault(http.StatusConflict))).To(HaveOccurred())
+	})
+
+	Describe("#ReconcileError", func() {
+		It("should requeue retryable errors", func() {
+			err := ReconcileError(ip.NewFreeIPDefault(http.StatusServiceUnavailable))
+
+			requeue, ok := err.(*controllererrors.RequeueAfterError)
+			Expect(ok).To(BeTrue())
+			Expect(requeue.RequeueAfter).To(Equal(30 * time.Second))
+		})
+
+		It("should requeue rate limited errors later", func() {
+			err := ReconcileError(ip.NewFreeIPDefault(http.StatusTooManyRequests))
+
+			requeue, ok := err.(*controllererrors.RequeueAfterError)
+			Expect(ok).To(BeTrue())
+			Expect(requeue.RequeueAfter).To(Equal(60 * time.Second))
+		})
+
+		It("should not requeue local errors", func() {
+			err := ReconcileError(fmt.Errorf("node network %q disappeared", "network-1"))
+
+			Expect(err).NotTo(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+			Expect(IsPermanent(err)).To(BeTrue())
+		})
+
+		It("should return permanent errors with their class", func() {
+			err := ReconcileError(ip.NewFreeIPDefault(http.StatusUnauthorized))
+
+			Expect(err).NotTo(BeAssignableToTypeOf(&controllererrors.RequeueAfterError{}))
+			Expect(IsUnauthorized(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("Unauthorized"))
+		})
+	})
+})
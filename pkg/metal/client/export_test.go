@@ -0,0 +1,29 @@
+package client
+
+import (
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// SetPoolClientConstructor replaces the function the given pool uses to create clients.
+func SetPoolClientConstructor(p *Pool, f func(credentials *metal.Credentials) (Client, error)) {
+	p.newClient = f
+}
+
+// SetPoolClock replaces the clock of the given pool.
+func SetPoolClock(p *Pool, now func() time.Time) {
+	p.now = now
+}
+
+// NewInstrumentedClient returns the given client with the latency and the errors of its requests exported as metrics.
+func NewInstrumentedClient(c Client) Client {
+	return newInstrumentedClient(c)
+}
+
+// RequestErrors returns the number of failed requests of the given operation with the given error class.
+func RequestErrors(operation string, class ErrorClass) float64 {
+	return testutil.ToFloat64(requestErrors.WithLabelValues(operation, string(class)))
+}
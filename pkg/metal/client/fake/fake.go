@@ -0,0 +1,673 @@
+// Package fake contains an in-memory implementation of the metal client for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/client/firewall"
+	"github.com/metal-stack/metal-go/api/client/ip"
+	"github.com/metal-stack/metal-go/api/client/machine"
+	"github.com/metal-stack/metal-go/api/client/network"
+	"github.com/metal-stack/metal-go/api/client/partition"
+	"github.com/metal-stack/metal-go/api/client/project"
+	"github.com/metal-stack/metal-go/api/models"
+
+	"github.com/go-openapi/strfmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client is an in-memory metal client. Resources created through the client are returned by subsequent calls, such
+// that the flows of the controllers can be tested without a metal-api. It also implements the client factory and
+// returns itself for every secret and credentials.
+type Client struct {
+	lock sync.Mutex
+
+	firewalls  map[string]*models.V1FirewallResponse
+	networks   map[string]*models.V1NetworkResponse
+	ips        map[string]*models.V1IPResponse
+	partitions map[string]*models.V1PartitionResponse
+	projects   map[string]*models.V1ProjectResponse
+
+	// NodeNetworkPrefixLength is the length of the prefixes of allocated networks.
+	NodeNetworkPrefixLength int
+	// Calls contains the names of all operations called on the client in their order.
+	Calls []string
+
+	failures map[string]error
+	counter  int
+}
+
+var _ metalclient.Client = &Client{}
+var _ metalclient.ClientFactory = &Client{}
+
+// NewClient returns a new in-memory metal client without resources.
+func NewClient() *Client {
+	return &Client{
+		firewalls:               map[string]*models.V1FirewallResponse{},
+		networks:                map[string]*models.V1NetworkResponse{},
+		ips:                     map[string]*models.V1IPResponse{},
+		partitions:              map[string]*models.V1PartitionResponse{},
+		projects:                map[string]*models.V1ProjectResponse{},
+		failures:                map[string]error{},
+		NodeNetworkPrefixLength: 22,
+	}
+}
+
+// NewClient implements metalclient.ClientFactory and returns the client itself.
+func (c *Client) NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (metalclient.Client, error) {
+	return c, nil
+}
+
+// NewClientFromCredentials implements metalclient.ClientFactory and returns the client itself.
+func (c *Client) NewClientFromCredentials(credentials *metal.Credentials) (metalclient.Client, error) {
+	return c, nil
+}
+
+// FailOn lets the given operation on the resource with the given id fail with the given error, a nil error lets the
+// operation succeed again. Only IPFree, IPUpdate and NetworkFree can be failed.
+func (c *Client) FailOn(operation, id string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err == nil {
+		delete(c.failures, operation+"/"+id)
+		return
+	}
+	c.failures[operation+"/"+id] = err
+}
+
+// AddFirewall adds the given firewall to the client.
+func (c *Client) AddFirewall(fw *models.V1FirewallResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.firewalls[*fw.ID] = fw
+}
+
+// AddNetwork adds the given network to the client.
+func (c *Client) AddNetwork(nw *models.V1NetworkResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.networks[*nw.ID] = nw
+}
+
+// AddIP adds the given ip to the client.
+func (c *Client) AddIP(i *models.V1IPResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ips[*i.Ipaddress] = i
+}
+
+// AddPartition adds the given partition to the client.
+func (c *Client) AddPartition(p *models.V1PartitionResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.partitions[*p.ID] = p
+}
+
+// AddProject adds the given project to the client.
+func (c *Client) AddProject(p *models.V1ProjectResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.projects[p.Meta.ID] = p
+}
+
+// Firewalls returns all firewalls ordered by their id.
+func (c *Client) Firewalls() []*models.V1FirewallResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.sortedFirewalls()
+}
+
+// Networks returns all networks ordered by their id.
+func (c *Client) Networks() []*models.V1NetworkResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.sortedNetworks()
+}
+
+// IPs returns all ips ordered by their address.
+func (c *Client) IPs() []*models.V1IPResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.sortedIPs()
+}
+
+// FirewallCreate allocates a new firewall which is immediately reported as allocated successfully.
+func (c *Client) FirewallCreate(fcr *metalgo.FirewallCreateRequest) (*metalgo.FirewallCreateResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "FirewallCreate")
+
+	var networks []*models.V1MachineNetwork
+	for _, n := range fcr.Networks {
+		nw, ok := c.networks[n.NetworkID]
+		if !ok {
+			return nil, firewallError(firewall.NewAllocateFirewallDefault(422), "network %q does not exist", n.NetworkID)
+		}
+		var ips []string
+		for _, address := range fcr.IPs {
+			if i, ok := c.ips[address]; ok && i.Networkid != nil && *i.Networkid == n.NetworkID {
+				ips = append(ips, address)
+			}
+		}
+		networks = append(networks, &models.V1MachineNetwork{
+			Networkid: nw.ID,
+			Prefixes:  nw.Prefixes,
+			Ips:       ips,
+			Private:   nw.Privatesuper,
+			Underlay:  nw.Underlay,
+			Nat:       nw.Nat,
+		})
+	}
+
+	var (
+		id        = fcr.UUID
+		succeeded = true
+		alive     = "Alive"
+		partition = fcr.Partition
+		size      = fcr.Size
+		image     = fcr.Image
+		name      = fcr.Name
+		hostname  = fcr.Hostname
+		projectID = fcr.Project
+		created   = strfmt.DateTime(time.Now())
+	)
+	if id == "" {
+		id = c.nextID("firewall")
+	}
+	if _, ok := c.firewalls[id]; ok {
+		return nil, firewallError(firewall.NewAllocateFirewallDefault(409), "machine %q is already allocated", id)
+	}
+
+	fw := &models.V1FirewallResponse{
+		ID:          &id,
+		Name:        name,
+		Description: fcr.Description,
+		Liveliness:  &alive,
+		Partition:   &models.V1PartitionResponse{ID: &partition},
+		Size:        &models.V1SizeResponse{ID: &size},
+		Tags:        append([]string{}, fcr.Tags...),
+		Allocation: &models.V1MachineAllocation{
+			Created:    &created,
+			Name:       &name,
+			Hostname:   &hostname,
+			Project:    &projectID,
+			Image:      &models.V1ImageResponse{ID: &image},
+			Networks:   networks,
+			Succeeded:  &succeeded,
+			SSHPubKeys: fcr.SSHPublicKeys,
+			UserData:   fcr.UserData,
+		},
+	}
+	c.firewalls[id] = fw
+
+	return &metalgo.FirewallCreateResponse{Firewall: fw}, nil
+}
+
+// FirewallFind returns the firewalls matching the id, name, partition, allocation project and tags of the request.
+func (c *Client) FirewallFind(ffr *metalgo.FirewallFindRequest) (*metalgo.FirewallListResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "FirewallFind")
+
+	resp := &metalgo.FirewallListResponse{}
+	for _, fw := range c.sortedFirewalls() {
+		if ffr.ID != nil && *ffr.ID != *fw.ID {
+			continue
+		}
+		if ffr.Name != nil && *ffr.Name != fw.Name {
+			continue
+		}
+		if ffr.PartitionID != nil && (fw.Partition == nil || *ffr.PartitionID != *fw.Partition.ID) {
+			continue
+		}
+		if ffr.AllocationProject != nil && (fw.Allocation == nil || fw.Allocation.Project == nil || *ffr.AllocationProject != *fw.Allocation.Project) {
+			continue
+		}
+		if !containsAll(fw.Tags, ffr.Tags) {
+			continue
+		}
+		resp.Firewalls = append(resp.Firewalls, fw)
+	}
+	return resp, nil
+}
+
+// FirewallGet returns the firewall with the given id.
+func (c *Client) FirewallGet(machineID string) (*metalgo.FirewallGetResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "FirewallGet")
+
+	fw, ok := c.firewalls[machineID]
+	if !ok {
+		err := firewall.NewFindFirewallDefault(404)
+		err.Payload = errorResponse(404, "firewall %q not found", machineID)
+		return nil, err
+	}
+	return &metalgo.FirewallGetResponse{Firewall: fw}, nil
+}
+
+// FirewallList returns all firewalls.
+func (c *Client) FirewallList() (*metalgo.FirewallListResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "FirewallList")
+
+	return &metalgo.FirewallListResponse{Firewalls: c.sortedFirewalls()}, nil
+}
+
+// MachineDelete deletes the firewall with the given id.
+func (c *Client) MachineDelete(machineID string) (*metalgo.MachineDeleteResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "MachineDelete")
+
+	fw, ok := c.firewalls[machineID]
+	if !ok {
+		err := machine.NewFreeMachineDefault(404)
+		err.Payload = errorResponse(404, "machine %q not found", machineID)
+		return nil, err
+	}
+	delete(c.firewalls, machineID)
+
+	return &metalgo.MachineDeleteResponse{Machine: &models.V1MachineResponse{ID: fw.ID}}, nil
+}
+
+// NetworkAllocate allocates a new private network with a prefix that is not used by any other network.
+func (c *Client) NetworkAllocate(ncr *metalgo.NetworkAllocateRequest) (*metalgo.NetworkDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkAllocate")
+
+	prefix, err := c.nextPrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	id := c.nextID("network")
+	labels := map[string]string{}
+	for k, v := range ncr.Labels {
+		labels[k] = v
+	}
+	nw := &models.V1NetworkResponse{
+		ID:          &id,
+		Name:        ncr.Name,
+		Description: ncr.Description,
+		Partitionid: ncr.PartitionID,
+		Projectid:   ncr.ProjectID,
+		Labels:      labels,
+		Prefixes:    []string{prefix},
+	}
+	c.networks[id] = nw
+
+	return &metalgo.NetworkDetailResponse{Network: nw}, nil
+}
+
+// NetworkAddPrefix adds a prefix to the given network.
+func (c *Client) NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkAddPrefix")
+
+	nw, ok := c.networks[nur.Networkid]
+	if !ok {
+		err := network.NewUpdateNetworkDefault(404)
+		err.Payload = errorResponse(404, "network %q not found", nur.Networkid)
+		return nil, err
+	}
+	if _, _, err := net.ParseCIDR(nur.Prefix); err != nil {
+		e := network.NewUpdateNetworkDefault(422)
+		e.Payload = errorResponse(422, "invalid prefix %q", nur.Prefix)
+		return nil, e
+	}
+	if !contains(nw.Prefixes, nur.Prefix) {
+		nw.Prefixes = append(nw.Prefixes, nur.Prefix)
+	}
+
+	return &metalgo.NetworkDetailResponse{Network: nw}, nil
+}
+
+// NetworkFind returns the networks matching the id, name, partition, project, prefixes and labels of the request.
+func (c *Client) NetworkFind(nfr *metalgo.NetworkFindRequest) (*metalgo.NetworkListResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkFind")
+
+	resp := &metalgo.NetworkListResponse{}
+	for _, nw := range c.sortedNetworks() {
+		if nfr.ID != nil && *nfr.ID != *nw.ID {
+			continue
+		}
+		if nfr.Name != nil && *nfr.Name != nw.Name {
+			continue
+		}
+		if nfr.PartitionID != nil && *nfr.PartitionID != nw.Partitionid {
+			continue
+		}
+		if nfr.ProjectID != nil && *nfr.ProjectID != nw.Projectid {
+			continue
+		}
+		if nfr.PrivateSuper != nil && *nfr.PrivateSuper != (nw.Privatesuper != nil && *nw.Privatesuper) {
+			continue
+		}
+		if !containsAll(nw.Prefixes, nfr.Prefixes) {
+			continue
+		}
+		matches := true
+		for k, v := range nfr.Labels {
+			if nw.Labels[k] != v {
+				matches = false
+			}
+		}
+		if !matches {
+			continue
+		}
+		resp.Networks = append(resp.Networks, nw)
+	}
+	return resp, nil
+}
+
+// NetworkFree releases the network with the given id, it fails if there are still ips in the network.
+func (c *Client) NetworkFree(id string) (*metalgo.NetworkDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkFree")
+	if err, ok := c.failures["NetworkFree/"+id]; ok {
+		return nil, err
+	}
+
+	nw, ok := c.networks[id]
+	if !ok {
+		err := network.NewFreeNetworkDefault(404)
+		err.Payload = errorResponse(404, "network %q not found", id)
+		return nil, err
+	}
+	for _, i := range c.ips {
+		if i.Networkid != nil && *i.Networkid == id {
+			err := network.NewFreeNetworkDefault(422)
+			err.Payload = errorResponse(422, "network %q has ips", id)
+			return nil, err
+		}
+	}
+	delete(c.networks, id)
+
+	return &metalgo.NetworkDetailResponse{Network: nw}, nil
+}
+
+// NetworkGet returns the network with the given id.
+func (c *Client) NetworkGet(id string) (*metalgo.NetworkGetResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkGet")
+
+	nw, ok := c.networks[id]
+	if !ok {
+		err := network.NewFindNetworkDefault(404)
+		err.Payload = errorResponse(404, "network %q not found", id)
+		return nil, err
+	}
+	return &metalgo.NetworkGetResponse{Network: nw}, nil
+}
+
+// NetworkList returns all networks.
+func (c *Client) NetworkList() (*metalgo.NetworkListResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "NetworkList")
+
+	return &metalgo.NetworkListResponse{Networks: c.sortedNetworks()}, nil
+}
+
+// IPAllocate allocates the requested ip or the next free ip of the given network.
+func (c *Client) IPAllocate(iar *metalgo.IPAllocateRequest) (*metalgo.IPDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "IPAllocate")
+
+	if _, ok := c.networks[iar.Networkid]; !ok {
+		err := ip.NewAllocateIPDefault(422)
+		err.Payload = errorResponse(422, "network %q does not exist", iar.Networkid)
+		return nil, err
+	}
+
+	address := iar.IPAddress
+	if address == "" {
+		c.counter++
+		address = fmt.Sprintf("212.0.%d.%d", c.counter/256, c.counter%256)
+	}
+	if _, ok := c.ips[address]; ok {
+		err := ip.NewAllocateIPDefault(409)
+		err.Payload = errorResponse(409, "ip %q is already allocated", address)
+		return nil, err
+	}
+
+	var (
+		networkID = iar.Networkid
+		projectID = iar.Projectid
+		ipType    = iar.Type
+	)
+	if ipType == "" {
+		ipType = metalgo.IPTypeEphemeral
+	}
+	i := &models.V1IPResponse{
+		Ipaddress:   &address,
+		Name:        iar.Name,
+		Description: iar.Description,
+		Networkid:   &networkID,
+		Projectid:   &projectID,
+		Type:        &ipType,
+		Tags:        append([]string{}, iar.Tags...),
+	}
+	c.ips[address] = i
+
+	return &metalgo.IPDetailResponse{IP: i}, nil
+}
+
+// IPFind returns the ips matching the address, project, network, type and tags of the request.
+func (c *Client) IPFind(ifr *metalgo.IPFindRequest) (*metalgo.IPListResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "IPFind")
+
+	resp := &metalgo.IPListResponse{}
+	for _, i := range c.sortedIPs() {
+		if ifr.IPAddress != nil && *ifr.IPAddress != *i.Ipaddress {
+			continue
+		}
+		if ifr.ProjectID != nil && (i.Projectid == nil || *ifr.ProjectID != *i.Projectid) {
+			continue
+		}
+		if ifr.NetworkID != nil && (i.Networkid == nil || *ifr.NetworkID != *i.Networkid) {
+			continue
+		}
+		if ifr.Type != nil && (i.Type == nil || *ifr.Type != *i.Type) {
+			continue
+		}
+		if !containsAll(i.Tags, ifr.Tags) {
+			continue
+		}
+		resp.IPs = append(resp.IPs, i)
+	}
+	return resp, nil
+}
+
+// IPFree releases the given ip.
+func (c *Client) IPFree(id string) (*metalgo.IPDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "IPFree")
+	if err, ok := c.failures["IPFree/"+id]; ok {
+		return nil, err
+	}
+
+	i, ok := c.ips[id]
+	if !ok {
+		err := ip.NewFreeIPDefault(404)
+		err.Payload = errorResponse(404, "ip %q not found", id)
+		return nil, err
+	}
+	delete(c.ips, id)
+
+	return &metalgo.IPDetailResponse{IP: i}, nil
+}
+
+// IPUpdate updates the name, description, type and tags of the given ip.
+func (c *Client) IPUpdate(iur *metalgo.IPUpdateRequest) (*metalgo.IPDetailResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "IPUpdate")
+	if err, ok := c.failures["IPUpdate/"+iur.IPAddress]; ok {
+		return nil, err
+	}
+
+	i, ok := c.ips[iur.IPAddress]
+	if !ok {
+		err := ip.NewUpdateIPDefault(404)
+		err.Payload = errorResponse(404, "ip %q not found", iur.IPAddress)
+		return nil, err
+	}
+	i.Name = iur.Name
+	i.Description = iur.Description
+	if iur.Type != "" {
+		ipType := iur.Type
+		i.Type = &ipType
+	}
+	i.Tags = append([]string{}, iur.Tags...)
+
+	return &metalgo.IPDetailResponse{IP: i}, nil
+}
+
+// PartitionGet returns the partition with the given id.
+func (c *Client) PartitionGet(partitionID string) (*metalgo.PartitionGetResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "PartitionGet")
+
+	p, ok := c.partitions[partitionID]
+	if !ok {
+		err := partition.NewFindPartitionDefault(404)
+		err.Payload = errorResponse(404, "partition %q not found", partitionID)
+		return nil, err
+	}
+	return &metalgo.PartitionGetResponse{Partition: p}, nil
+}
+
+// ProjectGet returns the project with the given id.
+func (c *Client) ProjectGet(projectID string) (*metalgo.ProjectGetResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Calls = append(c.Calls, "ProjectGet")
+
+	p, ok := c.projects[projectID]
+	if !ok {
+		err := project.NewFindProjectDefault(404)
+		err.Payload = errorResponse(404, "project %q not found", projectID)
+		return nil, err
+	}
+	return &metalgo.ProjectGetResponse{Project: p}, nil
+}
+
+func (c *Client) nextID(kind string) string {
+	c.counter++
+	return fmt.Sprintf("%s-%d", kind, c.counter)
+}
+
+// nextPrefix returns the first prefix of the configured length in 10.0.0.0/8 which does not overlap with the
+// prefixes of the existing networks.
+func (c *Client) nextPrefix() (string, error) {
+	size := uint32(1) << uint(32-c.NodeNetworkPrefixLength)
+	for base := uint32(10 << 24); base < uint32(11<<24); base += size {
+		candidate := &net.IPNet{
+			IP:   net.IPv4(byte(base>>24), byte(base>>16), byte(base>>8), byte(base)).To4(),
+			Mask: net.CIDRMask(c.NodeNetworkPrefixLength, 32),
+		}
+		if !c.overlaps(candidate) {
+			return candidate.String(), nil
+		}
+	}
+	err := network.NewAllocateNetworkDefault(422)
+	err.Payload = errorResponse(422, "no free prefix of length %d left", c.NodeNetworkPrefixLength)
+	return "", err
+}
+
+func (c *Client) overlaps(candidate *net.IPNet) bool {
+	for _, nw := range c.networks {
+		for _, prefix := range nw.Prefixes {
+			_, existing, err := net.ParseCIDR(prefix)
+			if err != nil {
+				continue
+			}
+			if existing.Contains(candidate.IP) || candidate.Contains(existing.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Client) sortedFirewalls() []*models.V1FirewallResponse {
+	var result []*models.V1FirewallResponse
+	for _, fw := range c.firewalls {
+		result = append(result, fw)
+	}
+	sort.Slice(result, func(i, j int) bool { return *result[i].ID < *result[j].ID })
+	return result
+}
+
+func (c *Client) sortedNetworks() []*models.V1NetworkResponse {
+	var result []*models.V1NetworkResponse
+	for _, nw := range c.networks {
+		result = append(result, nw)
+	}
+	sort.Slice(result, func(i, j int) bool { return *result[i].ID < *result[j].ID })
+	return result
+}
+
+func (c *Client) sortedIPs() []*models.V1IPResponse {
+	var result []*models.V1IPResponse
+	for _, i := range c.ips {
+		result = append(result, i)
+	}
+	sort.Slice(result, func(i, j int) bool { return *result[i].Ipaddress < *result[j].Ipaddress })
+	return result
+}
+
+func firewallError(err *firewall.AllocateFirewallDefault, format string, args ...interface{}) error {
+	err.Payload = errorResponse(int32(err.Code()), format, args...)
+	return err
+}
+
+func errorResponse(code int32, format string, args ...interface{}) *models.HttperrorsHTTPErrorResponse {
+	message := fmt.Sprintf(format, args...)
+	return &models.HttperrorsHTTPErrorResponse{
+		Statuscode: &code,
+		Message:    &message,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(values, required []string) bool {
+	for _, r := range required {
+		if !contains(values, r) {
+			return false
+		}
+	}
+	return true
+}
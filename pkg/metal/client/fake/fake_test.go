@@ -0,0 +1,105 @@
+package fake_test
+
+import (
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fake", func() {
+	var c *Client
+
+	BeforeEach(func() {
+		c = NewClient()
+		internet := "internet"
+		c.AddNetwork(&models.V1NetworkResponse{ID: &internet, Prefixes: []string{"212.0.0.0/16"}})
+	})
+
+	It("should allocate networks with distinct prefixes", func() {
+		first, err := c.NetworkAllocate(&metalgo.NetworkAllocateRequest{ProjectID: "project-1", PartitionID: "partition-a"})
+		Expect(err).NotTo(HaveOccurred())
+		second, err := c.NetworkAllocate(&metalgo.NetworkAllocateRequest{ProjectID: "project-1", PartitionID: "partition-a"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first.Network.Prefixes).To(HaveLen(1))
+		Expect(second.Network.Prefixes).To(HaveLen(1))
+		Expect(first.Network.Prefixes[0]).NotTo(Equal(second.Network.Prefixes[0]))
+
+		project := "project-1"
+		found, err := c.NetworkFind(&metalgo.NetworkFindRequest{ProjectID: &project})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Networks).To(HaveLen(2))
+	})
+
+	It("should return the allocated ips and release them", func() {
+		allocated, err := c.IPAllocate(&metalgo.IPAllocateRequest{
+			Networkid: "internet",
+			Projectid: "project-1",
+			Tags:      []string{"cluster=a"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*allocated.IP.Type).To(Equal(metalgo.IPTypeEphemeral))
+
+		_, err = c.IPAllocate(&metalgo.IPAllocateRequest{Networkid: "internet", IPAddress: *allocated.IP.Ipaddress})
+		Expect(metalclient.IsConflict(err)).To(BeTrue())
+
+		found, err := c.IPFind(&metalgo.IPFindRequest{Tags: []string{"cluster=a"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.IPs).To(HaveLen(1))
+
+		By("refusing to release the network while it contains ips")
+		_, err = c.NetworkFree("internet")
+		Expect(metalclient.IsPermanent(err)).To(BeTrue())
+
+		_, err = c.IPFree(*allocated.IP.Ipaddress)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.IPs()).To(BeEmpty())
+
+		_, err = c.NetworkFree("internet")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Calls).To(Equal([]string{"IPAllocate", "IPAllocate", "IPFind", "NetworkFree", "IPFree", "NetworkFree"}))
+	})
+
+	It("should report missing resources as not found", func() {
+		_, err := c.MachineDelete("missing")
+		Expect(metalclient.IsNotFound(err)).To(BeTrue())
+		_, err = c.IPFree("10.0.0.1")
+		Expect(metalclient.IsNotFound(err)).To(BeTrue())
+		_, err = c.NetworkFree("missing")
+		Expect(metalclient.IsNotFound(err)).To(BeTrue())
+		_, err = c.NetworkGet("missing")
+		Expect(metalclient.IsNotFound(err)).To(BeTrue())
+		_, err = c.ProjectGet("missing")
+		Expect(metalclient.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should find created firewalls by their tags", func() {
+		created, err := c.FirewallCreate(&metalgo.FirewallCreateRequest{
+			MachineCreateRequest: metalgo.MachineCreateRequest{
+				Hostname:  "firewall",
+				Project:   "project-1",
+				Partition: "partition-a",
+				Size:      "c1-xlarge-x86",
+				Image:     "firewall-1",
+				Tags:      []string{"cluster=a"},
+				Networks: []metalgo.MachineAllocationNetwork{
+					{NetworkID: "internet", Autoacquire: true},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		found, err := c.FirewallFind(&metalgo.FirewallFindRequest{MachineFindRequest: metalgo.MachineFindRequest{Tags: []string{"cluster=a"}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Firewalls).To(HaveLen(1))
+		Expect(found.Firewalls[0].ID).To(Equal(created.Firewall.ID))
+
+		found, err = c.FirewallFind(&metalgo.FirewallFindRequest{MachineFindRequest: metalgo.MachineFindRequest{Tags: []string{"cluster=b"}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Firewalls).To(BeEmpty())
+	})
+})
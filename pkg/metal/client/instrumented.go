@@ -0,0 +1,144 @@
+package client
+
+import (
+	"time"
+
+	metalgo "github.com/metal-stack/metal-go"
+)
+
+// instrumentedClient records the latency and the errors of every request to the metal-api.
+type instrumentedClient struct {
+	client Client
+}
+
+func newInstrumentedClient(c Client) Client {
+	return &instrumentedClient{client: c}
+}
+
+// observe records the duration of a request of the given operation which was started at the given time. A failed
+// request is counted by the class of its error.
+func observe(operation string, start time.Time, err error) {
+	requestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		requestErrors.WithLabelValues(operation, string(Classify(err).Class)).Inc()
+	}
+}
+
+func (i *instrumentedClient) FirewallCreate(fcr *metalgo.FirewallCreateRequest) (*metalgo.FirewallCreateResponse, error) {
+	start := time.Now()
+	resp, err := i.client.FirewallCreate(fcr)
+	observe("FirewallCreate", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) FirewallFind(ffr *metalgo.FirewallFindRequest) (*metalgo.FirewallListResponse, error) {
+	start := time.Now()
+	resp, err := i.client.FirewallFind(ffr)
+	observe("FirewallFind", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) FirewallGet(machineID string) (*metalgo.FirewallGetResponse, error) {
+	start := time.Now()
+	resp, err := i.client.FirewallGet(machineID)
+	observe("FirewallGet", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) FirewallList() (*metalgo.FirewallListResponse, error) {
+	start := time.Now()
+	resp, err := i.client.FirewallList()
+	observe("FirewallList", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) MachineDelete(machineID string) (*metalgo.MachineDeleteResponse, error) {
+	start := time.Now()
+	resp, err := i.client.MachineDelete(machineID)
+	observe("MachineDelete", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkAllocate(ncr *metalgo.NetworkAllocateRequest) (*metalgo.NetworkDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkAllocate(ncr)
+	observe("NetworkAllocate", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkAddPrefix(nur *metalgo.NetworkUpdateRequest) (*metalgo.NetworkDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkAddPrefix(nur)
+	observe("NetworkAddPrefix", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkFind(nfr *metalgo.NetworkFindRequest) (*metalgo.NetworkListResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkFind(nfr)
+	observe("NetworkFind", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkFree(id string) (*metalgo.NetworkDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkFree(id)
+	observe("NetworkFree", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkGet(id string) (*metalgo.NetworkGetResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkGet(id)
+	observe("NetworkGet", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) NetworkList() (*metalgo.NetworkListResponse, error) {
+	start := time.Now()
+	resp, err := i.client.NetworkList()
+	observe("NetworkList", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) IPAllocate(iar *metalgo.IPAllocateRequest) (*metalgo.IPDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.IPAllocate(iar)
+	observe("IPAllocate", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) IPFind(ifr *metalgo.IPFindRequest) (*metalgo.IPListResponse, error) {
+	start := time.Now()
+	resp, err := i.client.IPFind(ifr)
+	observe("IPFind", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) IPFree(id string) (*metalgo.IPDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.IPFree(id)
+	observe("IPFree", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) IPUpdate(iur *metalgo.IPUpdateRequest) (*metalgo.IPDetailResponse, error) {
+	start := time.Now()
+	resp, err := i.client.IPUpdate(iur)
+	observe("IPUpdate", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) PartitionGet(partitionID string) (*metalgo.PartitionGetResponse, error) {
+	start := time.Now()
+	resp, err := i.client.PartitionGet(partitionID)
+	observe("PartitionGet", start, err)
+	return resp, err
+}
+
+func (i *instrumentedClient) ProjectGet(projectID string) (*metalgo.ProjectGetResponse, error) {
+	start := time.Now()
+	resp, err := i.client.ProjectGet(projectID)
+	observe("ProjectGet", start, err)
+	return resp, err
+}
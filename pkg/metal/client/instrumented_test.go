@@ -0,0 +1,31 @@
+package client_test
+
+import (
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	"github.com/metal-stack/metal-go/api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instrumented client", func() {
+	It("should count failed requests by their error class", func() {
+		fake := metalfake.NewClient()
+		fake.AddProject(&models.V1ProjectResponse{Meta: &models.V1Meta{ID: "project-1"}})
+		c := NewInstrumentedClient(fake)
+
+		notFound := RequestErrors("MachineDelete", ErrorClassNotFound)
+		_, err := c.MachineDelete("unknown")
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(RequestErrors("MachineDelete", ErrorClassNotFound)).To(Equal(notFound + 1))
+
+		failed := RequestErrors("ProjectGet", ErrorClassNotFound)
+		resp, err := c.ProjectGet("project-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Project.Meta.ID).To(Equal("project-1"))
+		Expect(RequestErrors("ProjectGet", ErrorClassNotFound)).To(Equal(failed))
+
+		Expect(fake.Calls).To(Equal([]string{"MachineDelete", "ProjectGet"}))
+	})
+})
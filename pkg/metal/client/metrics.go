@@ -0,0 +1,46 @@
+package client
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_client_cache_hits_total",
+			Help: "Total number of metal-api reads answered from the cache of the metal client pool.",
+		},
+		[]string{"resource"},
+	)
+
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_client_cache_misses_total",
+			Help: "Total number of metal-api reads of cacheable resources which were sent to the metal-api.",
+		},
+		[]string{"resource"},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "metal_api_request_duration_seconds",
+			Help:    "Latency of the requests sent to the metal-api by operation.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"operation"},
+	)
+
+	requestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metal_api_request_errors_total",
+			Help: "Total number of failed requests to the metal-api by operation and error class.",
+		},
+		[]string{"operation", "class"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheHits, cacheMisses, requestDuration, requestErrors)
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultQPS is the default number of requests per second sent to the metal-api with the same credentials.
+	DefaultQPS float32 = 20
+	// DefaultBurst is the default number of requests which may be sent at once above the QPS.
+	DefaultBurst = 40
+	// DefaultCacheTTL is the default duration networks and projects are cached.
+	DefaultCacheTTL = 10 * time.Second
+
+	// poolIdleTimeout is the duration after which unused credentials are removed from the pool, e.g. the credentials
+	// of deleted shoots or credentials which were rotated.
+	poolIdleTimeout = time.Hour
+)
+
+// PoolOptions are the options of a client pool.
+type PoolOptions struct {
+	// QPS is the number of requests per second sent to the metal-api with the same credentials.
+	QPS float32
+	// Burst is the number of requests which may be sent at once above the QPS.
+	Burst int
+	// CacheTTL is the duration networks and projects are cached, they are not cached if it is zero.
+	CacheTTL time.Duration
+}
+
+// DefaultPoolOptions returns the default options of a client pool.
+func DefaultPoolOptions() PoolOptions {
+	return PoolOptions{
+		QPS:      DefaultQPS,
+		Burst:    DefaultBurst,
+		CacheTTL: DefaultCacheTTL,
+	}
+}
+
+// Pool is a client factory which shares clients between reconciliations. Clients are shared by their credentials, no
+// matter whether they are read from a secret or given directly. All clients with the same credentials share one rate
+// limit and one cache of networks and projects, requests with other credentials are not limited by them.
+type Pool struct {
+	lock sync.Mutex
+
+	opts    PoolOptions
+	clients map[string]*pooledClient
+
+	newClient func(credentials *metal.Credentials) (Client, error)
+	now       func() time.Time
+}
+
+// pooledClient contains the client and the state shared by all clients of one set of credentials.
+type pooledClient struct {
+	client   Client
+	limiter  flowcontrol.RateLimiter
+	cache    *cache
+	lastUsed time.Time
+}
+
+var _ ClientFactory = &Pool{}
+
+// NewPool returns a new client pool with the given options.
+func NewPool(opts PoolOptions) *Pool {
+	return &Pool{
+		opts:      opts,
+		clients:   map[string]*pooledClient{},
+		newClient: NewClientFromCredentials,
+		now:       time.Now,
+	}
+}
+
+// NewClient returns a client for the credentials in the given secret. The returned client stops waiting for the rate
+// limit when the given context is done.
+func (p *Pool) NewClient(ctx context.Context, k8sClient client.Client, secretRef *corev1.SecretReference) (Client, error) {
+	secret, err := extensionscontroller.GetSecretByReference(ctx, k8sClient, secretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := metal.ReadCredentialsSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.get(ctx, credentials)
+}
+
+// NewClientFromCredentials returns a client for the given credentials.
+func (p *Pool) NewClientFromCredentials(credentials *metal.Credentials) (Client, error) {
+	return p.get(context.Background(), credentials)
+}
+
+func (p *Pool) get(ctx context.Context, credentials *metal.Credentials) (Client, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.now()
+	p.removeIdle(now)
+
+	// the key does not contain the credentials themselves, as the keys of the pool might end up in logs or dumps
+	hash := sha256.Sum256([]byte(credentials.MetalAPIURL + "\x00" + credentials.MetalAPIKey + "\x00" + credentials.MetalAPIHMac))
+	key := fmt.Sprintf("%x", hash)
+
+	pooled, ok := p.clients[key]
+	if !ok {
+		c, err := p.newClient(credentials)
+		if err != nil {
+			return nil, err
+		}
+		pooled = &pooledClient{
+			client:  c,
+			limiter: flowcontrol.NewTokenBucketRateLimiter(p.opts.QPS, p.opts.Burst),
+			cache:   newCache(p.opts.CacheTTL, p.now),
+		}
+		p.clients[key] = pooled
+	}
+	pooled.lastUsed = now
+
+	return newCachingClient(&rateLimitedClient{ctx: ctx, client: pooled.client, limiter: pooled.limiter}, pooled.cache), nil
+}
+
+func (p *Pool) removeIdle(now time.Time) {
+	for key, pooled := range p.clients {
+		if now.Sub(pooled.lastUsed) > poolIdleTimeout {
+			pooled.limiter.Stop()
+			delete(p.clients, key)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package client_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	metalgo "github.com/metal-stack/metal-go"
+	"github.com/metal-stack/metal-go/api/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Pool", func() {
+	var (
+		ctx = context.TODO()
+
+		now       time.Time
+		created   []*metalfake.Client
+		k8sClient client.Client
+		secret    *corev1.Secret
+		secretRef = &corev1.SecretReference{Name: "cloudprovider", Namespace: "shoot--foo--bar"}
+		pool      *Pool
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		created = nil
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretRef.Name, Namespace: secretRef.Namespace},
+			Data: map[string][]byte{
+				metal.APIURL:  []byte("http://metal-api"),
+				metal.APIHMac: []byte("hmac"),
+			},
+		}
+		k8sClient = fake.NewFakeClient(secret)
+
+		pool = NewPool(PoolOptions{QPS: 1000, Burst: 1000, CacheTTL: 10 * time.Second})
+		SetPoolClock(pool, func() time.Time { return now })
+		SetPoolClientConstructor(pool, func(credentials *metal.Credentials) (Client, error) {
+			c := metalfake.NewClient()
+			project := "project-1"
+			c.AddProject(&models.V1ProjectResponse{Meta: &models.V1Meta{ID: project}})
+			created = append(created, c)
+			return c, nil
+		})
+	})
+
+	It("should reuse the client until the credentials secret changes", func() {
+		_, err := pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(1))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, secret)).To(Succeed())
+		secret.Data[metal.APIHMac] = []byte("rotated")
+		Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+		_, err = pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(2))
+	})
+
+	It("should share the client and its cache between a secret and the same credentials", func() {
+		fromSecret, err := pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+		fromCredentials, err := pool.NewClientFromCredentials(&metal.Credentials{MetalAPIURL: "http://metal-api", MetalAPIHMac: "hmac"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(1))
+
+		project := "project-1"
+		find := &metalgo.NetworkFindRequest{ProjectID: &project}
+		_, err = fromSecret.NetworkFind(find)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = fromCredentials.NetworkAllocate(&metalgo.NetworkAllocateRequest{ProjectID: project, PartitionID: "partition-a"})
+		Expect(err).NotTo(HaveOccurred())
+
+		networks, err := fromSecret.NetworkFind(find)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks.Networks).To(HaveLen(1))
+		Expect(created[0].Calls).To(Equal([]string{"NetworkFind", "NetworkAllocate", "NetworkFind"}))
+	})
+
+	It("should rate limit every set of credentials on its own and stop waiting when the context is done", func() {
+		pool = NewPool(PoolOptions{QPS: 0.001, Burst: 1})
+		SetPoolClientConstructor(pool, func(credentials *metal.Credentials) (Client, error) {
+			c := metalfake.NewClient()
+			c.AddProject(&models.V1ProjectResponse{Meta: &models.V1Meta{ID: "project-1"}})
+			return c, nil
+		})
+
+		cancelled, cancel := context.WithCancel(ctx)
+		c, err := pool.NewClient(cancelled, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.ProjectGet("project-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		cancel()
+		_, err = c.ProjectGet("project-1")
+		Expect(err).To(HaveOccurred())
+
+		other, err := pool.NewClientFromCredentials(&metal.Credentials{MetalAPIURL: "http://metal-api", MetalAPIHMac: "other"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = other.ProjectGet("project-1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should remove clients which were not used for a long time", func() {
+		_, err := pool.NewClientFromCredentials(&metal.Credentials{MetalAPIURL: "http://metal-api", MetalAPIHMac: "hmac"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = pool.NewClientFromCredentials(&metal.Credentials{MetalAPIURL: "http://metal-api", MetalAPIHMac: "hmac"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(1))
+
+		now = now.Add(2 * time.Hour)
+		_, err = pool.NewClientFromCredentials(&metal.Credentials{MetalAPIURL: "http://metal-api", MetalAPIHMac: "hmac"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(HaveLen(2))
+	})
+
+	It("should cache projects and networks until the ttl expires", func() {
+		c, err := pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			_, err := c.ProjectGet("project-1")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(created[0].Calls).To(Equal([]string{"ProjectGet"}))
+
+		now = now.Add(11 * time.Second)
+		_, err = c.ProjectGet("project-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created[0].Calls).To(Equal([]string{"ProjectGet", "ProjectGet"}))
+	})
+
+	It("should not cache errors", func() {
+		c, err := pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = c.ProjectGet("missing")
+		Expect(IsNotFound(err)).To(BeTrue())
+		_, err = c.ProjectGet("missing")
+		Expect(IsNotFound(err)).To(BeTrue())
+		Expect(created[0].Calls).To(Equal([]string{"ProjectGet", "ProjectGet"}))
+	})
+
+	It("should drop the cached networks when a network is changed", func() {
+		c, err := pool.NewClient(ctx, k8sClient, secretRef)
+		Expect(err).NotTo(HaveOccurred())
+
+		project := "project-1"
+		find := &metalgo.NetworkFindRequest{ProjectID: &project}
+
+		networks, err := c.NetworkFind(find)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks.Networks).To(BeEmpty())
+
+		_, err = c.NetworkAllocate(&metalgo.NetworkAllocateRequest{ProjectID: project, PartitionID: "partition-a"})
+		Expect(err).NotTo(HaveOccurred())
+
+		networks, err = c.NetworkFind(find)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks.Networks).To(HaveLen(1))
+
+		networks, err = c.NetworkFind(find)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks.Networks).To(HaveLen(1))
+		Expect(created[0].Calls).To(Equal([]string{"NetworkFind", "NetworkAllocate", "NetworkFind"}))
+	})
+})
@@ -0,0 +1,221 @@
+// Package tags builds and parses the tags of metal resources. Tags are strings of the form key=value, the cluster
+// membership of a resource is matched exactly on the cluster id and never by prefix.
+package tags
+
+import (
+	"strings"
+
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Keys of the Kubernetes tags of the machines of a cluster.
+const (
+	KubernetesCluster        = "kubernetes.io/cluster"
+	KubernetesRole           = "kubernetes.io/role"
+	KubernetesInstanceType   = "node.kubernetes.io/instance-type"
+	KubernetesTopologyRegion = "topology.kubernetes.io/region"
+	KubernetesTopologyZone   = "topology.kubernetes.io/zone"
+
+	// KubernetesRoleNode is the role of the worker machines of a cluster.
+	KubernetesRoleNode = "node"
+)
+
+// Seed is the key of the tag marking a resource as managed by the extension on the seed named in its value. Networks
+// carry it as a label. Only resources marked for its own seed are considered by the orphan collector of a seed.
+const Seed = "cluster.metal-stack.io/seed"
+
+const (
+	keyValueSeparator = "="
+	serviceSeparator  = "/"
+)
+
+// Tag is a metal tag consisting of a key and a value.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// New returns the tag with the given key and value.
+func New(key, value string) Tag {
+	return Tag{Key: key, Value: value}
+}
+
+// String returns the tag as it is stored at a metal resource.
+func (t Tag) String() string {
+	return t.Key + keyValueSeparator + t.Value
+}
+
+// Parse parses the given tag. Tags without a separator or with an empty key are rejected, the value is everything
+// after the first separator.
+func Parse(s string) (Tag, bool) {
+	parts := strings.SplitN(s, keyValueSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return Tag{}, false
+	}
+	return New(parts[0], parts[1]), true
+}
+
+// Value returns the value of the first tag with the given key.
+func Value(tags []string, key string) (string, bool) {
+	for _, s := range tags {
+		t, ok := Parse(s)
+		if ok && t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// ClusterID returns the tag marking a resource as a member of the cluster with the given id.
+func ClusterID(clusterID string) string {
+	return New(tag.ClusterID, clusterID).String()
+}
+
+// ClusterName returns the tag containing the name of the cluster, which is the namespace of the shoot in the seed.
+func ClusterName(name string) string {
+	return New(tag.ClusterName, name).String()
+}
+
+// ClusterProject returns the tag containing the metal project of the cluster.
+func ClusterProject(projectID string) string {
+	return New(tag.ClusterProject, projectID).String()
+}
+
+// SeedName returns the tag marking a resource as managed by the extension on the seed with the given name.
+func SeedName(name string) string {
+	return New(Seed, name).String()
+}
+
+// IsManagedBySeed returns true if the resource with the given tags is marked as managed by the seed with the given
+// name.
+func IsManagedBySeed(tags []string, name string) bool {
+	seed, ok := Value(tags, Seed)
+	return ok && seed == name
+}
+
+// SetSeed returns the given tags with the seed tag replaced by the one of the seed with the given name. The given tags
+// are not modified.
+func SetSeed(tags []string, name string) []string {
+	result := []string{}
+	for _, s := range tags {
+		if t, ok := Parse(s); ok && t.Key == Seed {
+			continue
+		}
+		result = append(result, s)
+	}
+	return append(result, SeedName(name))
+}
+
+// Service is a service of a cluster which uses a metal ip.
+type Service struct {
+	ClusterID string
+	Namespace string
+	Name      string
+}
+
+// String returns the service as it is written in the value of the service tag.
+func (s Service) String() string {
+	return strings.Join([]string{s.ClusterID, s.Namespace, s.Name}, serviceSeparator)
+}
+
+// ClusterService returns the tag marking an ip as used by the given service.
+func ClusterService(s Service) string {
+	return New(tag.ClusterServiceFQN, s.String()).String()
+}
+
+// ParseClusterID returns the cluster id of the given cluster id tag.
+func ParseClusterID(s string) (string, bool) {
+	t, ok := Parse(s)
+	if !ok || t.Key != tag.ClusterID || t.Value == "" {
+		return "", false
+	}
+	return t.Value, true
+}
+
+// ParseClusterService returns the service of the given cluster service tag.
+func ParseClusterService(s string) (Service, bool) {
+	t, ok := Parse(s)
+	if !ok || t.Key != tag.ClusterServiceFQN {
+		return Service{}, false
+	}
+	parts := strings.SplitN(t.Value, serviceSeparator, 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return Service{}, false
+	}
+	return Service{ClusterID: parts[0], Namespace: parts[1], Name: parts[2]}, true
+}
+
+// KubernetesTopology contains the Kubernetes tags of a machine of a cluster.
+type KubernetesTopology struct {
+	// Cluster is the name of the cluster.
+	Cluster string
+	// InstanceType is the size of the machine.
+	InstanceType string
+	// Region is the region of the cluster.
+	Region string
+	// Zone is the partition of the machine.
+	Zone string
+}
+
+// Tags returns the Kubernetes tags of a node with the given topology.
+func (k KubernetesTopology) Tags() []string {
+	return []string{
+		New(KubernetesCluster, k.Cluster).String(),
+		New(KubernetesRole, KubernetesRoleNode).String(),
+		New(KubernetesInstanceType, k.InstanceType).String(),
+		New(KubernetesTopologyRegion, k.Region).String(),
+		New(KubernetesTopologyZone, k.Zone).String(),
+	}
+}
+
+// ClusterIDs returns the ids of all clusters the resource with the given tags is a member of. Besides the cluster id
+// tag this includes the service tags of ips.
+func ClusterIDs(tags []string) sets.String {
+	ids := sets.NewString()
+	for _, s := range tags {
+		if id, ok := ParseClusterID(s); ok {
+			ids.Insert(id)
+			continue
+		}
+		if service, ok := ParseClusterService(s); ok {
+			ids.Insert(service.ClusterID)
+		}
+	}
+	return ids
+}
+
+// IsMemberOfCluster returns true if the resource with the given tags is a member of the cluster with the given id.
+func IsMemberOfCluster(tags []string, clusterID string) bool {
+	return ClusterIDs(tags).Has(clusterID)
+}
+
+// AddClusterMembership returns the given tags of an ip or machine with the cluster id tag of the given cluster added.
+// The given tags are not modified.
+func AddClusterMembership(tags []string, clusterID string) []string {
+	result := append([]string{}, tags...)
+	membership := ClusterID(clusterID)
+	for _, s := range tags {
+		if s == membership {
+			return result
+		}
+	}
+	return append(result, membership)
+}
+
+// RemoveClusterMembership returns the given tags of an ip or machine without the cluster id tag and the service tags
+// of the given cluster. The given tags are not modified.
+func RemoveClusterMembership(tags []string, clusterID string) []string {
+	result := []string{}
+	for _, s := range tags {
+		if id, ok := ParseClusterID(s); ok && id == clusterID {
+			continue
+		}
+		if service, ok := ParseClusterService(s); ok && service.ClusterID == clusterID {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
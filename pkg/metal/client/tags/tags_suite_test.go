@@ -0,0 +1,13 @@
+package tags_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTags(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metal Tags Suite")
+}
@@ -0,0 +1,156 @@
+package tags_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	. "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// name is a random non-empty name as it is used for cluster ids, namespaces and services.
+type name string
+
+func (name) Generate(r *rand.Rand, size int) reflect.Value {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789-"
+	b := make([]byte, 1+r.Intn(size+1))
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return reflect.ValueOf(name(b))
+}
+
+// resourceTags are random tags of a metal resource, a mix of cluster id tags, service tags and unrelated tags of a few
+// clusters whose ids share prefixes.
+type resourceTags []string
+
+func (resourceTags) Generate(r *rand.Rand, size int) reflect.Value {
+	clusterIDs := []string{"a", "ab", "abc", "b", "abc-d"}
+	var result []string
+	n := r.Intn(size + 1)
+	for i := 0; i < n; i++ {
+		clusterID := clusterIDs[r.Intn(len(clusterIDs))]
+		switch r.Intn(4) {
+		case 0:
+			result = append(result, ClusterID(clusterID))
+		case 1:
+			result = append(result, ClusterService(Service{ClusterID: clusterID, Namespace: "default", Name: "ingress"}))
+		case 2:
+			result = append(result, ClusterName(clusterID))
+		default:
+			result = append(result, "firewall.metal-stack.io/rules-hash="+clusterID)
+		}
+	}
+	return reflect.ValueOf(resourceTags(result))
+}
+
+// equal returns true if both tag lists contain the same tags in the same order, nil and empty lists are equal.
+func equal(a, b []string) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+var _ = Describe("Tags", func() {
+	check := func(property interface{}) {
+		Expect(quick.Check(property, &quick.Config{MaxCount: 500})).To(Succeed())
+	}
+
+	It("should parse the tags it builds", func() {
+		check(func(key, value name) bool {
+			t, ok := Parse(New(string(key), string(value)).String())
+			return ok && t == New(string(key), string(value))
+		})
+		check(func(clusterID name) bool {
+			id, ok := ParseClusterID(ClusterID(string(clusterID)))
+			return ok && id == string(clusterID)
+		})
+		check(func(clusterID, namespace, service name) bool {
+			s := Service{ClusterID: string(clusterID), Namespace: string(namespace), Name: string(service)}
+			parsed, ok := ParseClusterService(ClusterService(s))
+			return ok && parsed == s
+		})
+	})
+
+	It("should reject malformed tags", func() {
+		_, ok := Parse("no-separator")
+		Expect(ok).To(BeFalse())
+		_, ok = Parse("=value")
+		Expect(ok).To(BeFalse())
+		_, ok = ParseClusterID(tag.ClusterID + "=")
+		Expect(ok).To(BeFalse())
+		_, ok = ParseClusterService(tag.ClusterServiceFQN + "=cluster/default")
+		Expect(ok).To(BeFalse())
+		_, ok = ParseClusterID(tag.ClusterName + "=cluster")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not match clusters whose ids share a prefix", func() {
+		check(func(clusterID, suffix name) bool {
+			other := string(clusterID) + string(suffix)
+			t := []string{
+				ClusterID(other),
+				ClusterService(Service{ClusterID: other, Namespace: "default", Name: "ingress"}),
+			}
+			return !IsMemberOfCluster(t, string(clusterID)) && IsMemberOfCluster(t, other)
+		})
+	})
+
+	It("should add the cluster membership idempotently", func() {
+		check(func(t resourceTags, clusterID name) bool {
+			original := append([]string{}, t...)
+			added := AddClusterMembership(t, string(clusterID))
+			return IsMemberOfCluster(added, string(clusterID)) &&
+				equal(AddClusterMembership(added, string(clusterID)), added) &&
+				ClusterIDs(added).Equal(ClusterIDs(t).Insert(string(clusterID))) &&
+				equal(t, original)
+		})
+	})
+
+	It("should only remove the membership of the given cluster", func() {
+		check(func(t resourceTags, clusterID name) bool {
+			original := append([]string{}, t...)
+			removed := RemoveClusterMembership(t, string(clusterID))
+			return !IsMemberOfCluster(removed, string(clusterID)) &&
+				ClusterIDs(removed).Equal(ClusterIDs(t).Delete(string(clusterID))) &&
+				equal(t, original)
+		})
+		check(func(t resourceTags) bool {
+			return equal(RemoveClusterMembership(t, "a"), RemoveClusterMembership(RemoveClusterMembership(t, "a"), "a")) &&
+				ClusterIDs(RemoveClusterMembership(t, "a")).HasAll(ClusterIDs(t).Delete("a").List()...)
+		})
+	})
+
+	It("should keep the tags of other clusters when adding and removing a membership", func() {
+		check(func(t resourceTags, clusterID name) bool {
+			if IsMemberOfCluster(t, string(clusterID)) {
+				return true
+			}
+			return equal(RemoveClusterMembership(AddClusterMembership(t, string(clusterID)), string(clusterID)), t)
+		})
+	})
+
+	It("should replace the seed managing a resource", func() {
+		check(func(t resourceTags, seed name) bool {
+			original := append([]string{}, t...)
+			set := SetSeed(t, string(seed))
+			return IsManagedBySeed(set, string(seed)) &&
+				equal(SetSeed(set, string(seed)), set) &&
+				ClusterIDs(set).Equal(ClusterIDs(t)) &&
+				equal(t, original)
+		})
+		Expect(IsManagedBySeed([]string{SeedName("seed-a")}, "seed-b")).To(BeFalse())
+	})
+
+	It("should build the Kubernetes topology tags of a node", func() {
+		Expect(KubernetesTopology{Cluster: "shoot--foo--bar", InstanceType: "c1-xlarge-x86", Region: "region", Zone: "partition-a"}.Tags()).To(Equal([]string{
+			"kubernetes.io/cluster=shoot--foo--bar",
+			"kubernetes.io/role=node",
+			"node.kubernetes.io/instance-type=c1-xlarge-x86",
+			"topology.kubernetes.io/region=region",
+			"topology.kubernetes.io/zone=partition-a",
+		}))
+	})
+})
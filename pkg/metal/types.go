@@ -40,6 +40,16 @@ const (
 	CloudProviderConfigName = "cloud-provider-config"
 	// MachineControllerManagerName is a constant for the name of the machine-controller-manager.
 	MachineControllerManagerName = "machine-controller-manager"
+	// CloudControllerManagerDeploymentName is a constant for the name of the cloud-controller-manager deployment.
+	CloudControllerManagerDeploymentName = "cloud-controller-manager"
+	// AuthNWebhookDeploymentName is a constant for the name of the authn webhook deployment.
+	AuthNWebhookDeploymentName = "kube-jwt-authn-webhook"
+	// AccountingExporterDeploymentName is a constant for the name of the accounting exporter deployment.
+	AccountingExporterDeploymentName = "accounting-exporter"
+	// GroupRolebindingControllerDeploymentName is a constant for the name of the group rolebinding controller deployment.
+	GroupRolebindingControllerDeploymentName = "group-rolebinding-controller"
+	// LimitValidatingWebhookDeploymentName is a constant for the name of the limit validating webhook deployment.
+	LimitValidatingWebhookDeploymentName = "limit-validating-webhook"
 
 	// AuthN Webhook
 	AuthNWebHookConfigName        = "authn-webhook-config"
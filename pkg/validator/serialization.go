@@ -29,3 +29,14 @@ func decodeInfrastructureConfig(decoder runtime.Decoder, infra *core.ProviderCon
 
 	return infraConfig, nil
 }
+
+func decodeWorkerConfig(decoder runtime.Decoder, worker *core.ProviderConfig, fldPath *field.Path) (*metal.WorkerConfig, error) {
+	workerConfig := &metal.WorkerConfig{}
+	if worker != nil && worker.Raw != nil {
+		if err := util.Decode(decoder, worker.Raw, workerConfig); err != nil {
+			return nil, field.Invalid(fldPath, string(worker.Raw), "isn't a supported version")
+		}
+	}
+
+	return workerConfig, nil
+}
@@ -34,6 +34,10 @@ func (v *Shoot) validateShoot(ctx context.Context, shoot *core.Shoot) error {
 		return errList.ToAggregate()
 	}
 
+	if errList := metalvalidation.ValidateInfrastructureConfigAgainstNetworking(infraConfig, shoot.Spec.Networking, infraConfigFldPath); len(errList) != 0 {
+		return errList.ToAggregate()
+	}
+
 	cloudProfile := &gardencorev1beta1.CloudProfile{}
 	if err := v.client.Get(ctx, kutil.Key(shoot.Spec.CloudProfileName), cloudProfile); err != nil {
 		return err
@@ -66,10 +70,23 @@ func (v *Shoot) validateShoot(ctx context.Context, shoot *core.Shoot) error {
 	}
 
 	// Shoot workers
-	if errList := metalvalidation.ValidateWorkers(shoot.Spec.Provider.Workers, cloudProfile, fldPath); len(errList) != 0 {
+	if errList := metalvalidation.ValidateWorkers(shoot.Spec.Provider.Workers, infraConfig, cloudProfile, fldPath); len(errList) != 0 {
 		return errList.ToAggregate()
 	}
 
+	for i, worker := range shoot.Spec.Provider.Workers {
+		workerConfigFldPath := fldPath.Child("workers").Index(i).Child("providerConfig")
+
+		workerConfig, err := decodeWorkerConfig(v.decoder, worker.ProviderConfig, workerConfigFldPath)
+		if err != nil {
+			return err
+		}
+
+		if errList := metalvalidation.ValidateWorkerConfig(workerConfig, worker, infraConfig, workerConfigFldPath); len(errList) != 0 {
+			return errList.ToAggregate()
+		}
+	}
+
 	return nil
 }
 
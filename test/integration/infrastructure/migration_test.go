@@ -0,0 +1,240 @@
+package infrastructure_test
+
+import (
+	"context"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/install"
+	metalv1alpha1 "github.com/metal-stack/gardener-extension-provider-metal/pkg/apis/metal/v1alpha1"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/infrastructure"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+	metalclient "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client"
+	metalfake "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/fake"
+	metaltags "github.com/metal-stack/gardener-extension-provider-metal/pkg/metal/client/tags"
+	"github.com/metal-stack/metal-go/api/models"
+	"github.com/metal-stack/metal-lib/pkg/tag"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+var _ = Describe("Migration", func() {
+	const (
+		clusterID  = "cluster-id"
+		firewallID = "firewall-1"
+		sourceSeed = "seed-a"
+		targetSeed = "seed-b"
+		partition  = "partition-a"
+		nodeCIDR   = "10.0.0.0/22"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		scheme      *runtime.Scheme
+		c           client.Client
+		metalClient *metalfake.Client
+		namespace   *corev1.Namespace
+		cluster     *extensionscontroller.Cluster
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		install.Install(scheme)
+
+		var err error
+		c, err = client.New(restConfig, client.Options{Scheme: scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "shoot--foo--"}}
+		Expect(c.Create(ctx, namespace)).To(Succeed())
+
+		// the migrated clusters are recorded in the garden namespace of the source seed
+		garden := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: v1beta1constants.GardenNamespace}}
+		if err := c.Create(ctx, garden); !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		var (
+			size      = "c1-xlarge-x86"
+			image     = "firewall-1"
+			id        = firewallID
+			networkID = "private-network"
+			succeeded = true
+			alive     = "Alive"
+			hostname  = "firewall"
+			project   = "project-1"
+			internet  = "internet"
+		)
+		metalClient = metalfake.NewClient()
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:          &networkID,
+			Prefixes:    []string{nodeCIDR},
+			Projectid:   project,
+			Partitionid: partition,
+			Labels:      map[string]string{tag.ClusterID: clusterID},
+		})
+		metalClient.AddNetwork(&models.V1NetworkResponse{
+			ID:       &internet,
+			Prefixes: []string{"212.1.0.0/16"},
+		})
+		metalClient.AddFirewall(&models.V1FirewallResponse{
+			ID:         &id,
+			Liveliness: &alive,
+			Partition:  &models.V1PartitionResponse{ID: &[]string{partition}[0]},
+			Size:       &models.V1SizeResponse{ID: &size},
+			Tags:       []string{tag.ClusterID + "=" + clusterID, metaltags.SeedName(sourceSeed)},
+			Allocation: &models.V1MachineAllocation{
+				Hostname:  &hostname,
+				Project:   &project,
+				Image:     &models.V1ImageResponse{ID: &image},
+				Succeeded: &succeeded,
+			},
+		})
+
+		cluster = &extensionscontroller.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "bar",
+					UID:  clusterID,
+				},
+			},
+			Seed: &gardencorev1beta1.Seed{ObjectMeta: metav1.ObjectMeta{Name: sourceSeed}},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(c.Delete(ctx, namespace)).To(Succeed())
+	})
+
+	newActuator := func() interface {
+		Reconcile(context.Context, *extensionsv1alpha1.Infrastructure, *extensionscontroller.Cluster) error
+		Delete(context.Context, *extensionsv1alpha1.Infrastructure, *extensionscontroller.Cluster) error
+	} {
+		a := infrastructure.NewActuator(record.NewFakeRecorder(100), 0)
+		_, err := inject.SchemeInto(scheme, a)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = inject.ClientInto(c, a)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = metalclient.ClientFactoryInto(metalClient, a)
+		Expect(err).NotTo(HaveOccurred())
+		return a
+	}
+
+	newInfrastructure := func() *extensionsv1alpha1.Infrastructure {
+		return &extensionsv1alpha1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "infrastructure", Namespace: namespace.Name},
+			Spec: extensionsv1alpha1.InfrastructureSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type: metal.Type,
+					ProviderConfig: &runtime.RawExtension{
+						Raw: []byte(`{
+  "apiVersion": "metal.provider.extensions.gardener.cloud/v1alpha1",
+  "kind": "InfrastructureConfig",
+  "projectID": "project-1",
+  "partitionID": "partition-a",
+  "firewall": {"size": "c1-xlarge-x86", "image": "firewall-1", "networks": ["internet"]}
+}`),
+					},
+				},
+				Region:    "region-a",
+				SecretRef: corev1.SecretReference{Name: "cloudprovider", Namespace: namespace.Name},
+			},
+		}
+	}
+
+	// writes returns the operations called on the metal client which change metal resources.
+	writes := func() []string {
+		var result []string
+		for _, call := range metalClient.Calls {
+			if !strings.HasSuffix(call, "Find") && !strings.HasSuffix(call, "List") && !strings.HasSuffix(call, "Get") {
+				result = append(result, call)
+			}
+		}
+		return result
+	}
+
+	decodeStatus := func(raw *runtime.RawExtension) *metalv1alpha1.InfrastructureStatus {
+		Expect(raw).NotTo(BeNil())
+		status := &metalv1alpha1.InfrastructureStatus{}
+		_, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(raw.Raw, nil, status)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	It("should persist the state on migration and adopt the metal resources on restore", func() {
+		By("reconciling the infrastructure on the source seed")
+		source := newInfrastructure()
+		Expect(c.Create(ctx, source)).To(Succeed())
+		cidr := nodeCIDR
+		source.Status.NodesCIDR = &cidr
+		Expect(c.Status().Update(ctx, source)).To(Succeed())
+
+		sourceActuator := newActuator()
+		Expect(sourceActuator.Reconcile(ctx, source, cluster)).To(Succeed())
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, source.Name), source)).To(Succeed())
+		Expect(source.Status.ProviderStatus).NotTo(BeNil())
+		Expect(source.Status.State).To(BeNil())
+
+		By("migrating the infrastructure away from the source seed")
+		source.Annotations = map[string]string{v1beta1constants.GardenerOperation: v1beta1constants.GardenerOperationMigrate}
+		Expect(c.Update(ctx, source)).To(Succeed())
+
+		Expect(sourceActuator.Reconcile(ctx, source, cluster)).To(Succeed())
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, source.Name), source)).To(Succeed())
+
+		state := decodeStatus(source.Status.State)
+		Expect(state.Firewalls).To(HaveLen(1))
+		Expect(state.Firewalls[0].MachineID).To(Equal("metal:///" + partition + "/" + firewallID))
+		Expect(state.NodeNetworkPrefixes).To(ConsistOf(nodeCIDR))
+
+		Expect(sourceActuator.Delete(ctx, source, cluster)).To(Succeed())
+		Expect(c.Delete(ctx, source)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+
+		migrated := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, kutil.Key(v1beta1constants.GardenNamespace, "metal-migrated-clusters"), migrated)).To(Succeed())
+		Expect(migrated.Data).To(HaveKey(clusterID))
+
+		By("restoring the infrastructure on the destination seed from its state only")
+		cluster.Seed = &gardencorev1beta1.Seed{ObjectMeta: metav1.ObjectMeta{Name: targetSeed}}
+		destination := newInfrastructure()
+		Expect(c.Create(ctx, destination)).To(Succeed())
+		destination.Status.State = &runtime.RawExtension{Raw: source.Status.State.Raw}
+		Expect(c.Status().Update(ctx, destination)).To(Succeed())
+
+		Expect(newActuator().Reconcile(ctx, destination, cluster)).To(Succeed())
+		Expect(writes()).To(BeEmpty())
+
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, destination.Name), destination)).To(Succeed())
+		Expect(destination.Status.NodesCIDR).To(PointTo(Equal(nodeCIDR)))
+
+		status := decodeStatus(destination.Status.ProviderStatus)
+		Expect(status.Firewalls).To(HaveLen(1))
+		Expect(status.Firewalls[0].MachineID).To(Equal("metal:///" + partition + "/" + firewallID))
+		Expect(status.Firewalls[0].Phase).To(Equal(metalv1alpha1.FirewallPhaseRunning))
+
+		By("keeping the firewall marked for the source seed")
+		Expect(metalClient.Firewalls()).To(HaveLen(1))
+		Expect(*metalClient.Firewalls()[0].ID).To(Equal(firewallID))
+		Expect(metalClient.Firewalls()[0].Tags).To(ContainElement(metaltags.SeedName(sourceSeed)))
+	})
+})
@@ -0,0 +1,225 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+
+	extensionscontroller "github.com/gardener/gardener-extensions/pkg/controller"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/controller/worker"
+	"github.com/metal-stack/gardener-extension-provider-metal/pkg/metal"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// migrationActuator is the worker actuator of the metal provider, it migrates and restores the machine objects.
+type migrationActuator interface {
+	Migrate(context.Context, *extensionsv1alpha1.Worker) error
+	Restore(context.Context, *extensionsv1alpha1.Worker) error
+	Delete(context.Context, *extensionsv1alpha1.Worker, *extensionscontroller.Cluster) error
+}
+
+var _ = Describe("Migration", func() {
+	const (
+		finalizer   = "machine.sapcloud.io/machine-controller-manager"
+		machineName = "shoot--foo--bar-worker-a-z1-abcde-12345"
+		nodeName    = "node-1"
+	)
+
+	var (
+		ctx = context.TODO()
+
+		scheme    *runtime.Scheme
+		c         client.Client
+		namespace *corev1.Namespace
+		labels    = map[string]string{"name": "shoot--foo--bar-worker-a-z1"}
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(machinev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		var err error
+		c, err = client.New(restConfig, client.Options{Scheme: scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "shoot--foo--"}}
+		Expect(c.Create(ctx, namespace)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(c.Delete(ctx, namespace)).To(Succeed())
+	})
+
+	newActuator := func() migrationActuator {
+		a := worker.NewActuator(record.NewFakeRecorder(100), nil)
+		_, err := inject.ClientInto(c, a)
+		Expect(err).NotTo(HaveOccurred())
+		return a.(migrationActuator)
+	}
+
+	newWorker := func() *extensionsv1alpha1.Worker {
+		return &extensionsv1alpha1.Worker{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: namespace.Name},
+			Spec: extensionsv1alpha1.WorkerSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: metal.Type},
+				Region:      "region-a",
+				SecretRef:   corev1.SecretReference{Name: "cloudprovider", Namespace: namespace.Name},
+				Pools: []extensionsv1alpha1.WorkerPool{
+					{
+						Name:           "worker-a",
+						MachineType:    "c1-xlarge-x86",
+						Minimum:        1,
+						Maximum:        1,
+						MaxSurge:       intstr.FromInt(1),
+						MaxUnavailable: intstr.FromInt(0),
+						UserData:       []byte("user-data"),
+					},
+				},
+			},
+		}
+	}
+
+	It("should persist the machines on migration and restore them together with their status", func() {
+		By("creating the machine objects on the source seed")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "shoot--foo--bar-worker-a-z1-3f2a1",
+				Namespace:  namespace.Name,
+				Labels:     map[string]string{"garden.sapcloud.io/purpose": "machineclass"},
+				Finalizers: []string{finalizer},
+			},
+		}
+		Expect(c.Create(ctx, secret)).To(Succeed())
+
+		machineClass := &machinev1alpha1.MetalMachineClass{
+			ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: namespace.Name, Finalizers: []string{finalizer}},
+			Spec: machinev1alpha1.MetalMachineClassSpec{
+				Partition: "partition-a",
+				Size:      "c1-xlarge-x86",
+				Image:     "ubuntu-19.04",
+				SecretRef: &corev1.SecretReference{Name: secret.Name, Namespace: namespace.Name},
+			},
+		}
+		Expect(c.Create(ctx, machineClass)).To(Succeed())
+
+		machineSet := &machinev1alpha1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar-worker-a-z1-abcde", Namespace: namespace.Name, Labels: labels, Finalizers: []string{finalizer}},
+			Spec: machinev1alpha1.MachineSetSpec{
+				Replicas: 1,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: machinev1alpha1.MachineTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: machinev1alpha1.MachineSpec{
+						Class: machinev1alpha1.ClassSpec{Kind: "MetalMachineClass", Name: machineClass.Name},
+					},
+				},
+			},
+		}
+		Expect(c.Create(ctx, machineSet)).To(Succeed())
+
+		machine := &machinev1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: machineName, Namespace: namespace.Name, Labels: labels, Finalizers: []string{finalizer}},
+			Spec: machinev1alpha1.MachineSpec{
+				Class:      machinev1alpha1.ClassSpec{Kind: "MetalMachineClass", Name: machineClass.Name},
+				ProviderID: "metal:///partition-a/machine-1",
+			},
+		}
+		Expect(c.Create(ctx, machine)).To(Succeed())
+		machine.Status = machinev1alpha1.MachineStatus{
+			Node:          nodeName,
+			CurrentStatus: machinev1alpha1.CurrentStatus{Phase: machinev1alpha1.MachineRunning},
+		}
+		Expect(c.Status().Update(ctx, machine)).To(Succeed())
+
+		replicas := int32(1)
+		machineControllerManager := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: metal.MachineControllerManagerName, Namespace: namespace.Name},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": metal.MachineControllerManagerName}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": metal.MachineControllerManagerName}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "machine-controller-manager", Image: "machine-controller-manager"}}},
+				},
+			},
+		}
+		Expect(c.Create(ctx, machineControllerManager)).To(Succeed())
+
+		source := newWorker()
+		source.Annotations = map[string]string{v1beta1constants.GardenerOperation: v1beta1constants.GardenerOperationMigrate}
+		Expect(c.Create(ctx, source)).To(Succeed())
+
+		By("migrating the worker away from the source seed")
+		sourceActuator := newActuator()
+		Expect(sourceActuator.Migrate(ctx, source)).To(Succeed())
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, source.Name), source)).To(Succeed())
+		Expect(source.Status.State).NotTo(BeNil())
+
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, metal.MachineControllerManagerName), machineControllerManager)).To(Succeed())
+		Expect(machineControllerManager.Spec.Replicas).To(PointTo(BeEquivalentTo(0)))
+
+		state := &struct {
+			MachineSets []machinev1alpha1.MachineSet `json:"machineSets"`
+			Machines    []machinev1alpha1.Machine    `json:"machines"`
+		}{}
+		Expect(json.Unmarshal(source.Status.State.Raw, state)).To(Succeed())
+		Expect(state.MachineSets).To(HaveLen(1))
+		Expect(state.Machines).To(HaveLen(1))
+
+		By("deleting the worker on the source seed without deleting the machines")
+		Expect(sourceActuator.Delete(ctx, source, nil)).To(Succeed())
+		for _, obj := range []runtime.Object{secret, machineClass, machineSet, machine} {
+			key, err := client.ObjectKeyFromObject(obj)
+			Expect(err).NotTo(HaveOccurred())
+			accessor, err := meta.Accessor(obj)
+			Expect(err).NotTo(HaveOccurred())
+			accessor.SetFinalizers(nil)
+			Expect(c.Get(ctx, key, obj)).To(Succeed())
+			Expect(accessor.GetFinalizers()).NotTo(ContainElement(finalizer))
+
+			Expect(c.Delete(ctx, obj)).To(Succeed())
+			Expect(apierrors.IsNotFound(c.Get(ctx, key, obj))).To(BeTrue())
+		}
+		Expect(c.Delete(ctx, source)).To(Succeed())
+
+		By("restoring the worker on the destination seed from its state only")
+		destination := newWorker()
+		Expect(c.Create(ctx, destination)).To(Succeed())
+		destination.Status.State = &runtime.RawExtension{Raw: source.Status.State.Raw}
+		Expect(c.Status().Update(ctx, destination)).To(Succeed())
+
+		Expect(newActuator().Restore(ctx, destination)).To(Succeed())
+
+		restoredMachineSet := &machinev1alpha1.MachineSet{}
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, machineSet.Name), restoredMachineSet)).To(Succeed())
+		Expect(restoredMachineSet.Spec.Replicas).To(Equal(int32(1)))
+		Expect(restoredMachineSet.Labels).To(Equal(labels))
+
+		restoredMachine := &machinev1alpha1.Machine{}
+		Expect(c.Get(ctx, kutil.Key(namespace.Name, machineName), restoredMachine)).To(Succeed())
+		Expect(restoredMachine.Spec.ProviderID).To(Equal("metal:///partition-a/machine-1"))
+		Expect(restoredMachine.Status.Node).To(Equal(nodeName))
+		Expect(restoredMachine.Status.CurrentStatus.Phase).To(Equal(machinev1alpha1.MachineRunning))
+	})
+})